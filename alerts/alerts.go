@@ -0,0 +1,187 @@
+// Package alerts wertet monitoring.Metrics gegen eine Menge konfigurierbarer
+// Rules aus (Error-Rate, Latenz, Detektor-Fehlerquote, Verdict-Drift,
+// Analyse-Qualität) und verteilt ausgelöste Alerts an ein oder mehrere Sinks
+// (Webhook, Alertmanager, stderr) - bisher flippte healthHandler nur ein
+// einzelnes Bool um, wenn die Gesamt-Error-Rate eine feste Schwelle
+// überschritt.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/BramseDev/imageAnalyzer/monitoring"
+)
+
+// Severity klassifiziert, wie dringend ein Alert ist - geht unverändert als
+// Label an jeden Sink durch.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Alert ist ein einzelnes, von einer Rule ausgelöstes Ereignis.
+type Alert struct {
+	Rule     string
+	Severity Severity
+	Message  string
+	Value    float64
+	FiredAt  time.Time
+}
+
+// Rule prüft einen Aspekt von metrics und meldet, ob er gerade eine
+// Alarmbedingung erfüllt. Implementierungen liegen in rules.go.
+type Rule interface {
+	// Name identifiziert die Rule eindeutig - dient als Dedup-/Cooldown-Key
+	// und als Alert.Rule.
+	Name() string
+	Severity() Severity
+	// Cooldown ist die Mindestzeit zwischen zwei Alerts derselben Rule,
+	// solange die Bedingung durchgehend erfüllt bleibt.
+	Cooldown() time.Duration
+	// Evaluate liefert triggered=true plus einer für Menschen lesbaren
+	// message und dem zugrundeliegenden value, falls die Bedingung gerade
+	// erfüllt ist.
+	Evaluate(metrics *monitoring.Metrics) (triggered bool, message string, value float64)
+}
+
+// Sink nimmt einen ausgelösten Alert entgegen und liefert ihn an ein externes
+// System aus. Implementierungen liegen in sinks.go.
+type Sink interface {
+	Send(alert Alert) error
+}
+
+// ruleState hält den Dedup-/Cooldown-Zustand einer einzelnen Rule zwischen
+// zwei Manager-Ticks.
+type ruleState struct {
+	active    bool
+	lastFired time.Time
+}
+
+// Manager sampelt metrics in regelmäßigen Abständen, wertet alle
+// registrierten Rules aus und verteilt neu ausgelöste Alerts an sinks - nach
+// demselben Hintergrund-Goroutine/Ticker-Muster wie
+// Metrics.StartSystemSampler und Metrics.RegisterReporter.
+type Manager struct {
+	metrics *monitoring.Metrics
+	sinks   []Sink
+
+	mu     sync.RWMutex
+	rules  []Rule
+	states map[string]*ruleState
+	active map[string]Alert
+}
+
+// NewManager erstellt einen Manager, der metrics gegen rules prüft und
+// ausgelöste Alerts an sinks schickt. sinks darf leer sein (Alerts werden
+// dann nur im aktiven Set unter Active() sichtbar).
+func NewManager(metrics *monitoring.Metrics, sinks []Sink, rules ...Rule) *Manager {
+	return &Manager{
+		metrics: metrics,
+		sinks:   sinks,
+		rules:   rules,
+		states:  make(map[string]*ruleState),
+		active:  make(map[string]Alert),
+	}
+}
+
+// AddRule registriert eine weitere Rule, die ab dem nächsten Tick mit
+// ausgewertet wird.
+func (mgr *Manager) AddRule(rule Rule) {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	mgr.rules = append(mgr.rules, rule)
+}
+
+// Start startet die Hintergrund-Goroutine, die alle interval sämtliche
+// registrierten Rules auswertet, bis ctx storniert wird - analog zu
+// Metrics.StartSystemSampler.
+func (mgr *Manager) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				mgr.tick()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (mgr *Manager) tick() {
+	mgr.mu.RLock()
+	rules := make([]Rule, len(mgr.rules))
+	copy(rules, mgr.rules)
+	mgr.mu.RUnlock()
+
+	for _, rule := range rules {
+		triggered, message, value := rule.Evaluate(mgr.metrics)
+
+		mgr.mu.Lock()
+		state, exists := mgr.states[rule.Name()]
+		if !exists {
+			state = &ruleState{}
+			mgr.states[rule.Name()] = state
+		}
+
+		if !triggered {
+			state.active = false
+			delete(mgr.active, rule.Name())
+			mgr.mu.Unlock()
+			continue
+		}
+
+		fire := !state.active || time.Since(state.lastFired) >= rule.Cooldown()
+		state.active = true
+		if !fire {
+			mgr.mu.Unlock()
+			continue
+		}
+		state.lastFired = time.Now()
+
+		alert := Alert{
+			Rule:     rule.Name(),
+			Severity: rule.Severity(),
+			Message:  message,
+			Value:    value,
+			FiredAt:  state.lastFired,
+		}
+		mgr.active[rule.Name()] = alert
+		mgr.mu.Unlock()
+
+		mgr.dispatch(alert)
+	}
+}
+
+// dispatch schickt alert an alle Sinks. Ein fehlschlagender Sink wird nur
+// geloggt, nicht weitergereicht - analog zu RegisterReporter soll ein
+// einzelner kaputter Sink die übrigen nicht blockieren.
+func (mgr *Manager) dispatch(alert Alert) {
+	for _, sink := range mgr.sinks {
+		if err := sink.Send(alert); err != nil {
+			fmt.Printf("WARNING: alert sink failed for rule %q: %v\n", alert.Rule, err)
+		}
+	}
+}
+
+// Active liefert die aktuell ausgelösten Alerts (eine Kopie, nach Rule-Name
+// sortiert wäre Overkill für diese Größenordnung - Reihenfolge ist nicht
+// garantiert).
+func (mgr *Manager) Active() []Alert {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+
+	active := make([]Alert, 0, len(mgr.active))
+	for _, alert := range mgr.active {
+		active = append(active, alert)
+	}
+	return active
+}
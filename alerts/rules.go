@@ -0,0 +1,149 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/BramseDev/imageAnalyzer/monitoring"
+)
+
+// ErrorRateRule löst aus, wenn der Anteil fehlgeschlagener Requests für
+// AnalysisType über Threshold liegt - ersetzt das hartkodierte
+// "overall_error_rate > 0.1" in healthHandler.
+type ErrorRateRule struct {
+	AnalysisType string
+	Threshold    float64
+	Sev          Severity
+	CooldownFor  time.Duration
+}
+
+func (r ErrorRateRule) Name() string       { return fmt.Sprintf("error_rate:%s", r.AnalysisType) }
+func (r ErrorRateRule) Severity() Severity { return r.Sev }
+func (r ErrorRateRule) Cooldown() time.Duration {
+	return r.CooldownFor
+}
+
+func (r ErrorRateRule) Evaluate(metrics *monitoring.Metrics) (bool, string, float64) {
+	rate := metrics.GetErrorRate(r.AnalysisType)
+	if rate <= r.Threshold {
+		return false, "", rate
+	}
+	return true, fmt.Sprintf("%s error rate %.1f%% exceeds threshold %.1f%%", r.AnalysisType, rate*100, r.Threshold*100), rate
+}
+
+// LatencyRule löst aus, wenn das Percentile (z.B. "p95") der AnalysisType-
+// Latenz über Threshold liegt.
+type LatencyRule struct {
+	AnalysisType string
+	Percentile   string
+	Threshold    time.Duration
+	Sev          Severity
+	CooldownFor  time.Duration
+}
+
+func (r LatencyRule) Name() string {
+	return fmt.Sprintf("latency:%s:%s", r.AnalysisType, r.Percentile)
+}
+func (r LatencyRule) Severity() Severity      { return r.Sev }
+func (r LatencyRule) Cooldown() time.Duration { return r.CooldownFor }
+
+func (r LatencyRule) Evaluate(metrics *monitoring.Metrics) (bool, string, float64) {
+	percentiles := metrics.GetLatencyPercentiles(r.AnalysisType)
+	d, ok := percentiles[r.Percentile]
+	if !ok || d <= r.Threshold {
+		return false, "", float64(d)
+	}
+	return true, fmt.Sprintf("%s %s latency %s exceeds threshold %s", r.AnalysisType, r.Percentile, d, r.Threshold), float64(d)
+}
+
+// DetectorFailureRateRule löst aus, wenn der Anteil fehlgeschlagener
+// Durchläufe der Python-Skript-Stage Detector über Threshold liegt - erkennt
+// einen einzelnen kaputten Detektor, bevor genug Stages mitreißen, um
+// ErrorRateRule auf Pipeline-Ebene auszulösen.
+type DetectorFailureRateRule struct {
+	Detector    string
+	Threshold   float64
+	Sev         Severity
+	CooldownFor time.Duration
+}
+
+func (r DetectorFailureRateRule) Name() string {
+	return fmt.Sprintf("detector_failure_rate:%s", r.Detector)
+}
+func (r DetectorFailureRateRule) Severity() Severity { return r.Sev }
+func (r DetectorFailureRateRule) Cooldown() time.Duration {
+	return r.CooldownFor
+}
+
+func (r DetectorFailureRateRule) Evaluate(metrics *monitoring.Metrics) (bool, string, float64) {
+	rate := metrics.GetDetectorFailureRate(r.Detector)
+	if rate <= r.Threshold {
+		return false, "", rate
+	}
+	return true, fmt.Sprintf("detector %q failure rate %.1f%% exceeds threshold %.1f%%", r.Detector, rate*100, r.Threshold*100), rate
+}
+
+// LowQualityStreakRule löst aus, wenn MinStreak aufeinanderfolgende Analysen
+// eine analysis_quality unter QualityThreshold hatten - ein Hinweis auf
+// mehrere gleichzeitig ausgefallene Detektoren, der in der einzelnen
+// analysis_quality eines Requests untergeht.
+type LowQualityStreakRule struct {
+	QualityThreshold float64
+	MinStreak        int
+	Sev              Severity
+	CooldownFor      time.Duration
+}
+
+func (r LowQualityStreakRule) Name() string       { return "low_quality_streak" }
+func (r LowQualityStreakRule) Severity() Severity { return r.Sev }
+func (r LowQualityStreakRule) Cooldown() time.Duration {
+	return r.CooldownFor
+}
+
+func (r LowQualityStreakRule) Evaluate(metrics *monitoring.Metrics) (bool, string, float64) {
+	streak := metrics.LowQualityStreak(r.QualityThreshold)
+	if streak < r.MinStreak {
+		return false, "", float64(streak)
+	}
+	return true, fmt.Sprintf("%d consecutive analyses below analysis_quality %.2f", streak, r.QualityThreshold), float64(streak)
+}
+
+// VerdictDriftRule löst aus, wenn sich die AI-Detection-Rate seit dem
+// letzten Tick um mehr als Threshold verschiebt - ein plötzlicher Sprung
+// deutet eher auf Modell-/Kalibrierungs-Drift oder einen kaputten Detektor
+// hin als auf eine echte Verschiebung in den eingereichten Bildern.
+// VerdictDriftRule ist zustandsbehaftet (baseline) und darf daher nicht für
+// mehrere Manager gleichzeitig verwendet werden.
+type VerdictDriftRule struct {
+	Threshold   float64
+	Sev         Severity
+	CooldownFor time.Duration
+
+	baseline    float64
+	initialized bool
+}
+
+func (r *VerdictDriftRule) Name() string       { return "verdict_drift" }
+func (r *VerdictDriftRule) Severity() Severity { return r.Sev }
+func (r *VerdictDriftRule) Cooldown() time.Duration {
+	return r.CooldownFor
+}
+
+func (r *VerdictDriftRule) Evaluate(metrics *monitoring.Metrics) (bool, string, float64) {
+	business := metrics.GetBusinessMetrics()
+	current, _ := business["ai_detection_rate"].(float64)
+
+	if !r.initialized {
+		r.baseline = current
+		r.initialized = true
+		return false, "", 0
+	}
+
+	shift := current - r.baseline
+	defer func() { r.baseline = current }()
+
+	if shift < -r.Threshold || shift > r.Threshold {
+		return true, fmt.Sprintf("AI detection rate shifted by %.1f%% to %.1f%% since last check", shift*100, current*100), shift
+	}
+	return false, "", shift
+}
@@ -0,0 +1,138 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// StderrSink schreibt jeden Alert als Textzeile auf stderr (über fmt.Printf,
+// wie der Rest des Pakets Warnungen loggt) - Default-Sink, falls kein
+// Webhook/Alertmanager konfiguriert ist.
+type StderrSink struct{}
+
+func (StderrSink) Send(alert Alert) error {
+	fmt.Printf("ALERT [%s] %s: %s (value=%.4f)\n", alert.Severity, alert.Rule, alert.Message, alert.Value)
+	return nil
+}
+
+// WebhookFormat wählt die Body-Form, die WebhookSink an URL schickt.
+type WebhookFormat string
+
+const (
+	// WebhookFormatSlack/WebhookFormatDiscord rendern den Alert als einfache
+	// Chat-Nachricht, kompatibel mit Slack- bzw. Discord-Incoming-Webhooks.
+	WebhookFormatSlack   WebhookFormat = "slack"
+	WebhookFormatDiscord WebhookFormat = "discord"
+	// WebhookFormatGeneric schickt den Alert als rohes JSON-Objekt - für
+	// eigene Webhook-Empfänger.
+	WebhookFormatGeneric WebhookFormat = "generic"
+)
+
+// WebhookSink POSTet jeden Alert als JSON an URL - Format bestimmt, ob der
+// Body eine Slack/Discord-kompatible Chat-Nachricht oder das rohe
+// Alert-Objekt ist.
+type WebhookSink struct {
+	URL    string
+	Format WebhookFormat
+	client *http.Client
+}
+
+// NewWebhookSink erstellt einen WebhookSink, der Alerts per POST an url
+// schickt, gerendert gemäß format.
+func NewWebhookSink(url string, format WebhookFormat) *WebhookSink {
+	return &WebhookSink{
+		URL:    url,
+		Format: format,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (s *WebhookSink) Send(alert Alert) error {
+	body, err := s.render(alert)
+	if err != nil {
+		return fmt.Errorf("render webhook body: %w", err)
+	}
+
+	resp, err := s.client.Post(s.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *WebhookSink) render(alert Alert) ([]byte, error) {
+	text := fmt.Sprintf("[%s] %s: %s", alert.Severity, alert.Rule, alert.Message)
+
+	switch s.Format {
+	case WebhookFormatSlack:
+		return json.Marshal(map[string]string{"text": text})
+	case WebhookFormatDiscord:
+		return json.Marshal(map[string]string{"content": text})
+	default:
+		return json.Marshal(alert)
+	}
+}
+
+// AlertmanagerSink POSTet jeden Alert im Alertmanager-v2-API-Format
+// (https://github.com/prometheus/alertmanager/blob/main/api/v2/openapi.yaml,
+// POST /api/v2/alerts) an URL - Alertmanager übernimmt damit Routing,
+// Silencing und Grouping, das dieses Paket bewusst nicht neu erfindet.
+type AlertmanagerSink struct {
+	URL    string
+	client *http.Client
+}
+
+// NewAlertmanagerSink erstellt einen AlertmanagerSink für die Alertmanager-
+// Instanz unter baseURL (z.B. "http://alertmanager:9093").
+func NewAlertmanagerSink(baseURL string) *AlertmanagerSink {
+	return &AlertmanagerSink{
+		URL:    baseURL + "/api/v2/alerts",
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// alertmanagerAlert spiegelt die von der Alertmanager-v2-API erwarteten
+// Pflichtfelder - kein Anspruch auf Vollständigkeit (EndsAt/GeneratorURL
+// bleiben leer, da dieses Paket Alerts nicht explizit auflöst).
+type alertmanagerAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+}
+
+func (s *AlertmanagerSink) Send(alert Alert) error {
+	payload := []alertmanagerAlert{{
+		Labels: map[string]string{
+			"alertname": alert.Rule,
+			"severity":  string(alert.Severity),
+		},
+		Annotations: map[string]string{
+			"summary": alert.Message,
+		},
+		StartsAt: alert.FiredAt,
+	}}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal alertmanager payload: %w", err)
+	}
+
+	resp, err := s.client.Post(s.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("post alertmanager alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alertmanager returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -1,35 +1,126 @@
 package cache
 
 import (
+	"container/list"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"sync"
 	"time"
 )
 
-type AnalysisCache struct {
-	mu    sync.RWMutex
-	items map[string]CacheItem
-}
+const (
+	// DefaultMaxEntries begrenzt, wie viele Einträge im In-Memory-Tier gehalten werden.
+	DefaultMaxEntries = 500
+	// DefaultMaxBytes begrenzt die (JSON-approximierte) Gesamtgröße des In-Memory-Tiers.
+	DefaultMaxBytes = 256 * 1024 * 1024 // 256MB
+)
 
+// CacheItem ist der Wert, der pro Key im Memory-Tier und (serialisiert) auf
+// der Platte gehalten wird.
 type CacheItem struct {
 	Data      interface{}
 	ExpiresAt time.Time
 	CreatedAt time.Time
 }
 
+// diskEnvelope ist das on-disk Format - ExpiresAt steht im "Header", damit
+// abgelaufene Spill-Dateien beim Laden ohne Deserialisierung von Data
+// erkannt werden können.
+type diskEnvelope struct {
+	ExpiresAt time.Time       `json:"expires_at"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Backend ist die Schnittstelle, die AnalysisCache implementiert - Grundlage
+// für austauschbare Speicher hinter der Pipeline (siehe
+// pkg/analyzer/pipeline.SetGlobalCacheBackend), etwa ein RedisBackend für
+// Operator, die den Cache über mehrere Instanzen teilen wollen.
+type Backend interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, data interface{}, ttl time.Duration)
+	// Delete entfernt einen einzelnen Eintrag - true, wenn er existierte.
+	Delete(key string) bool
+	// Keys listet alle aktuell im Memory-Tier gehaltenen Keys auf, fürs
+	// /cache Admin-Endpoint.
+	Keys() []string
+	Stats() Stats
+}
+
+// Stats fasst die Wirksamkeit des Caches zusammen, fürs Dashboard.
+type Stats struct {
+	Hits        int64 `json:"hits"`
+	Misses      int64 `json:"misses"`
+	Evictions   int64 `json:"evictions"`
+	DiskHits    int64 `json:"disk_hits"`
+	Entries     int   `json:"entries"`
+	ApproxBytes int64 `json:"approx_bytes"`
+	MaxEntries  int   `json:"max_entries"`
+	MaxBytes    int64 `json:"max_bytes"`
+}
+
+type cacheEntry struct {
+	key   string
+	item  CacheItem
+	bytes int64
+}
+
+// AnalysisCache ist ein zweistufiger Cache: ein bounded LRU im Speicher,
+// abgesichert durch einen On-Disk-Spill unter diskDir, beide indiziert über
+// den SHA-256-Inhalts-Hash aus GetFileHash.
+type AnalysisCache struct {
+	mu sync.RWMutex
+
+	items      map[string]*list.Element
+	evictList  *list.List
+	maxEntries int
+	maxBytes   int64
+	curBytes   int64
+	diskDir    string
+
+	hits      int64
+	misses    int64
+	diskHits  int64
+	evictions int64
+
+	stopCleanup chan struct{}
+}
+
+// NewAnalysisCache erstellt einen Cache mit Standardgrößen und Spill unter
+// dem System-Temp-Verzeichnis.
 func NewAnalysisCache() *AnalysisCache {
-	cache := &AnalysisCache{
-		items: make(map[string]CacheItem),
+	return NewAnalysisCacheWithConfig(DefaultMaxEntries, DefaultMaxBytes, filepath.Join(os.TempDir(), "analyzer-cache"))
+}
+
+// NewAnalysisCacheWithConfig erstellt einen Cache mit expliziten Limits und
+// Spill-Verzeichnis.
+func NewAnalysisCacheWithConfig(maxEntries int, maxBytes int64, diskDir string) *AnalysisCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+
+	os.MkdirAll(diskDir, 0755)
+
+	c := &AnalysisCache{
+		items:       make(map[string]*list.Element),
+		evictList:   list.New(),
+		maxEntries:  maxEntries,
+		maxBytes:    maxBytes,
+		diskDir:     diskDir,
+		stopCleanup: make(chan struct{}),
 	}
 
-	// Temporarily disable cleanup for testing
-	// go cache.cleanup()
+	go c.cleanup()
 
-	return cache
+	return c
 }
 
 func (c *AnalysisCache) GetFileHash(filePath string) (string, error) {
@@ -47,101 +138,273 @@ func (c *AnalysisCache) GetFileHash(filePath string) (string, error) {
 	return hex.EncodeToString(hasher.Sum(nil)), nil
 }
 
+// Get prüft zuerst das Memory-Tier, dann die Platte.
 func (c *AnalysisCache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		if time.Now().After(entry.item.ExpiresAt) {
+			c.removeElementLocked(elem)
+			c.misses++
+			c.mu.Unlock()
+			return nil, false
+		}
+		c.evictList.MoveToFront(elem)
+		c.hits++
+		c.mu.Unlock()
+		return entry.item.Data, true
+	}
+	c.mu.Unlock()
+
+	// Memory-Miss - auf der Platte nachsehen.
+	if data, item, ok := c.loadFromDisk(key); ok {
+		c.mu.Lock()
+		c.diskHits++
+		c.hits++
+		c.mu.Unlock()
+		c.promoteToMemory(key, item)
+		return data, true
+	}
+
+	c.mu.Lock()
+	c.misses++
+	c.mu.Unlock()
+	return nil, false
+}
+
+// Set schreibt ins Memory-Tier (mit Eviction) und persistiert asynchron auf die Platte.
+func (c *AnalysisCache) Set(key string, data interface{}, ttl time.Duration) {
+	item := CacheItem{
+		Data:      data,
+		ExpiresAt: time.Now().Add(ttl),
+		CreatedAt: time.Now(),
+	}
+
+	size := approxSize(data)
+
+	c.mu.Lock()
+	if elem, ok := c.items[key]; ok {
+		old := elem.Value.(*cacheEntry)
+		c.curBytes -= old.bytes
+		old.item = item
+		old.bytes = size
+		c.curBytes += size
+		c.evictList.MoveToFront(elem)
+	} else {
+		entry := &cacheEntry{key: key, item: item, bytes: size}
+		elem := c.evictList.PushFront(entry)
+		c.items[key] = elem
+		c.curBytes += size
+	}
+
+	c.evictLocked()
+	c.mu.Unlock()
+
+	go c.persistToDisk(key, item)
+}
+
+// Stats liefert Treffer/Verfehlungen/Evictions für Monitoring-Dashboards.
+func (c *AnalysisCache) Stats() Stats {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	now := time.Now()
-	item, exists := c.items[key]
-
-	// DEBUG Logging - Enhanced
-	fmt.Printf("CACHE DEBUG: Get key=%s, exists=%t, total_items=%d",
-		key[:16], exists, len(c.items))
-	if exists {
-		fmt.Printf(", expired=%t, now=%s, expires_at=%s\n",
-			now.After(item.ExpiresAt),
-			now.Format("15:04:05"),
-			item.ExpiresAt.Format("15:04:05"))
-	} else {
-		fmt.Printf("\n")
-		// List all keys for debugging
-		if len(c.items) > 0 {
-			fmt.Printf("CACHE DEBUG: Available keys: ")
-			for k := range c.items {
-				fmt.Printf("%s ", k[:16])
-			}
-			fmt.Printf("\n")
-		}
+	return Stats{
+		Hits:        c.hits,
+		Misses:      c.misses,
+		Evictions:   c.evictions,
+		DiskHits:    c.diskHits,
+		Entries:     len(c.items),
+		ApproxBytes: c.curBytes,
+		MaxEntries:  c.maxEntries,
+		MaxBytes:    c.maxBytes,
 	}
+}
+
+// Close stoppt die Cleanup-Goroutine. Nützlich in Tests oder beim Ersetzen
+// des globalen Caches.
+func (c *AnalysisCache) Close() {
+	close(c.stopCleanup)
+}
 
-	if !exists || now.After(item.ExpiresAt) {
-		return nil, false
+// Delete entfernt key aus Memory-Tier und Platten-Spill. Liefert true, wenn
+// der Key im Memory-Tier existierte.
+func (c *AnalysisCache) Delete(key string) bool {
+	c.mu.Lock()
+	elem, ok := c.items[key]
+	if ok {
+		c.removeElementLocked(elem)
 	}
+	c.mu.Unlock()
 
-	return item.Data, true
+	os.Remove(c.diskPath(key))
+	return ok
 }
 
-func (c *AnalysisCache) Set(key string, data interface{}, ttl time.Duration) {
+// Keys listet die Keys im Memory-Tier auf - der Platten-Spill wird hier
+// bewusst nicht gescannt, da das /cache Admin-Endpoint nur eine Übersicht
+// über das aktive Working-Set braucht.
+func (c *AnalysisCache) Keys() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	keys := make([]string, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (c *AnalysisCache) evictLocked() {
+	for (c.maxEntries > 0 && len(c.items) > c.maxEntries) || (c.maxBytes > 0 && c.curBytes > c.maxBytes) {
+		oldest := c.evictList.Back()
+		if oldest == nil {
+			break
+		}
+		c.removeElementLocked(oldest)
+		c.evictions++
+	}
+}
+
+func (c *AnalysisCache) removeElementLocked(elem *list.Element) {
+	entry := elem.Value.(*cacheEntry)
+	delete(c.items, entry.key)
+	c.evictList.Remove(elem)
+	c.curBytes -= entry.bytes
+}
+
+func (c *AnalysisCache) promoteToMemory(key string, item CacheItem) {
+	size := approxSize(item.Data)
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	expiresAt := time.Now().Add(ttl)
+	entry := &cacheEntry{key: key, item: item, bytes: size}
+	elem := c.evictList.PushFront(entry)
+	c.items[key] = elem
+	c.curBytes += size
+	c.evictLocked()
+}
 
-	c.items[key] = CacheItem{
-		Data:      data,
-		ExpiresAt: expiresAt,
-		CreatedAt: time.Now(),
+func (c *AnalysisCache) diskPath(key string) string {
+	return filepath.Join(c.diskDir, key+".json")
+}
+
+func (c *AnalysisCache) persistToDisk(key string, item CacheItem) {
+	raw, err := json.Marshal(item.Data)
+	if err != nil {
+		fmt.Printf("CACHE DEBUG: failed to marshal %s for disk spill: %v\n", key[:min16(key)], err)
+		return
 	}
 
-	// Enhanced DEBUG logging
-	fmt.Printf("CACHE DEBUG: Set key=%s, expires_at=%s, ttl=%s, total_items=%d\n",
-		key[:16], expiresAt.Format("15:04:05"), ttl, len(c.items))
+	envelope := diskEnvelope{
+		ExpiresAt: item.ExpiresAt,
+		CreatedAt: item.CreatedAt,
+		Data:      raw,
+	}
+
+	out, err := json.Marshal(envelope)
+	if err != nil {
+		return
+	}
 
-	// List all keys after set
-	fmt.Printf("CACHE DEBUG: All keys after set: ")
-	for k := range c.items {
-		fmt.Printf("%s ", k[:16])
+	tmp := c.diskPath(key) + ".tmp"
+	if err := os.WriteFile(tmp, out, 0644); err != nil {
+		return
 	}
-	fmt.Printf("\n")
+	os.Rename(tmp, c.diskPath(key))
 }
 
+func (c *AnalysisCache) loadFromDisk(key string) (interface{}, CacheItem, bool) {
+	raw, err := os.ReadFile(c.diskPath(key))
+	if err != nil {
+		return nil, CacheItem{}, false
+	}
+
+	var envelope diskEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, CacheItem{}, false
+	}
+
+	if time.Now().After(envelope.ExpiresAt) {
+		os.Remove(c.diskPath(key))
+		return nil, CacheItem{}, false
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(envelope.Data, &data); err != nil {
+		return nil, CacheItem{}, false
+	}
+
+	item := CacheItem{Data: data, ExpiresAt: envelope.ExpiresAt, CreatedAt: envelope.CreatedAt}
+	return data, item, true
+}
+
+// cleanup räumt abgelaufene Einträge aus beiden Tiers.
 func (c *AnalysisCache) cleanup() {
-	ticker := time.NewTicker(5 * time.Minute) // Clean every 5 minutes
+	ticker := time.NewTicker(5 * time.Minute)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			c.mu.Lock()
-			now := time.Now()
-			itemsRemoved := 0
-
-			for key, item := range c.items {
-				if now.After(item.ExpiresAt) {
-					delete(c.items, key)
-					itemsRemoved++
-				}
-			}
+			c.cleanupMemory()
+			c.cleanupDisk()
+		case <-c.stopCleanup:
+			return
+		}
+	}
+}
 
-			// Debug logging
-			fmt.Printf("CACHE CLEANUP: Removed %d expired items, %d items remaining\n",
-				itemsRemoved, len(c.items))
+func (c *AnalysisCache) cleanupMemory() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-			c.mu.Unlock()
+	now := time.Now()
+	var expired []*list.Element
+	for _, elem := range c.items {
+		if now.After(elem.Value.(*cacheEntry).item.ExpiresAt) {
+			expired = append(expired, elem)
 		}
 	}
+	for _, elem := range expired {
+		c.removeElementLocked(elem)
+	}
 }
 
-func (c *AnalysisCache) GetWithMetrics(key string, metrics interface{}) (interface{}, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+func (c *AnalysisCache) cleanupDisk() {
+	entries, err := os.ReadDir(c.diskDir)
+	if err != nil {
+		return
+	}
 
-	item, exists := c.items[key]
-	if !exists || time.Now().After(item.ExpiresAt) {
-		// Cache Miss - Metrics interface würde hier genutzt
-		return nil, false
+	now := time.Now()
+	for _, entry := range entries {
+		path := filepath.Join(c.diskDir, entry.Name())
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var envelope diskEnvelope
+		if err := json.Unmarshal(raw, &envelope); err != nil {
+			continue
+		}
+		if now.After(envelope.ExpiresAt) {
+			os.Remove(path)
+		}
 	}
+}
+
+func approxSize(data interface{}) int64 {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return 0
+	}
+	return int64(len(raw))
+}
 
-	// Cache Hit - Metrics interface würde hier genutzt
-	return item.Data, true
+func min16(key string) int {
+	if len(key) < 16 {
+		return len(key)
+	}
+	return 16
 }
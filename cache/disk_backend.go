@@ -0,0 +1,203 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DiskBackend ist ein rein plattenresidentes Backend: jeder Eintrag liegt als
+// eigene JSON-Datei unter dir, adressiert über den bereits als Cache-Key
+// verwendeten Inhalts-Hash (siehe cacheKeyForHash in pkg/analyzer/pipeline).
+// Anders als AnalysisCache hält DiskBackend keinen Memory-Tier - jeder Get
+// liest die Datei neu ein. Das macht es zum passenden Backend für einen
+// warmen Neustart des Analyzer-Service: Ergebnisse aus vor dem Neustart
+// gelaufenen Analysen bleiben unter dir liegen und werden beim ersten Get
+// danach wiederverwendet, ohne den Python/Rust-Runner erneut zu bemühen.
+// Ein Embedded-KV-Store wie Pebble/BadgerDB würde denselben Zweck erfüllen,
+// zieht aber ein go.mod/Vendoring nach sich, das dieses Repo (noch) nicht
+// führt (siehe RedisBackend) - ein Verzeichnis aus einzelnen JSON-Dateien
+// erreicht dieselbe Haltbarkeit ohne neue Abhängigkeit.
+type DiskBackend struct {
+	dir string
+
+	mu       sync.Mutex
+	keys     map[string]struct{}
+	curBytes int64
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+// NewDiskBackend öffnet (und erstellt bei Bedarf) dir als Speicherort und
+// liest den bereits vorhandenen Bestand ein, damit Entries/ApproxBytes in
+// Stats ab dem ersten Aufruf stimmen, auch wenn dir von einem vorherigen
+// Prozesslauf übernommen wurde.
+func NewDiskBackend(dir string) (*DiskBackend, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create disk cache dir %s: %w", dir, err)
+	}
+
+	b := &DiskBackend{dir: dir, keys: make(map[string]struct{})}
+	b.loadExisting()
+	return b, nil
+}
+
+func (b *DiskBackend) loadExisting() {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		key := strings.TrimSuffix(entry.Name(), ".json")
+		b.keys[key] = struct{}{}
+		if info, err := entry.Info(); err == nil {
+			b.curBytes += info.Size()
+		}
+	}
+}
+
+func (b *DiskBackend) path(key string) string {
+	return filepath.Join(b.dir, key+".json")
+}
+
+// Get liest und deserialisiert den diskEnvelope für key - ein abgelaufener
+// oder fehlender Eintrag zählt als Miss.
+func (b *DiskBackend) Get(key string) (interface{}, bool) {
+	raw, err := os.ReadFile(b.path(key))
+	if err != nil {
+		b.mu.Lock()
+		b.misses++
+		b.mu.Unlock()
+		return nil, false
+	}
+
+	var envelope diskEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		b.mu.Lock()
+		b.misses++
+		b.mu.Unlock()
+		return nil, false
+	}
+
+	if time.Now().After(envelope.ExpiresAt) {
+		b.Delete(key)
+		b.mu.Lock()
+		b.misses++
+		b.mu.Unlock()
+		return nil, false
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(envelope.Data, &data); err != nil {
+		b.mu.Lock()
+		b.misses++
+		b.mu.Unlock()
+		return nil, false
+	}
+
+	b.mu.Lock()
+	b.hits++
+	b.mu.Unlock()
+	return data, true
+}
+
+// Set serialisiert data als diskEnvelope und schreibt ihn atomar (tmp +
+// Rename) unter path(key) - analog zu AnalysisCache.persistToDisk, hier
+// synchron statt fire-and-forget, da DiskBackend keinen Memory-Tier hat, der
+// den Eintrag bis zum Abschluss des Schreibens sichtbar hält.
+func (b *DiskBackend) Set(key string, data interface{}, ttl time.Duration) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	envelope := diskEnvelope{
+		ExpiresAt: time.Now().Add(ttl),
+		CreatedAt: time.Now(),
+		Data:      raw,
+	}
+
+	out, err := json.Marshal(envelope)
+	if err != nil {
+		return
+	}
+
+	// prevSize ist die Größe einer bereits vorhandenen Datei unter diesem
+	// key - ohne sie vor dem Addieren von len(out) abzuziehen, würde jedes
+	// Overwrite (TTL-Ablauf + Re-Run, ?nocache=1 Write-back) curBytes über
+	// die tatsächliche Plattenbelegung hinaus aufblähen.
+	var prevSize int64
+	if info, statErr := os.Stat(b.path(key)); statErr == nil {
+		prevSize = info.Size()
+	}
+
+	tmp := b.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, out, 0644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, b.path(key)); err != nil {
+		return
+	}
+
+	b.mu.Lock()
+	if _, existed := b.keys[key]; !existed {
+		b.keys[key] = struct{}{}
+	}
+	b.curBytes += int64(len(out)) - prevSize
+	b.mu.Unlock()
+}
+
+// Delete entfernt die Datei für key. Liefert true, wenn sie existierte.
+func (b *DiskBackend) Delete(key string) bool {
+	info, statErr := os.Stat(b.path(key))
+	err := os.Remove(b.path(key))
+
+	b.mu.Lock()
+	_, existed := b.keys[key]
+	delete(b.keys, key)
+	if existed && statErr == nil {
+		b.curBytes -= info.Size()
+		b.evictions++
+	}
+	b.mu.Unlock()
+
+	return err == nil
+}
+
+// Keys listet alle bekannten Keys auf - fürs /cache Admin-Endpoint.
+func (b *DiskBackend) Keys() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	keys := make([]string, 0, len(b.keys))
+	for key := range b.keys {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Stats liefert Entries/ApproxBytes aus dem intern mitgeführten Bestand statt
+// eines Directory-Scans - MaxEntries/MaxBytes bleiben 0 (unbegrenzt), da
+// DiskBackend anders als AnalysisCache keine Eviction-Policy fährt.
+func (b *DiskBackend) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return Stats{
+		Hits:        b.hits,
+		Misses:      b.misses,
+		Evictions:   b.evictions,
+		Entries:     len(b.keys),
+		ApproxBytes: b.curBytes,
+	}
+}
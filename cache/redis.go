@@ -0,0 +1,237 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedisBackend ist ein Backend, das Einträge über eine minimale RESP-
+// Implementierung (GET/SET/DEL/KEYS) auf einem Redis-kompatiblen Server
+// hält - Grundlage, damit mehrere Analyzer-Instanzen sich den Analysis-Cache
+// teilen können, statt je einen eigenen In-Memory-Cache zu pflegen. Analog
+// zum StatsDReporter unter monitoring/reporter verzichtet das auf einen
+// externen Client und spricht das Protokoll direkt über net.Dial, da dieses
+// Repo kein go.mod/Vendoring für Drittanbieter-Clients führt.
+type RedisBackend struct {
+	mu     sync.Mutex
+	addr   string
+	prefix string
+	conn   net.Conn
+
+	hits   int64
+	misses int64
+}
+
+// NewRedisBackend verbindet sich mit einem Redis-kompatiblen Server unter
+// addr ("host:port") und präfixt jeden Key mit prefix, damit mehrere
+// Anwendungen sich dieselbe Instanz teilen können, ohne sich Keys streitig
+// zu machen.
+func NewRedisBackend(addr, prefix string) (*RedisBackend, error) {
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("dial redis at %s: %w", addr, err)
+	}
+	return &RedisBackend{addr: addr, prefix: prefix, conn: conn}, nil
+}
+
+func (r *RedisBackend) key(key string) string {
+	return r.prefix + key
+}
+
+// Get liest key per RESP GET und deserialisiert den gespeicherten JSON-Wert.
+// Ein Miss (Redis Nil-Reply) zählt nicht als Fehler.
+func (r *RedisBackend) Get(key string) (interface{}, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reply, err := r.command("GET", r.key(key))
+	if err != nil || reply == nil {
+		r.misses++
+		return nil, false
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(reply, &data); err != nil {
+		r.misses++
+		return nil, false
+	}
+
+	r.hits++
+	return data, true
+}
+
+// Set schreibt data (als JSON) per RESP SET mit EX-Ablauf in Sekunden.
+func (r *RedisBackend) Set(key string, data interface{}, ttl time.Duration) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seconds := int(ttl.Seconds())
+	if seconds <= 0 {
+		seconds = 1
+	}
+	r.command("SET", r.key(key), string(raw), "EX", strconv.Itoa(seconds))
+}
+
+// Delete entfernt key per RESP DEL. Liefert true, wenn ein Eintrag gelöscht wurde.
+func (r *RedisBackend) Delete(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reply, err := r.command("DEL", r.key(key))
+	if err != nil || reply == nil {
+		return false
+	}
+	return strings.TrimSpace(string(reply)) != "0"
+}
+
+// Keys listet alle Keys mit dem konfigurierten Prefix per RESP KEYS auf.
+func (r *RedisBackend) Keys() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys, err := r.commandArray("KEYS", r.prefix+"*")
+	if err != nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, strings.TrimPrefix(k, r.prefix))
+	}
+	return result
+}
+
+// Stats liefert nur Hits/Misses - Evictions/Disk-Spill-Zähler gibt es bei
+// einem externen Redis-Server nicht, die bleiben auf 0.
+func (r *RedisBackend) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return Stats{
+		Hits:   r.hits,
+		Misses: r.misses,
+	}
+}
+
+// Close trennt die Verbindung zum Redis-Server.
+func (r *RedisBackend) Close() error {
+	return r.conn.Close()
+}
+
+// command sendet args als RESP-Array und liefert den Bulk-String-Inhalt der
+// Antwort (nil bei Redis-Nil-Reply).
+func (r *RedisBackend) command(args ...string) ([]byte, error) {
+	if err := r.writeCommand(args); err != nil {
+		return nil, err
+	}
+	return r.readBulkReply()
+}
+
+// commandArray sendet args und liest eine RESP-Array-Antwort aus Bulk-Strings -
+// für KEYS.
+func (r *RedisBackend) commandArray(args ...string) ([]string, error) {
+	if err := r.writeCommand(args); err != nil {
+		return nil, err
+	}
+	return r.readArrayReply()
+}
+
+func (r *RedisBackend) writeCommand(args []string) error {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&buf, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	_, err := r.conn.Write([]byte(buf.String()))
+	return err
+}
+
+func (r *RedisBackend) readBulkReply() ([]byte, error) {
+	reader := bufio.NewReader(r.conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if len(line) == 0 {
+		return nil, fmt.Errorf("empty redis reply")
+	}
+
+	switch line[0] {
+	case '-':
+		return nil, fmt.Errorf("redis error: %s", line[1:])
+	case '+':
+		return []byte(line[1:]), nil
+	case ':':
+		return []byte(line[1:]), nil
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		body := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			return nil, err
+		}
+		return body[:size], nil
+	default:
+		return nil, fmt.Errorf("unexpected redis reply type %q", line[0])
+	}
+}
+
+func (r *RedisBackend) readArrayReply() ([]string, error) {
+	reader := bufio.NewReader(r.conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if len(line) == 0 || line[0] != '*' {
+		return nil, fmt.Errorf("expected redis array reply, got %q", line)
+	}
+
+	count, err := strconv.Atoi(line[1:])
+	if err != nil || count < 0 {
+		return nil, err
+	}
+
+	results := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		bulkLine, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		bulkLine = strings.TrimRight(bulkLine, "\r\n")
+		if len(bulkLine) == 0 || bulkLine[0] != '$' {
+			return nil, fmt.Errorf("expected redis bulk string, got %q", bulkLine)
+		}
+		size, err := strconv.Atoi(bulkLine[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			continue
+		}
+		body := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			return nil, err
+		}
+		results = append(results, string(body[:size]))
+	}
+	return results, nil
+}
@@ -0,0 +1,81 @@
+// Command fit-bayes-model liest eine Datei mit einem JSON-Objekt
+// {"scores": {...}, "is_ai": bool} pro Zeile (ein historischer
+// Analyse-Lauf je Zeile), fittet daraus die CPDs des Bayes-Netzes und
+// schreibt das Ergebnis nach pkg/verdict/bayes/models/model.gob - siehe
+// pkg/verdict/bayes für die Ladeseite (BayesFusion nutzt die geschriebene
+// Datei automatisch, sobald sie existiert).
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/BramseDev/imageAnalyzer/pkg/verdict/bayes"
+)
+
+func main() {
+	runsPath := flag.String("runs", "", "path to a file with one JSON {\"scores\":{...},\"is_ai\":bool} run per line")
+	flag.Parse()
+
+	if *runsPath == "" {
+		log.Fatal("usage: fit-bayes-model -runs runs.jsonl")
+	}
+
+	runs, err := readRuns(*runsPath)
+	if err != nil {
+		log.Fatalf("failed to read %s: %v", *runsPath, err)
+	}
+
+	model := bayes.Fit(runs)
+	if err := bayes.SaveModel(model); err != nil {
+		log.Fatalf("failed to save model: %v", err)
+	}
+
+	fmt.Printf("fitted model: prior=%.4f n=%d detectors=%d\n", model.Prior, len(runs), len(model.Nodes))
+	for _, detector := range sortedDetectors(model.Nodes) {
+		fmt.Printf("  - %s\n", detector)
+	}
+}
+
+func readRuns(path string) ([]bayes.LabeledRun, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var runs []bayes.LabeledRun
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var run bayes.LabeledRun
+		if err := json.Unmarshal(line, &run); err != nil {
+			return nil, fmt.Errorf("invalid run %q: %w", line, err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+func sortedDetectors(nodes map[string]bayes.NodeCPD) []string {
+	keys := make([]string, 0, len(nodes))
+	for k := range nodes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
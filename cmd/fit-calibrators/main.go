@@ -0,0 +1,110 @@
+// Command fit-calibrators liest eine CSV mit detector,raw_score,label
+// Zeilen, fittet pro Detektor Platt-Scaling, Isotonic Regression und
+// Temperature Scaling via Cross-Validation und schreibt den Calibrator mit
+// dem niedrigsten CV-Brier-Score nach config/calibrators/<detector>.json -
+// siehe pkg/analyzer/calibration für die Ladeseite (verdict.applyBalanced-
+// Calibration nutzt die geschriebenen Dateien automatisch für jeden
+// Detektor, für den eine existiert).
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/BramseDev/imageAnalyzer/pkg/analyzer/calibration"
+)
+
+const defaultFolds = 5
+
+func main() {
+	csvPath := flag.String("csv", "", "path to CSV file with detector,raw_score,label rows")
+	outDir := flag.String("out", "config/calibrators", "directory to write fitted calibrator JSON files into")
+	folds := flag.Int("folds", defaultFolds, "number of cross-validation folds per detector")
+	flag.Parse()
+
+	if *csvPath == "" {
+		log.Fatal("usage: fit-calibrators -csv rows.csv [-out config/calibrators] [-folds 5]")
+	}
+
+	rawByDetector, labelByDetector, err := readRows(*csvPath)
+	if err != nil {
+		log.Fatalf("failed to read %s: %v", *csvPath, err)
+	}
+
+	calibration.SetDir(*outDir)
+
+	for _, detector := range sortedKeys(rawByDetector) {
+		raw := rawByDetector[detector]
+		label := labelByDetector[detector]
+
+		result := calibration.FitBest(raw, label, *folds)
+		if err := calibration.Save(detector, result); err != nil {
+			log.Fatalf("failed to save calibrator for %s: %v", detector, err)
+		}
+
+		fmt.Printf("%-20s method=%-11s n=%-5d brier=%.4f\n", detector, result.Method, len(raw), result.BrierScore)
+	}
+}
+
+// readRows gruppiert die CSV-Zeilen nach detector - jede Zeile ist ein
+// (raw_score, label)-Paar aus einem historischen Analyse-Durchlauf.
+func readRows(path string) (map[string][]float64, map[string][]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+
+	rawByDetector := map[string][]float64{}
+	labelByDetector := map[string][]float64{}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(header) != 3 || header[0] != "detector" {
+		return nil, nil, fmt.Errorf("expected header \"detector,raw_score,label\", got %v", header)
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		detector := record[0]
+		raw, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid raw_score %q for %s: %w", record[1], detector, err)
+		}
+		label, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid label %q for %s: %w", record[2], detector, err)
+		}
+
+		rawByDetector[detector] = append(rawByDetector[detector], raw)
+		labelByDetector[detector] = append(labelByDetector[detector], label)
+	}
+
+	return rawByDetector, labelByDetector, nil
+}
+
+func sortedKeys(m map[string][]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,18 +1,61 @@
 package main
 
 import (
+	"context"
 	"log"
 	"log/slog"
+	"os"
+	"time"
 
+	"github.com/BramseDev/imageAnalyzer/cache"
 	"github.com/BramseDev/imageAnalyzer/dashboard"
 	"github.com/BramseDev/imageAnalyzer/internal/handlers"
+	"github.com/BramseDev/imageAnalyzer/internal/handlers/verdict"
 	"github.com/BramseDev/imageAnalyzer/logging"
+	"github.com/BramseDev/imageAnalyzer/pkg/analyzer/pipeline"
+	"github.com/BramseDev/imageAnalyzer/pkg/pythonrunner"
 
 	"github.com/gin-gonic/gin"
 )
 
+// systemSampleInterval ist das Intervall, in dem Metrics.StartSystemSampler
+// Prozess-/Host-Ressourcen sampelt.
+const systemSampleInterval = 15 * time.Second
+
 func main() {
 	customLogger := logging.NewLogger(slog.LevelInfo)
+	verdict.InitCalibrationConfig("")
+	verdict.InitDetectorConfig("")
+	pythonrunner.InitRegistry("")
+
+	// pipeline kennt verdict nicht (Importzyklus), daher wird die
+	// Config-Fingerprint-Funktion hier verdrahtet - der Analysis-Cache
+	// invalidiert sich selbst, sobald Operator detector.yaml/
+	// calibration.yaml ändern.
+	pipeline.ConfigFingerprint = verdict.ConfigFingerprint
+
+	// REDIS_ADDR setzt den Analysis-Cache auf ein geteiltes Redis-Backend
+	// statt des In-Memory-LRU - für Deployments mit mehreren Instanzen.
+	if redisAddr := os.Getenv("REDIS_ADDR"); redisAddr != "" {
+		redisCache, err := cache.NewRedisBackend(redisAddr, "imageanalyzer:analysis:")
+		if err != nil {
+			log.Printf("WARNING: could not connect to Redis at %s, falling back to in-memory cache: %v", redisAddr, err)
+		} else {
+			pipeline.SetGlobalCacheBackend(redisCache)
+		}
+	} else if cacheDiskDir := os.Getenv("CACHE_DISK_DIR"); cacheDiskDir != "" {
+		// CACHE_DISK_DIR setzt den Analysis-Cache auf ein rein
+		// plattenresidentes Backend - anders als der Spill-Tier von
+		// AnalysisCache übersteht es einen Neustart des Service, ohne
+		// REDIS_ADDR und damit einen externen Redis-Server zu brauchen.
+		diskCache, err := cache.NewDiskBackend(cacheDiskDir)
+		if err != nil {
+			log.Printf("WARNING: could not open disk cache at %s, falling back to in-memory cache: %v", cacheDiskDir, err)
+		} else {
+			pipeline.SetGlobalCacheBackend(diskCache)
+		}
+	}
+
 	r := gin.Default()
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
@@ -34,9 +77,11 @@ func main() {
 	// Connection Tracker als globale Middleware (vor allen Routes)
 	connectionTracker := handlers.NewActiveConnectionTracker(metrics)
 	r.Use(connectionTracker.TrackConnection())
+	metrics.StartVisitorWindowRotation(context.Background())
+	metrics.StartSystemSampler(context.Background(), systemSampleInterval)
 
 	// Dashboard Routes registrieren
-	dashboard.RegisterDashboardRoutes(r)
+	dashboard.RegisterDashboardRoutes(r, metrics)
 
 	customLogger.Info("Server starting", "port", 8080)
 	log.Println("Metrics Dashboard: http://localhost:8080/dashboard/metrics")
@@ -0,0 +1,84 @@
+// Command train-reliability liest eine Datei mit einem JSON-Objekt
+// {"scores": {...}, "is_ai": bool} pro Zeile (ein historischer
+// Analyse-Lauf je Zeile), fittet daraus pro Detektor ein
+// reliability.Model (Precision/Recall/Brier je Score-Bin plus
+// Platt-Scaling) und schreibt das Ergebnis nach
+// pkg/verdict/reliability/models/<detector>.gob - siehe
+// pkg/verdict/reliability für die Ladeseite (ReliabilityAwareWeight und
+// applyBalancedCalibration nutzen die eingebetteten Dateien ab dem nächsten
+// Neubuild automatisch).
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/BramseDev/imageAnalyzer/pkg/verdict/reliability"
+)
+
+func main() {
+	runsPath := flag.String("runs", "", "path to a file with one JSON {\"scores\":{...},\"is_ai\":bool} run per line")
+	flag.Parse()
+
+	if *runsPath == "" {
+		log.Fatal("usage: train-reliability -runs runs.jsonl")
+	}
+
+	runs, err := readRuns(*runsPath)
+	if err != nil {
+		log.Fatalf("failed to read %s: %v", *runsPath, err)
+	}
+
+	models := reliability.Fit(runs)
+	if err := reliability.SaveModels(models); err != nil {
+		log.Fatalf("failed to save models: %v", err)
+	}
+
+	for _, detector := range sortedDetectors(models) {
+		m := models[detector]
+		fmt.Printf("%-20s has_platt=%-5t\n", detector, m.HasPlatt)
+	}
+}
+
+func readRuns(path string) ([]reliability.LabeledRun, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var runs []reliability.LabeledRun
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var run reliability.LabeledRun
+		if err := json.Unmarshal(line, &run); err != nil {
+			return nil, fmt.Errorf("invalid run %q: %w", line, err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+func sortedDetectors(models map[string]reliability.Model) []string {
+	keys := make([]string, 0, len(models))
+	for k := range models {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
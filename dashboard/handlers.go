@@ -2,18 +2,29 @@ package dashboard
 
 import (
 	"net/http"
+	"net/http/pprof"
+	"time"
 
+	"github.com/BramseDev/imageAnalyzer/internal/handlers/verdict"
+	"github.com/BramseDev/imageAnalyzer/monitoring"
+	"github.com/BramseDev/imageAnalyzer/monitoring/prom"
+	"github.com/BramseDev/imageAnalyzer/pkg/verdictstore"
 	"github.com/gin-gonic/gin"
 )
 
 // Registriere Dashboard-Routes
-func RegisterDashboardRoutes(r *gin.Engine) {
+func RegisterDashboardRoutes(r *gin.Engine, metrics *monitoring.Metrics) {
 	// Statische Dateien servieren
 	r.Static("/dashboard/static", "./dashboard/static")
 
 	// Dashboard-Routes
 	r.GET("/dashboard/metrics", metricsPageHandler)
 	r.GET("/dashboard/health", healthPageHandler)
+	r.GET("/dashboard/calibration", calibrationHandler)
+	r.GET("/dashboard/metrics/prom", prometheusHandler(metrics))
+	r.GET("/dashboard/history", historyHandler)
+
+	registerPprofRoutes(r)
 }
 
 func metricsPageHandler(c *gin.Context) {
@@ -27,3 +38,74 @@ func healthPageHandler(c *gin.Context) {
 		"title": "Health Dashboard",
 	})
 }
+
+// calibrationHandler zeigt das aktuell aktive Kalibrierungsprofil
+// (Faktoren + bedingte Gewichtsregeln) und den Zeitpunkt des letzten
+// Reloads, damit Operator A/B-Profile ohne Neudeploy verifizieren können.
+func calibrationHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, verdict.GetCalibrationStatus())
+}
+
+// historyHandler liefert gespeicherte Verdicts aus pkg/verdictstore, gefiltert
+// nach Zeitraum (from/to, RFC3339), Verdict-Band (verdict) und ausgelöstem
+// Analyzer (analyzer, Rohscore >= 0.7). Ungesetzte Parameter deaktivieren das
+// jeweilige Kriterium.
+func historyHandler(c *gin.Context) {
+	filter := verdictstore.Filter{
+		Verdict:           c.Query("verdict"),
+		AnalyzerTriggered: c.Query("analyzer"),
+	}
+
+	if from := c.Query("from"); from != "" {
+		parsed, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from: " + err.Error()})
+			return
+		}
+		filter.From = parsed
+	}
+	if to := c.Query("to"); to != "" {
+		parsed, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to: " + err.Error()})
+			return
+		}
+		filter.To = parsed
+	}
+
+	records := verdictstore.GlobalStore().Query(filter)
+	c.JSON(http.StatusOK, gin.H{
+		"count":    len(records),
+		"verdicts": records,
+	})
+}
+
+// prometheusHandler liefert den vollen Prometheus-Text-Exposition-Scrape über
+// monitoring/prom.Registry - Counter/Gauges für Analysen, Fehler, Verdicts und
+// Cache, sowie EWMA-Rate-Gauges (1m/5m/15m) für Verarbeitungsdauer je
+// Pipeline-Stage sowie Memory/CPU.
+func prometheusHandler(metrics *monitoring.Metrics) gin.HandlerFunc {
+	registry := prom.NewRegistry(metrics)
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := registry.WriteTo(c.Writer); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to render metrics"})
+		}
+	}
+}
+
+// registerPprofRoutes hängt die net/http/pprof Handler unter
+// /dashboard/debug/pprof/* ein, damit während einer laufenden Analyse ein
+// CPU-Profil gezogen werden kann, das dank der "stage"-pprof.Labels in
+// pkg/analyzer/pipeline die Zeit einzelnen Analyzern zuordnet.
+func registerPprofRoutes(r *gin.Engine) {
+	r.GET("/dashboard/debug/pprof/", gin.WrapF(pprof.Index))
+	r.GET("/dashboard/debug/pprof/cmdline", gin.WrapF(pprof.Cmdline))
+	r.GET("/dashboard/debug/pprof/profile", gin.WrapF(pprof.Profile))
+	r.GET("/dashboard/debug/pprof/symbol", gin.WrapF(pprof.Symbol))
+	r.POST("/dashboard/debug/pprof/symbol", gin.WrapF(pprof.Symbol))
+	r.GET("/dashboard/debug/pprof/trace", gin.WrapF(pprof.Trace))
+	r.GET("/dashboard/debug/pprof/:name", func(c *gin.Context) {
+		pprof.Handler(c.Param("name")).ServeHTTP(c.Writer, c.Request)
+	})
+}
@@ -3,19 +3,39 @@ package analysis
 import (
 	"context"
 	"fmt"
-	"log/slog"
 	"time"
 
+	"github.com/BramseDev/imageAnalyzer/logging"
 	"github.com/BramseDev/imageAnalyzer/pkg/analyzer/pipeline"
 )
 
-func RunSecureAnalyses(tempFile string, logger *slog.Logger) (*pipeline.PipelineResult, error) {
+// globalMetrics nimmt, falls über SetGlobalMetrics gesetzt, Cache-Hit/Miss-
+// Zähler entgegen - analog zu handlers.globalLogger. Bleibt es nil, läuft
+// die Pipeline ohne Metrics-Tracking (z.B. in Tests).
+var globalMetrics pipeline.MetricsRecorder
 
-	analysisPipeline := pipeline.NewAnalysisPipeline()
+// SetGlobalMetrics verdrahtet den MetricsRecorder, den RunSecureAnalyses an
+// die Pipeline weiterreicht - wird von handlers.RegisterHandlers beim Start
+// aufgerufen.
+func SetGlobalMetrics(metrics pipeline.MetricsRecorder) {
+	globalMetrics = metrics
+}
+
+// RunSecureAnalyses führt die Pipeline gegen tempFile aus. noCache
+// (?nocache=1) umgeht den geteilten Analysis-Cache für diesen einen Aufruf
+// (siehe pipeline.WithNoCache) - etwa wenn ein Operator einen erzwungenen
+// Re-Run ohne vorheriges DetectorVersion-Hochzählen braucht.
+func RunSecureAnalyses(tempFile string, logger *logging.Logger, noCache bool) (*pipeline.PipelineResult, error) {
+
+	analysisPipeline := pipeline.NewAnalysisPipelineWithCache(globalMetrics)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
+	if noCache {
+		ctx = pipeline.WithNoCache(ctx)
+	}
+
 	results, err := analysisPipeline.RunAnalysis(ctx, tempFile)
 	if err != nil {
 		return nil, fmt.Errorf("pipeline failed: %w", err)
@@ -28,5 +48,32 @@ func RunSecureAnalyses(tempFile string, logger *slog.Logger) (*pipeline.Pipeline
 		"cache_hit", results.CacheHit,
 	)
 
+	logger.LogPipelineMetrics(results.StagesRun, results.ProcessTime, results.EarlyExit, toLoggingStageMetrics(results.StageMetrics))
+
 	return results, nil
 }
+
+// StreamSecureAnalyses führt dieselbe Pipeline wie RunSecureAnalyses aus,
+// liefert aber statt eines einzelnen *pipeline.PipelineResult einen Channel
+// mit einem pipeline.StageEvent je abgeschlossener (oder fortschreitender)
+// Stage - siehe pipeline.AnalysisPipeline.RunAnalysisStream. Das
+// abschließende Event trägt Status "done" und das komplette
+// *pipeline.PipelineResult; ein Aufrufer wie der SSE-Handler von
+// GET /analyze/stream berechnet daraus das Verdict genauso, wie
+// runPipelineAnalysis es nach RunSecureAnalyses tut.
+func StreamSecureAnalyses(ctx context.Context, tempFile string) (<-chan pipeline.StageEvent, error) {
+	analysisPipeline := pipeline.NewAnalysisPipelineWithCache(globalMetrics)
+	return analysisPipeline.RunAnalysisStream(ctx, tempFile)
+}
+
+func toLoggingStageMetrics(stageMetrics map[string]pipeline.StageMetric) map[string]logging.StageMetric {
+	converted := make(map[string]logging.StageMetric, len(stageMetrics))
+	for name, metric := range stageMetrics {
+		converted[name] = logging.StageMetric{
+			CPUNanos:   metric.Duration.Nanoseconds(),
+			AllocBytes: metric.AllocBytes,
+			AllocCount: metric.AllocCount,
+		}
+	}
+	return converted
+}
@@ -78,6 +78,25 @@ func convertAnalysisResult(data interface{}) (map[string]interface{}, error) {
 				}
 			}
 
+			if v.Software != "" {
+				result["software"] = v.Software
+			}
+			if v.LensModel != "" {
+				result["lens_model"] = v.LensModel
+			}
+			if v.CreatorTool != "" {
+				result["creator_tool"] = v.CreatorTool
+			}
+			if v.DigitalSourceType != "" {
+				result["digital_source_type"] = v.DigitalSourceType
+			}
+			if len(v.EditHistory) > 0 {
+				result["edit_history"] = v.EditHistory
+			}
+			if v.MakerNoteFingerprint != "" {
+				result["maker_note_fingerprint"] = v.MakerNoteFingerprint
+			}
+
 			// Raw EXIF als Indikator für Modifikation
 			result["modification_indicators"] = len(v.Raw) > 0
 		}
@@ -101,6 +120,8 @@ func createAnalysisSection(analysisType string, data map[string]interface{}) map
 		section["explanation"] = explanations.GenerateAIExplanation(data)
 	case "metadata":
 		section["explanation"] = explanations.GenerateMetadataExplanation(data)
+	case "metadata-structured":
+		section["explanation"] = explanations.GenerateStructuredMetadataExplanation(data)
 	case "compression":
 		section["explanation"] = explanations.GenerateCompressionExplanation(data)
 	case "pixel-analysis":
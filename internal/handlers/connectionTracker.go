@@ -1,81 +1,70 @@
 package handlers
 
 import (
-	"fmt"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/BramseDev/imageAnalyzer/monitoring"
 	"github.com/gin-gonic/gin"
 )
 
+const visitorCookieName = "analyzer_vid"
+
+// ActiveConnectionTracker liefert zwei getrennte Signale statt des alten
+// clientIP+userAgent-Maps mit 2-Minuten-TTL: einen echten In-Flight-Gauge
+// für Load-Shedding, und eine Sliding-Window-Schätzung eindeutiger Besucher
+// über eine HyperLogLog-Sketch, die auch hinter NAT/Proxies funktioniert,
+// weil sie auf einer pro-Client gesetzten Session-Cookie statt IP+UA basiert.
 type ActiveConnectionTracker struct {
-	mu          sync.RWMutex
-	connections map[string]time.Time
-	metrics     *monitoring.Metrics
+	metrics *monitoring.Metrics
 }
 
 func NewActiveConnectionTracker(metrics *monitoring.Metrics) *ActiveConnectionTracker {
-	tracker := &ActiveConnectionTracker{
-		connections: make(map[string]time.Time),
-		metrics:     metrics,
-	}
-
-	// Cleanup expired connections every 30 seconds
-	go tracker.cleanupExpiredConnections()
-
-	return tracker
+	return &ActiveConnectionTracker{metrics: metrics}
 }
 
 func (t *ActiveConnectionTracker) TrackConnection() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Erstelle bessere Session-ID
-		userAgent := c.Request.UserAgent()
-		clientIP := c.ClientIP()
-
-		// Nur Dashboard-Zugriffe zählen als "aktive User"
-		if strings.Contains(c.Request.URL.Path, "/dashboard") ||
-			strings.Contains(c.Request.URL.Path, "/metrics") ||
-			strings.Contains(c.Request.URL.Path, "/upload") {
-
-			connID := clientIP + ":" + userAgent
-
-			t.mu.Lock()
-			t.connections[connID] = time.Now()
-			activeCount := int64(len(t.connections))
-			t.mu.Unlock()
-
-			// Debug logging
-			fmt.Printf("CONNECTION TRACKER: User activity detected, %d active users\n", activeCount)
+		sessionID := t.ensureVisitorCookie(c)
+		t.metrics.RecordVisitor(hashSession(sessionID))
 
-			t.metrics.UpdateActiveConnections(activeCount)
+		if isTrackedPath(c.Request.URL.Path) {
+			t.metrics.IncrementInFlight()
+			defer t.metrics.DecrementInFlight()
 		}
 
 		c.Next()
 	}
 }
 
-func (t *ActiveConnectionTracker) cleanupExpiredConnections() {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		t.mu.Lock()
-		cutoff := time.Now().Add(-2 * time.Minute) // ← Von 5 auf 2 Minuten reduziert
+func isTrackedPath(path string) bool {
+	return strings.Contains(path, "/dashboard") ||
+		strings.Contains(path, "/metrics") ||
+		strings.Contains(path, "/upload")
+}
 
-		for connID, lastSeen := range t.connections {
-			if lastSeen.Before(cutoff) {
-				delete(t.connections, connID)
-			}
-		}
+// ensureVisitorCookie liest die Besucher-Cookie oder setzt eine neue,
+// zufällige Session-ID - unabhängig von Client-IP oder User-Agent.
+func (t *ActiveConnectionTracker) ensureVisitorCookie(c *gin.Context) string {
+	if cookie, err := c.Cookie(visitorCookieName); err == nil && cookie != "" {
+		return cookie
+	}
 
-		activeCount := int64(len(t.connections))
-		t.mu.Unlock()
+	id := newSessionID()
+	c.SetCookie(visitorCookieName, id, 365*24*60*60, "/", "", false, true)
+	return id
+}
 
-		t.metrics.UpdateActiveConnections(activeCount)
+func newSessionID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
 
-		// Debug logging
-		fmt.Printf("CONNECTION TRACKER: %d active connections after cleanup\n", activeCount)
-	}
+func hashSession(sessionID string) uint64 {
+	sum := sha256.Sum256([]byte(sessionID))
+	return binary.BigEndian.Uint64(sum[:8])
 }
@@ -7,6 +7,10 @@ import (
 )
 
 func GenerateEXIFExplanation(data map[string]interface{}) string {
+	if sourceType, exists := utils.GetStringValue(data, "digital_source_type"); exists && sourceType != "" {
+		return fmt.Sprintf("IPTC DigitalSourceType reports \"%s\". Definitive AI-generation marker.", sourceType)
+	}
+
 	hasCameraInfo, _ := utils.GetFloatValue(data, "has_camera_info")
 
 	if hasCameraInfo > 0 {
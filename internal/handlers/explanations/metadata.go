@@ -16,6 +16,24 @@ func GenerateMetadataExplanation(data map[string]interface{}) string {
 	return "Limited or missing metadata. Could indicate AI generation or heavy post-processing that stripped metadata."
 }
 
+func GenerateStructuredMetadataExplanation(data map[string]interface{}) string {
+	if generator, exists := utils.GetStringValue(data, "known_ai_generator"); exists && generator != "" {
+		return "Known AI generator tag found: " + generator + ". Strong indicator of AI-generated content."
+	}
+
+	if combos, exists := data["suspicious_combinations"].([]interface{}); exists && len(combos) > 0 {
+		return "Suspicious metadata tag combination found (e.g. camera model without lens or GPS). Possible sign of partially forged EXIF data."
+	}
+
+	if stripped, exists := data["stripped_metadata"]; exists {
+		if isStripped, ok := stripped.(bool); ok && isStripped {
+			return "No EXIF, XMP, IPTC or ICC metadata found. Could indicate AI generation or deliberate metadata removal."
+		}
+	}
+
+	return "Structured metadata (EXIF/XMP/IPTC/ICC) found with no suspicious patterns. Consistent with an authentic photograph."
+}
+
 func GenerateQuickMetadataExplanation(data map[string]interface{}) string {
 	if hasMetadata, exists := data["has_metadata"]; exists {
 		if has, ok := hasMetadata.(bool); ok && has {
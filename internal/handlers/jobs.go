@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/BramseDev/imageAnalyzer/internal/handlers/jobs"
+	"github.com/BramseDev/imageAnalyzer/internal/handlers/verdict"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// jobQueueCapacity begrenzt, wie viele async eingereichte Uploads auf
+	// einen freien Worker warten dürfen, bevor neue Submits mit 429
+	// abgelehnt werden - dieselbe Back-Pressure-Idee wie uploadLimiter,
+	// nur für den asynchronen Pfad.
+	jobQueueCapacity = 50
+
+	// jobWorkerCount ist an MaxInFlightAnalyses angelehnt, damit async und
+	// synchrone Analysen dieselbe Gesamt-Parallelität respektieren.
+	jobWorkerCount = MaxInFlightAnalyses
+)
+
+var (
+	jobStore = jobs.NewStore()
+	jobQueue = jobs.NewQueue(jobStore, jobQueueCapacity, jobWorkerCount, analyzeForJob)
+)
+
+// analyzeForJob adaptiert runPipelineAnalysis auf jobs.AnalyzeFunc - der
+// HTTP-Status ist für den asynchronen Pfad irrelevant, da es keinen
+// gin.Context gibt, an den er geschrieben werden könnte.
+func analyzeForJob(tempFilePath string) (map[string]interface{}, error) {
+	response, _, err := runPipelineAnalysis(tempFilePath, time.Now(), false, false, verdict.DefaultEnvironmentalContext())
+	return response, err
+}
+
+// isAsyncMode erkennt den async-Modus über ?mode=async oder den
+// X-Analysis-Mode-Header.
+func isAsyncMode(c *gin.Context) bool {
+	return c.Query("mode") == "async" || c.GetHeader("X-Analysis-Mode") == "async"
+}
+
+// submitAsyncJob reiht tempFilePath als Job ein und antwortet sofort mit
+// 202 Accepted + job_id. Ist die Queue voll, wird die temporäre Datei hier
+// entfernt und 429 zurückgegeben - andernfalls übernimmt der Worker in
+// jobs.Queue.run das Aufräumen, sobald der Job terminiert.
+func submitAsyncJob(c *gin.Context, tempFilePath string) {
+	callbackURL := c.PostForm("callback_url")
+
+	job := jobStore.Create(callbackURL)
+	if !jobQueue.Submit(jobs.QueueItem{JobID: job.ID, TempFilePath: tempFilePath, SubmittedAt: time.Now()}) {
+		os.Remove(tempFilePath)
+		if metrics != nil {
+			metrics.RecordError("upload")
+		}
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many analyses in flight, please retry shortly"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"job_id": job.ID,
+		"status": job.Snapshot().Status,
+	})
+}
+
+// jobStatusHandler liefert den aktuellen Stand eines Jobs für Polling-Clients.
+func jobStatusHandler(c *gin.Context) {
+	job, ok := jobStore.Get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+	c.JSON(http.StatusOK, job.Snapshot())
+}
+
+// jobStreamHandler streamt den Job-Status per Server-Sent Events, bis ein
+// Terminal-Status erreicht ist - ein Snapshot direkt beim Verbindungsaufbau,
+// danach einer je Statuswechsel.
+func jobStreamHandler(c *gin.Context) {
+	job, ok := jobStore.Get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	writeSnapshot := func() bool {
+		snap := job.Snapshot()
+		body, err := json.Marshal(snap)
+		if err != nil {
+			return true
+		}
+		fmt.Fprintf(c.Writer, "data: %s\n\n", body)
+		c.Writer.Flush()
+		return snap.Status == jobs.StatusDone || snap.Status == jobs.StatusFailed
+	}
+
+	if writeSnapshot() {
+		return
+	}
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-job.Subscribe():
+			if writeSnapshot() {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Package jobs implementiert den asynchronen Analyse-Modus: ein Job je
+// eingereichtem Upload, ein bounded Worker-Pool, der die Pipeline im
+// Hintergrund fährt, und ein Store, über den /jobs/:id (Polling) und
+// /jobs/:id/stream (SSE) den aktuellen Stand abfragen. Das Paket kennt die
+// Pipeline selbst nicht - internal/handlers injiziert sie über AnalyzeFunc,
+// um einen Import-Zyklus mit internal/handlers/analysis und
+// internal/handlers/verdict zu vermeiden.
+package jobs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Status ist der Lebenszyklus-Stand eines Jobs.
+type Status string
+
+const (
+	StatusQueued  Status = "queued"
+	StatusRunning Status = "running"
+	StatusDone    Status = "done"
+	StatusFailed  Status = "failed"
+)
+
+// Job ist ein einzelner asynchroner Analyse-Request, von der Einreichung bis
+// zum Abschluss nachverfolgt - unabhängig davon, ob (oder wie oft) der Client
+// zwischendurch pollt.
+type Job struct {
+	mu sync.RWMutex
+
+	ID          string    `json:"job_id"`
+	Status      Status    `json:"status"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+	CallbackURL string    `json:"-"`
+
+	// PipelineOutput spiegelt dasselbe results+verdict+metadata-Payload
+	// wider, das der synchrone /upload-Endpunkt zurückgibt.
+	PipelineOutput map[string]interface{} `json:"result,omitempty"`
+	Error          string                 `json:"error,omitempty"`
+
+	// subscribers werden bei jedem Statuswechsel einmalig benachrichtigt
+	// (geschlossen) - Grundlage für jobStreamHandler, das nach jeder
+	// Benachrichtigung einen frischen Snapshot sendet, bis ein Terminal-
+	// Status erreicht ist.
+	subscribers []chan struct{}
+}
+
+func newJob(callbackURL string) *Job {
+	return &Job{
+		ID:          newJobID(),
+		Status:      StatusQueued,
+		SubmittedAt: time.Now(),
+		CallbackURL: callbackURL,
+	}
+}
+
+func newJobID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// JobSnapshot ist eine unter RLock kopierte Momentaufnahme eines Job - anders
+// als Job selbst ohne mu (und ohne subscribers), damit sie frei kopiert,
+// serialisiert und über deliverCallback an einen Webhook verschickt werden
+// kann. Job selbst darf dafür nie by-value herausgereicht werden: es bettet
+// sync.RWMutex ein, und go vet's copylocks-Check schlägt bei jedem Kopieren
+// eines Mutex-Werts an (auch wenn es hier, da die Kopie stets frisch und
+// ungenutzt ist, zur Laufzeit folgenlos bliebe).
+type JobSnapshot struct {
+	ID          string    `json:"job_id"`
+	Status      Status    `json:"status"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+	CallbackURL string    `json:"-"`
+
+	PipelineOutput map[string]interface{} `json:"result,omitempty"`
+	Error          string                 `json:"error,omitempty"`
+}
+
+// Snapshot liefert eine unter RLock kopierte Momentaufnahme - sicher zum
+// Lesen/Serialisieren, nachdem der Lock wieder freigegeben ist.
+func (j *Job) Snapshot() JobSnapshot {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return JobSnapshot{
+		ID:             j.ID,
+		Status:         j.Status,
+		SubmittedAt:    j.SubmittedAt,
+		StartedAt:      j.StartedAt,
+		FinishedAt:     j.FinishedAt,
+		CallbackURL:    j.CallbackURL,
+		PipelineOutput: j.PipelineOutput,
+		Error:          j.Error,
+	}
+}
+
+// Subscribe liefert einen Channel, der geschlossen wird, sobald sich j.Status
+// das nächste Mal ändert - oder sofort, falls j bereits einen Terminal-Status
+// erreicht hat.
+func (j *Job) Subscribe() <-chan struct{} {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	ch := make(chan struct{})
+	if j.Status == StatusDone || j.Status == StatusFailed {
+		close(ch)
+		return ch
+	}
+	j.subscribers = append(j.subscribers, ch)
+	return ch
+}
+
+func (j *Job) setStatus(status Status) {
+	j.mu.Lock()
+	j.Status = status
+	switch status {
+	case StatusRunning:
+		j.StartedAt = time.Now()
+	case StatusDone, StatusFailed:
+		j.FinishedAt = time.Now()
+	}
+	subs := j.subscribers
+	j.subscribers = nil
+	j.mu.Unlock()
+
+	for _, ch := range subs {
+		close(ch)
+	}
+}
+
+// MarkRunning transitions the job to StatusRunning - called by the worker
+// that picked it off the queue.
+func (j *Job) MarkRunning() {
+	j.setStatus(StatusRunning)
+}
+
+// Complete stores the pipeline output and transitions the job to
+// StatusDone.
+func (j *Job) Complete(output map[string]interface{}) {
+	j.mu.Lock()
+	j.PipelineOutput = output
+	j.mu.Unlock()
+	j.setStatus(StatusDone)
+}
+
+// Fail records err and transitions the job to StatusFailed.
+func (j *Job) Fail(err error) {
+	j.mu.Lock()
+	j.Error = err.Error()
+	j.mu.Unlock()
+	j.setStatus(StatusFailed)
+}
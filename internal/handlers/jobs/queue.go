@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// QueueItem ist eine einzelne an den Worker-Pool übergebene Arbeitseinheit -
+// die vom Handler bereits validierte und gespeicherte temporäre Datei, die
+// zugehörige Job-ID und der Einreichzeitpunkt.
+type QueueItem struct {
+	JobID        string
+	TempFilePath string
+	SubmittedAt  time.Time
+}
+
+// AnalyzeFunc führt Pipeline+Verdict+Metadata für tempFilePath aus und
+// liefert dasselbe Payload-Format wie die synchrone /upload-Antwort -
+// injiziert von internal/handlers, damit jobs keine Abhängigkeit auf
+// internal/handlers/analysis oder internal/handlers/verdict braucht
+// (Import-Zyklus).
+type AnalyzeFunc func(tempFilePath string) (map[string]interface{}, error)
+
+// Queue ist ein bounded Worker-Pool für asynchrone Analyse-Jobs: Submit legt
+// ein QueueItem in einen gepufferten Channel, den numWorkers Goroutinen
+// abarbeiten. Ist der Puffer voll, schlägt Submit fehl statt zu blockieren -
+// Aufrufer sollen das als 429 an den Client zurückgeben, statt die Queue
+// unbegrenzt wachsen zu lassen.
+type Queue struct {
+	items   chan QueueItem
+	store   *Store
+	analyze AnalyzeFunc
+	workers int
+
+	inFlight int64 // atomic - Jobs, die gerade von einem Worker bearbeitet werden
+}
+
+// NewQueue erstellt eine Queue mit capacity gepufferten Slots und startet
+// numWorkers Worker-Goroutinen, die items abarbeiten und über analyze den
+// eigentlichen Analyse-Durchlauf ausführen.
+func NewQueue(store *Store, capacity, numWorkers int, analyze AnalyzeFunc) *Queue {
+	q := &Queue{
+		items:   make(chan QueueItem, capacity),
+		store:   store,
+		analyze: analyze,
+		workers: numWorkers,
+	}
+	for i := 0; i < numWorkers; i++ {
+		go q.worker()
+	}
+	return q
+}
+
+// Submit reiht item ein. ok ist false, wenn die Queue voll ist - in dem Fall
+// nimmt kein Worker item je an, der Aufrufer muss TempFilePath selbst
+// aufräumen.
+func (q *Queue) Submit(item QueueItem) (ok bool) {
+	select {
+	case q.items <- item:
+		return true
+	default:
+		return false
+	}
+}
+
+func (q *Queue) worker() {
+	for item := range q.items {
+		q.run(item)
+	}
+}
+
+// run führt genau ein QueueItem aus und garantiert - unabhängig davon, ob
+// oder wie der Client danach pollt - dass die temporäre Datei entfernt wird.
+func (q *Queue) run(item QueueItem) {
+	defer os.Remove(item.TempFilePath)
+
+	job, ok := q.store.Get(item.JobID)
+	if !ok {
+		return
+	}
+
+	atomic.AddInt64(&q.inFlight, 1)
+	defer atomic.AddInt64(&q.inFlight, -1)
+
+	job.MarkRunning()
+
+	output, err := q.analyze(item.TempFilePath)
+	if err != nil {
+		job.Fail(err)
+	} else {
+		job.Complete(output)
+	}
+
+	if job.CallbackURL != "" {
+		go deliverCallback(job.CallbackURL, job.Snapshot())
+	}
+}
+
+// Depth liefert die Anzahl noch nicht von einem Worker abgeholter Items.
+func (q *Queue) Depth() int {
+	return len(q.items)
+}
+
+// Capacity liefert die Puffergröße der Queue.
+func (q *Queue) Capacity() int {
+	return cap(q.items)
+}
+
+// Utilization liefert den Anteil der Worker, die gerade einen Job bearbeiten
+// (0..1).
+func (q *Queue) Utilization() float64 {
+	if q.workers == 0 {
+		return 0
+	}
+	return float64(atomic.LoadInt64(&q.inFlight)) / float64(q.workers)
+}
+
+// Stats fasst Queue-Tiefe/-Kapazität und Worker-Auslastung für metricsHandler
+// zusammen.
+func (q *Queue) Stats() map[string]interface{} {
+	return map[string]interface{}{
+		"depth":       q.Depth(),
+		"capacity":    q.Capacity(),
+		"workers":     q.workers,
+		"in_flight":   atomic.LoadInt64(&q.inFlight),
+		"utilization": q.Utilization(),
+	}
+}
@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"sync"
+	"time"
+)
+
+// retention bestimmt, wie lange abgeschlossene Jobs nach Abschluss noch
+// abrufbar bleiben, bevor sweep sie entfernt - Jobs sind transiente
+// Work-Items, kein Langzeit-Verlauf wie pkg/verdictstore.
+const retention = 1 * time.Hour
+
+const sweepInterval = 10 * time.Minute
+
+// Store hält Jobs im Speicher, keyed by ID - für Polling (GET /jobs/:id) und
+// SSE (GET /jobs/:id/stream). Nicht persistiert: ein Prozess-Neustart
+// verliert offene Jobs, Clients müssen in dem Fall erneut einreichen.
+type Store struct {
+	mu   sync.RWMutex
+	jobs map[string]*Job
+}
+
+// NewStore erstellt einen leeren Store und startet die Hintergrund-Sweep, die
+// abgeschlossene Jobs nach retention entfernt.
+func NewStore() *Store {
+	s := &Store{jobs: make(map[string]*Job)}
+	go s.sweepLoop()
+	return s
+}
+
+// Create registriert einen neuen, im Status StatusQueued stehenden Job mit
+// der (ggf. leeren) Callback-URL und gibt ihn zurück.
+func (s *Store) Create(callbackURL string) *Job {
+	job := newJob(callbackURL)
+	s.mu.Lock()
+	s.jobs[job.ID] = job
+	s.mu.Unlock()
+	return job
+}
+
+// Get liefert den Job mit der gegebenen ID, falls vorhanden.
+func (s *Store) Get(id string) (*Job, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.jobs[id]
+	return job, ok
+}
+
+func (s *Store) sweepLoop() {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.sweep()
+	}
+}
+
+func (s *Store) sweep() {
+	cutoff := time.Now().Add(-retention)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, job := range s.jobs {
+		snap := job.Snapshot()
+		terminal := snap.Status == StatusDone || snap.Status == StatusFailed
+		if terminal && snap.FinishedAt.Before(cutoff) {
+			delete(s.jobs, id)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package jobs
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/BramseDev/imageAnalyzer/internal/handlers/utils"
+)
+
+// webhookSecret signiert ausgehende Callback-Payloads, damit Empfänger
+// verifizieren können, dass der Callback tatsächlich von diesem Server
+// stammt - konfiguriert per Deployment über JOB_WEBHOOK_SECRET, analog zu
+// verdictstore's VERDICT_STORE_DIR-Override. Leer bedeutet: kein
+// X-Signature-Header.
+var webhookSecret = os.Getenv("JOB_WEBHOOK_SECRET")
+
+// allowedWebhookHosts/deniedWebhookHosts grenzen ein, an welche Hosts
+// callback_url überhaupt ausliefern darf - dieselbe Idee wie
+// IMAGE_FETCH_ALLOWED_HOSTS/IMAGE_FETCH_DENIED_HOSTS für image_url (siehe
+// internal/handlers/utils/remote.go), nur unter einem eigenen
+// JOB_WEBHOOK_*-Namen, da Client und Anwendungsfall verschieden sind. Leer
+// (Default) heißt: keine Einschränkung außer der IP-basierten Prüfung.
+var (
+	allowedWebhookHosts = utils.ParseHostList(os.Getenv("JOB_WEBHOOK_ALLOWED_HOSTS"))
+	deniedWebhookHosts  = utils.ParseHostList(os.Getenv("JOB_WEBHOOK_DENIED_HOSTS"))
+)
+
+func webhookHostAllowed(host string) bool {
+	host = strings.ToLower(host)
+	if deniedWebhookHosts[host] {
+		return false
+	}
+	if len(allowedWebhookHosts) > 0 && !allowedWebhookHosts[host] {
+		return false
+	}
+	return true
+}
+
+// webhookClient ist derselbe SSRF-gehärtete Client-Typ wie
+// utils.FetchRemoteImage ihn für image_url verwendet (private/Loopback/
+// Link-Local-IPs blockiert, Redirects gegen webhookHostAllowed
+// re-validiert) - callback_url kommt genauso unvalidiert vom Client wie
+// image_url und braucht denselben Schutz gegen interne Ziele wie
+// http://169.254.169.254/.
+var webhookClient = utils.NewSSRFGuardedClient(10*time.Second, webhookHostAllowed)
+
+// deliverCallback POSTet job als JSON an callbackURL, mit einer
+// HMAC-SHA256-Signatur des Bodies (hex-kodiert) im X-Signature-Header, falls
+// JOB_WEBHOOK_SECRET gesetzt ist. Fehler werden nicht retried - der Client
+// kann sich weiterhin über GET /jobs/:id vergewissern. Nimmt eine
+// JobSnapshot statt Job selbst entgegen, da Job sync.RWMutex einbettet und
+// go vet's copylocks-Check bei einem by-value-Parameter anschlägt.
+func deliverCallback(callbackURL string, job JobSnapshot) error {
+	parsed, err := url.Parse(callbackURL)
+	if err != nil {
+		return fmt.Errorf("invalid callback_url: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("unsupported callback_url scheme: %s", parsed.Scheme)
+	}
+	if !webhookHostAllowed(parsed.Hostname()) {
+		return fmt.Errorf("callback_url host is not permitted")
+	}
+
+	body, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("marshal job callback payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build job callback request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Job-Id", job.ID)
+	if webhookSecret != "" {
+		req.Header.Set("X-Signature", signPayload(body))
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("deliver job callback: %w", err)
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+func signPayload(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(webhookSecret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
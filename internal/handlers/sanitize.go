@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/BramseDev/imageAnalyzer/internal/handlers/utils"
+	exifanalyzer "github.com/BramseDev/imageAnalyzer/pkg/analyzer/exif_analyzer"
+	"github.com/gin-gonic/gin"
+)
+
+// sanitizeHandler nimmt denselben Multipart-Upload wie uploadHandler entgegen,
+// entfernt aber statt zu analysieren alle EXIF/XMP/IPTC-Segmente und streamt
+// das bereinigte Bild zurück. Damit lässt sich auch messen, wie viel des
+// Verdicts aus Metadaten statt aus Pixeln kommt: einfach die bereinigte
+// Version erneut gegen /upload laufen lassen und die Scores vergleichen.
+func sanitizeHandler(c *gin.Context) {
+	file, header, err := c.Request.FormFile("image")
+	if err != nil {
+		if metrics != nil {
+			metrics.RecordError("sanitize")
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file provided"})
+		return
+	}
+	defer file.Close()
+
+	if err := utils.ValidateFile(header); err != nil {
+		if metrics != nil {
+			metrics.RecordError("sanitize")
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// In einen Buffer schreiben, bevor Header gesetzt werden - die entfernten
+	// Segmente sind erst nach dem vollständigen Durchlauf bekannt.
+	var sanitized bytes.Buffer
+	removed, err := exifanalyzer.StripMetadata(file, &sanitized)
+	if errors.Is(err, exifanalyzer.ErrUnsupportedFormat) {
+		if metrics != nil {
+			metrics.RecordError("sanitize")
+		}
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Sanitizing is currently only supported for JPEG images"})
+		return
+	}
+	if err != nil {
+		if metrics != nil {
+			metrics.RecordError("sanitize")
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sanitize image"})
+		return
+	}
+
+	c.Header("X-Removed-Segments", strings.Join(removed, ","))
+	c.Header("Content-Disposition", "attachment; filename=\"sanitized_"+header.Filename+"\"")
+	if metrics != nil {
+		metrics.RecordSuccess("sanitize")
+	}
+	c.Data(http.StatusOK, "image/jpeg", sanitized.Bytes())
+}
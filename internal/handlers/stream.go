@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/BramseDev/imageAnalyzer/internal/handlers/analysis"
+	"github.com/BramseDev/imageAnalyzer/internal/handlers/utils"
+	"github.com/BramseDev/imageAnalyzer/internal/handlers/verdict"
+	"github.com/BramseDev/imageAnalyzer/pkg/analyzer/pipeline"
+	"github.com/gin-gonic/gin"
+)
+
+// analyzeStreamHandler nimmt denselben multipart-Upload wie uploadHandler
+// entgegen, führt die Pipeline aber über analysis.StreamSecureAnalyses aus
+// und reicht jedes pipeline.StageEvent sofort als SSE-Frame weiter, statt
+// auf das vollständige Ergebnis zu warten - für Clients, die während der
+// Analyse eine Live-Fortschrittsanzeige pro Analyzer zeigen wollen. Das
+// abschließende "done"-Event wird zu einem zusätzlichen "result"-Frame mit
+// demselben results+analysis+metadata-Payload wie POST /upload umgeformt,
+// damit ein Client nicht zwei unterschiedliche Response-Formen parsen muss.
+func analyzeStreamHandler(c *gin.Context) {
+	startTime := time.Now()
+
+	if metrics != nil {
+		if metrics.GetActiveInFlight() >= MaxInFlightAnalyses {
+			metrics.RecordError("upload")
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Too many analyses in flight, please retry shortly"})
+			return
+		}
+
+		metrics.IncrementActiveConnections()
+		defer metrics.DecrementActiveConnections()
+	}
+
+	file, header, err := c.Request.FormFile("image")
+	if err != nil {
+		if metrics != nil {
+			metrics.RecordError("upload")
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file provided"})
+		return
+	}
+	defer file.Close()
+
+	if err := utils.ValidateFile(header); err != nil {
+		if metrics != nil {
+			metrics.RecordError("upload")
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tempFilePath, err := utils.CreateSecureTempFile(file, header)
+	if err != nil {
+		if metrics != nil {
+			metrics.RecordError("upload")
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+		return
+	}
+	defer os.Remove(tempFilePath)
+
+	if err := utils.ValidateFileContent(tempFilePath); err != nil {
+		if metrics != nil {
+			metrics.RecordError("upload")
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := rejectUnsupportedMediaType(tempFilePath); err != nil {
+		if metrics != nil {
+			metrics.RecordError("upload")
+		}
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := analysis.StreamSecureAnalyses(c.Request.Context(), tempFilePath)
+	if err != nil {
+		if metrics != nil {
+			metrics.RecordError("pipeline")
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Analysis failed"})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	envCtx := environmentalContextFromRequest(c)
+
+	writeEvent := func(event string, payload interface{}) {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, body)
+		c.Writer.Flush()
+	}
+
+	for event := range events {
+		if event.Status != "done" {
+			writeEvent("stage", event)
+			continue
+		}
+
+		results, ok := event.PartialResult.(*pipeline.PipelineResult)
+		if !ok {
+			continue
+		}
+
+		if metrics != nil {
+			metrics.RecordSuccess("upload")
+			metrics.RecordDuration("upload", time.Since(startTime))
+		}
+
+		verdictData := verdict.CalculateOverallVerdict(results, envCtx)
+		if metrics != nil {
+			metrics.RecordVerdict(results.Verdict, results.EarlyExit)
+		}
+
+		response := analysis.CreateStructuredResponse(results)
+		response["analysis"] = verdictData
+		response["metadata"] = gin.H{
+			"analysis_duration": time.Since(startTime).Milliseconds(),
+			"pipeline_duration": results.ProcessTime.Milliseconds(),
+			"early_exit":        results.EarlyExit,
+			"cache_hit":         results.CacheHit,
+			"analyses_run":      len(results.Results),
+		}
+
+		writeEvent("result", response)
+	}
+}
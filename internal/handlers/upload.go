@@ -1,29 +1,49 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/BramseDev/imageAnalyzer/alerts"
 	"github.com/BramseDev/imageAnalyzer/internal/handlers/analysis"
 	"github.com/BramseDev/imageAnalyzer/internal/handlers/utils"
 	"github.com/BramseDev/imageAnalyzer/internal/handlers/verdict"
 	"github.com/BramseDev/imageAnalyzer/logging"
 	"github.com/BramseDev/imageAnalyzer/monitoring"
+	"github.com/BramseDev/imageAnalyzer/monitoring/prom"
+	"github.com/BramseDev/imageAnalyzer/pkg/analyzer/pipeline"
+	"github.com/BramseDev/imageAnalyzer/pkg/verdictstore"
 	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
 	MaxFiles          = 3
 	MaxFileSize       = 50 * 1024 * 1024
 	MaxFilenameLength = 255
+
+	// MaxInFlightAnalyses begrenzt, wie viele Uploads gleichzeitig durch die
+	// Pipeline laufen dürfen, bevor neue Requests abgelehnt statt still
+	// angestellt werden.
+	MaxInFlightAnalyses = 10
 )
 
 var (
 	uploadLimiter = make(chan struct{}, MaxFiles)
 	metrics       *monitoring.Metrics
 	globalLogger  *logging.Logger
+
+	// alertManager wird von RegisterHandlers aufgesetzt und läuft bis zum
+	// Prozessende - alertsHandler liest seinen aktuell aktiven Alert-Satz.
+	alertManager *alerts.Manager
 )
 
 func init() {
@@ -38,6 +58,12 @@ func uploadHandler(c *gin.Context) {
 	startTime := time.Now()
 
 	if metrics != nil {
+		if metrics.GetActiveInFlight() >= MaxInFlightAnalyses {
+			metrics.RecordError("upload")
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Too many analyses in flight, please retry shortly"})
+			return
+		}
+
 		metrics.IncrementActiveConnections()
 		defer metrics.DecrementActiveConnections()
 	}
@@ -68,6 +94,209 @@ func uploadHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
 		return
 	}
+
+	if err := utils.ValidateFileContent(tempFilePath); err != nil {
+		os.Remove(tempFilePath)
+		if metrics != nil {
+			metrics.RecordError("upload")
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := rejectUnsupportedMediaType(tempFilePath); err != nil {
+		os.Remove(tempFilePath)
+		if metrics != nil {
+			metrics.RecordError("upload")
+		}
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	}
+
+	if isAsyncMode(c) {
+		// submitAsyncJob entscheidet selbst über das Aufräumen von
+		// tempFilePath: der Queue-Worker übernimmt es bei Erfolg, bei
+		// voller Queue räumt submitAsyncJob selbst auf.
+		submitAsyncJob(c, tempFilePath)
+		return
+	}
+	defer os.Remove(tempFilePath)
+
+	if declaredHash := c.GetHeader("X-Content-SHA256"); declaredHash != "" {
+		if respondFromHashCache(c, tempFilePath, declaredHash) {
+			return
+		}
+	}
+
+	runAnalysisAndRespond(c, tempFilePath, startTime)
+}
+
+// respondFromHashCache verifiziert den vom Client per X-Content-SHA256
+// behaupteten Inhalts-Hash gegen die tatsächlich hochgeladene Datei und
+// beantwortet den Request direkt aus dem Pipeline-Cache, falls ein Treffer
+// vorliegt - erspart Clients, die denselben Inhalt wiederholt prüfen (z.B.
+// Moderations-Pipelines), sowohl den Upload der Bytes als auch die mehrere
+// Sekunden dauernde Python-Analyse. Liefert false, wenn der Hash nicht
+// passt oder kein gecachtes Ergebnis existiert - uploadHandler fällt dann
+// auf den normalen Pipeline-Pfad zurück.
+func respondFromHashCache(c *gin.Context, tempFilePath, declaredHash string) bool {
+	actualHash, err := verdictstore.HashFile(tempFilePath)
+	if err != nil || !strings.EqualFold(actualHash, declaredHash) {
+		return false
+	}
+
+	result, found := pipeline.LookupByHash(actualHash)
+	if !found {
+		return false
+	}
+
+	if metrics != nil {
+		metrics.RecordCacheHit()
+	}
+
+	c.JSON(http.StatusOK, hashCacheResponse(result, environmentalContextFromRequest(c)))
+	return true
+}
+
+// hashCacheResponse bildet ein aus dem Pipeline-Cache gefundenes
+// PipelineResult auf dasselbe results+analysis+metadata-Payload ab, das ein
+// frischer Durchlauf zurückgibt - geteilt zwischen respondFromHashCache und
+// uploadCheckHandler.
+func hashCacheResponse(result *pipeline.PipelineResult, envCtx verdict.EnvironmentalContext) gin.H {
+	response := analysis.CreateStructuredResponse(result)
+	response["analysis"] = verdict.CalculateOverallVerdict(result, envCtx)
+	response["metadata"] = gin.H{
+		"cache_hit": true,
+	}
+	return response
+}
+
+// environmentalContextFromRequest liest die optionalen
+// Environmental-Context-Query-Parameter (source_trust, context, sensitivity)
+// aus c - siehe verdict.EnvironmentalContext. Fehlende oder ungültige Werte
+// fallen auf den neutralen Default zurück (siehe verdict.NewEnvironmentalContext).
+func environmentalContextFromRequest(c *gin.Context) verdict.EnvironmentalContext {
+	sourceTrust, err := strconv.ParseFloat(c.Query("source_trust"), 64)
+	if err != nil {
+		sourceTrust = 0.5
+	}
+	return verdict.NewEnvironmentalContext(sourceTrust, c.Query("context"), c.Query("sensitivity"))
+}
+
+// uploadCheckHandler implementiert den client-seitigen Hash-Pre-Check für
+// Content-Addressed Dedup: statt Bilddaten erneut hochzuladen, kann ein
+// Client per Inhalts-Hash prüfen, ob bereits ein Analyse-Ergebnis im
+// Pipeline-Cache vorliegt. size wird nur zur Diagnose mitgeführt - der
+// Cache ist allein über den Inhalts-Hash indiziert, eine abweichende Größe
+// bei gleichem Hash wäre ohnehin ein Hash-Kollisionsfall.
+type uploadCheckRequest struct {
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+func uploadCheckHandler(c *gin.Context) {
+	var req uploadCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.SHA256 == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sha256 is required"})
+		return
+	}
+
+	result, found := pipeline.LookupByHash(strings.ToLower(req.SHA256))
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no cached analysis for this hash"})
+		return
+	}
+
+	if metrics != nil {
+		metrics.RecordCacheHit()
+	}
+
+	c.JSON(http.StatusOK, hashCacheResponse(result, environmentalContextFromRequest(c)))
+}
+
+// remoteImageRequest ist der JSON-Body für /analyze-remote: entweder eine
+// RFC-2397 Data-URL oder eine vom Server abgerufene image_url - genau eine
+// davon muss gesetzt sein.
+type remoteImageRequest struct {
+	DataURL  string `json:"data_url"`
+	ImageURL string `json:"image_url"`
+}
+
+// remoteUploadHandler nimmt Bilder entgegen, die nicht als multipart-Upload
+// vorliegen (Browser-Extensions, Server-zu-Server-Integrationen), sondern
+// als Base64-Data-URL oder als Remote-URL. Beide Wege münden in denselben
+// CreateSecureTempFileFromBytes/ValidateFileContent-Pfad wie uploadHandler.
+func remoteUploadHandler(c *gin.Context) {
+	startTime := time.Now()
+
+	if metrics != nil {
+		if metrics.GetActiveInFlight() >= MaxInFlightAnalyses {
+			metrics.RecordError("upload")
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Too many analyses in flight, please retry shortly"})
+			return
+		}
+
+		metrics.IncrementActiveConnections()
+		defer metrics.DecrementActiveConnections()
+	}
+
+	var req remoteImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if (req.DataURL == "") == (req.ImageURL == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of data_url or image_url is required"})
+		return
+	}
+
+	var declaredType string
+	var data []byte
+	var err error
+
+	if req.DataURL != "" {
+		declaredType, data, err = utils.ParseDataURL(req.DataURL)
+	} else {
+		fetchStart := time.Now()
+		declaredType, data, err = utils.FetchRemoteImage(req.ImageURL)
+		if metrics != nil {
+			metrics.RecordDuration("fetch", time.Since(fetchStart))
+		}
+	}
+	if err != nil {
+		if metrics != nil {
+			if req.ImageURL != "" {
+				metrics.RecordError(utils.FetchErrorCategory(err))
+			} else {
+				metrics.RecordError("upload")
+			}
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := utils.ValidateDeclaredMediaType(declaredType, data); err != nil {
+		if metrics != nil {
+			metrics.RecordError("upload")
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ext := utils.ExtensionForMediaType(declaredType)
+	if ext == "" {
+		ext = utils.ExtensionForMediaType(http.DetectContentType(data))
+	}
+
+	tempFilePath, err := utils.CreateSecureTempFileFromBytes(data, ext)
+	if err != nil {
+		if metrics != nil {
+			metrics.RecordError("upload")
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+		return
+	}
 	defer os.Remove(tempFilePath)
 
 	if err := utils.ValidateFileContent(tempFilePath); err != nil {
@@ -78,16 +307,86 @@ func uploadHandler(c *gin.Context) {
 		return
 	}
 
+	if err := rejectUnsupportedMediaType(tempFilePath); err != nil {
+		if metrics != nil {
+			metrics.RecordError("upload")
+		}
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	}
+
+	runAnalysisAndRespond(c, tempFilePath, startTime)
+}
+
+// rejectUnsupportedMediaType meldet einen Fehler, wenn pipeline.DetectMIME für
+// tempFilePath kein in pipeline.Registry bekanntes Format erkennt - anders als
+// utils.ValidateFile(Content) (Extension-Allowlist bzw. generische
+// Bild-Lesbarkeit) prüft das hier, ob überhaupt ein Detektor-Profil für das
+// Format existiert, damit ein grundsätzlich lesbares, aber nicht abgedecktes
+// Format mit 415 statt einer irreführenden leeren Analyse beantwortet wird.
+func rejectUnsupportedMediaType(tempFilePath string) error {
+	mimeType, err := pipeline.DetectMIME(tempFilePath)
+	if err != nil {
+		return fmt.Errorf("detect media type: %w", err)
+	}
+	if !pipeline.IsSupportedMIME(mimeType) {
+		return fmt.Errorf("unsupported media type: %s", mimeType)
+	}
+	return nil
+}
+
+// runAnalysisAndRespond führt die Pipeline auf einer bereits validierten
+// temporären Datei aus und schreibt die fertige JSON-Antwort - geteilt
+// zwischen uploadHandler (multipart) und remoteUploadHandler (data_url /
+// image_url). Ist der Inhalts-Hash bereits im Verdict-Store bekannt, wird die
+// gespeicherte Verdict sofort zurückgegeben und die Pipeline übersprungen, es
+// sei denn der Request setzt ?force=1. ?nocache=1 geht weiter und umgeht
+// zusätzlich den geteilten Analysis-Cache der Pipeline (siehe
+// pipeline.WithNoCache) - für einen echten Full-Rerun statt nur eines
+// Verdict-Store-Kurzschlusses.
+func runAnalysisAndRespond(c *gin.Context, tempFilePath string, startTime time.Time) {
+	force := c.Query("force") == "1"
+	noCache := c.Query("nocache") == "1"
+	envCtx := environmentalContextFromRequest(c)
+
+	response, status, err := runPipelineAnalysis(tempFilePath, startTime, force, noCache, envCtx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Analysis failed"})
+		return
+	}
+
+	c.JSON(status, response)
+}
+
+// runPipelineAnalysis fährt die eigentliche Pipeline+Verdict-Auswertung für
+// tempFilePath und liefert dasselbe results+analysis+metadata-Payload, das
+// der synchrone /upload-Endpunkt zurückgibt - geteilt zwischen
+// runAnalysisAndRespond und dem async Job-Queue-Worker (siehe jobs.go), damit
+// beide Pfade exakt dasselbe Verhalten (Cache-Kurzschluss, Metrics,
+// Verdict-Store) haben. status ist der HTTP-Status, den ein synchroner
+// Aufrufer zurückgeben würde (200, auch im Cache-Hit-Fall) - für den
+// asynchronen Pfad irrelevant. envCtx ist der vom Aufrufer deklarierte
+// Environmental-Context (siehe verdict.EnvironmentalContext) - Aufrufer ohne
+// eigene Query-Parameter (Batch, async Worker) übergeben
+// verdict.DefaultEnvironmentalContext().
+func runPipelineAnalysis(tempFilePath string, startTime time.Time, force bool, noCache bool, envCtx verdict.EnvironmentalContext) (map[string]interface{}, int, error) {
+	hash, hashErr := verdictstore.HashFile(tempFilePath)
+
+	if hashErr == nil && !force && !noCache {
+		if cached, found := verdictstore.GlobalStore().Get(hash); found {
+			return cachedVerdictResponse(cached), http.StatusOK, nil
+		}
+	}
+
 	pipelineStart := time.Now()
-	results, err := analysis.RunSecureAnalyses(tempFilePath, globalLogger.Logger)
+	results, err := analysis.RunSecureAnalyses(tempFilePath, globalLogger, noCache)
 	pipelineDuration := time.Since(pipelineStart)
 
 	if err != nil {
 		if metrics != nil {
 			metrics.RecordError("pipeline")
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Analysis failed"})
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if metrics != nil {
@@ -103,17 +402,13 @@ func uploadHandler(c *gin.Context) {
 		}
 	}
 
-	verdictData := verdict.CalculateOverallVerdict(results)
+	verdictData := verdict.CalculateOverallVerdict(results, envCtx)
 
-	if verdictString, exists := verdictData["verdict"].(string); exists {
-		fmt.Printf("DEBUG uploadHandler: Calling RecordVerdict with: %s\n", verdictString)
-		if metrics != nil {
-			metrics.RecordVerdict(verdictString, results.EarlyExit)
-		} else {
-			fmt.Printf("DEBUG: metrics is nil!\n")
-		}
-	} else {
-		fmt.Printf("DEBUG: No verdict string found in: %+v\n", verdictData)
+	// CalculateOverallVerdict sets results.Verdict on the same PipelineResult,
+	// so RecordVerdict gets the typed enum directly instead of re-parsing the
+	// "verdict" string out of verdictData.
+	if metrics != nil {
+		metrics.RecordVerdict(results.Verdict, results.EarlyExit)
 	}
 
 	response := analysis.CreateStructuredResponse(results)
@@ -126,25 +421,270 @@ func uploadHandler(c *gin.Context) {
 		"analyses_run":      len(results.Results),
 	}
 
-	c.JSON(http.StatusOK, response)
+	if hashErr == nil {
+		saveVerdictRecord(hash, results, verdictData)
+	}
+
+	return response, http.StatusOK, nil
 }
 
-func metricsHandler(c *gin.Context) {
-	fmt.Printf("DEBUG: metrics variable: %+v\n", metrics)
+// batchUploadHandler nimmt bis zu MaxFiles Dateien im Feld "images[]" entgegen
+// und analysiert sie parallel, jede unter demselben uploadLimiter-Semaphor
+// wie uploadHandler - ein einzelner fehlgeschlagener Eintrag lässt den Rest
+// des Batches weiterlaufen, statt den ganzen Request scheitern zu lassen.
+func batchUploadHandler(c *gin.Context) {
+	startTime := time.Now()
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid multipart form"})
+		return
+	}
+
+	files := form.File["images[]"]
+	if len(files) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No files provided"})
+		return
+	}
+	if len(files) > MaxFiles {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Maximum %d files per batch request", MaxFiles)})
+		return
+	}
+
+	results := make([]gin.H, len(files))
+	var failureCount int32
+
+	var g errgroup.Group
+	for i, header := range files {
+		i, header := i, header
+		g.Go(func() error {
+			result := processBatchFile(header, startTime)
+			if result["status"] == "error" {
+				atomic.AddInt32(&failureCount, 1)
+			}
+			results[i] = result
+			return nil
+		})
+	}
+	g.Wait()
+
+	if metrics != nil {
+		metrics.RecordBatch(len(files), time.Since(startTime), int(failureCount))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// processBatchFile validiert und analysiert eine einzelne Datei eines
+// Batch-Uploads und liefert dasselbe results+analysis+metadata-Payload wie
+// runPipelineAnalysis, ergänzt um filename/status(/error) - nie einen Fehler,
+// damit ein einzelner Eintrag den Batch nicht abbricht.
+func processBatchFile(header *multipart.FileHeader, startTime time.Time) gin.H {
+	select {
+	case uploadLimiter <- struct{}{}:
+		defer func() { <-uploadLimiter }()
+	case <-time.After(5 * time.Second):
+		return gin.H{"filename": header.Filename, "status": "error", "error": "rate limit exceeded"}
+	}
+
+	if err := utils.ValidateFile(header); err != nil {
+		return gin.H{"filename": header.Filename, "status": "error", "error": err.Error()}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return gin.H{"filename": header.Filename, "status": "error", "error": "failed to open file"}
+	}
+	defer file.Close()
+
+	tempFilePath, err := utils.CreateSecureTempFile(file, header)
+	if err != nil {
+		return gin.H{"filename": header.Filename, "status": "error", "error": "failed to save file"}
+	}
+	defer os.Remove(tempFilePath)
+
+	if err := utils.ValidateFileContent(tempFilePath); err != nil {
+		return gin.H{"filename": header.Filename, "status": "error", "error": err.Error()}
+	}
+
+	if err := rejectUnsupportedMediaType(tempFilePath); err != nil {
+		return gin.H{"filename": header.Filename, "status": "error", "error": err.Error()}
+	}
+
+	response, _, err := runPipelineAnalysis(tempFilePath, startTime, false, false, verdict.DefaultEnvironmentalContext())
+	if err != nil {
+		return gin.H{"filename": header.Filename, "status": "error", "error": "analysis failed"}
+	}
+
+	response["filename"] = header.Filename
+	response["status"] = "ok"
+	return response
+}
+
+// saveVerdictRecord persistiert einen Analyse-Durchlauf im Verdict-Store, aus
+// dem /api/verdicts/{hash}, /dashboard/history und der Rescore-Hintergrundjob
+// gespeist werden. Ein Speicherfehler ist nicht fatal für die Antwort an den
+// Client - er wird nur geloggt.
+func saveVerdictRecord(hash string, results *pipeline.PipelineResult, verdictData map[string]interface{}) {
+	pipelineJSON, err := json.Marshal(results)
+	if err != nil {
+		fmt.Printf("WARNING: failed to marshal pipeline result for verdict store: %v\n", err)
+		return
+	}
+
+	record := &verdictstore.Record{
+		Hash:           hash,
+		Timestamp:      time.Now(),
+		StagesRun:      results.StagesRun,
+		DurationsNanos: durationsToNanos(results.StageMetrics),
+		PipelineResult: json.RawMessage(pipelineJSON),
+	}
+
+	if scores, ok := verdictData["scores"].(map[string]float64); ok {
+		record.RawScores = scores
+	}
+	if calibrated, ok := verdictData["calibrated_scores"].(map[string]float64); ok {
+		record.CalibratedScores = calibrated
+	}
+	if weights, ok := verdictData["weights_used"].(map[string]float64); ok {
+		record.WeightsUsed = weights
+	}
+	if verdictString, ok := verdictData["verdict"].(string); ok {
+		record.Verdict = verdictString
+	}
+	if confidence, ok := verdictData["confidence"].(float64); ok {
+		record.Confidence = confidence
+	}
+
+	if err := verdictstore.GlobalStore().Save(record); err != nil {
+		fmt.Printf("WARNING: failed to save verdict record %s: %v\n", hash, err)
+	}
+}
 
+func durationsToNanos(stageMetrics map[string]pipeline.StageMetric) map[string]int64 {
+	durations := make(map[string]int64, len(stageMetrics))
+	for name, metric := range stageMetrics {
+		durations[name] = metric.Duration.Nanoseconds()
+	}
+	return durations
+}
+
+// cachedVerdictResponse bildet einen gespeicherten Verdict-Record auf dasselbe
+// Response-Format wie ein frischer Pipeline-Durchlauf ab, damit Clients
+// gecachte und frische Antworten nicht unterscheiden müssen.
+func cachedVerdictResponse(record *verdictstore.Record) gin.H {
+	return gin.H{
+		"analysis": gin.H{
+			"verdict":           record.Verdict,
+			"confidence":        record.Confidence,
+			"scores":            record.RawScores,
+			"calibrated_scores": record.CalibratedScores,
+			"weights_used":      record.WeightsUsed,
+		},
+		"metadata": gin.H{
+			"cache_hit":  true,
+			"cached_at":  record.Timestamp,
+			"stages_run": record.StagesRun,
+		},
+	}
+}
+
+// verdictLookupHandler liefert den gespeicherten Verdict-Record für einen
+// Inhalts-Hash zurück - Grundlage für Clients, die vor einem erneuten Upload
+// per Hash prüfen wollen, ob bereits ein Ergebnis vorliegt.
+func verdictLookupHandler(c *gin.Context) {
+	hash := c.Param("hash")
+	record, found := verdictstore.GlobalStore().Get(hash)
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no verdict stored for this hash"})
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
+
+// metricsHandler content-negotiated über den Accept-Header: Prometheus
+// Scraper schicken "Accept: text/plain[;version=...]" oder gar keinen
+// Accept-Header und erwarten das Exposition-Format von monitoring/prom -
+// nur ein Client, der explizit application/json anfragt, bekommt weiterhin
+// die bisherige GetMetricsSummary()-Zusammenfassung. pipeline/upload
+// Verarbeitungsdauern sind dort bereits als
+// analyzer_analysis_duration_seconds{analysis_type="pipeline"|"upload"}
+// Histogramm enthalten - kein separates Histogramm nötig.
+func metricsHandler(c *gin.Context) {
 	if metrics == nil {
-		fmt.Printf("DEBUG: metrics is nil!\n")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "metrics not initialized"})
 		return
 	}
 
-	response := metrics.GetMetricsSummary()
-	fmt.Printf("DEBUG: Full response: %+v\n", response)
+	metrics.RecordCacheStats(pipeline.GetGlobalCacheStats())
+
+	if wantsJSONMetrics(c) {
+		response := metrics.GetMetricsSummary()
+		response["timestamp"] = time.Now().Unix()
+		response["job_queue"] = jobQueue.Stats()
+		response["detector_config"] = verdict.GetDetectorConfigStatus()
+
+		c.Header("Content-Type", "application/json")
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := prom.NewRegistry(metrics).WriteTo(c.Writer); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to render metrics"})
+	}
+}
+
+// cacheInspectHandler liefert Stats und die aktuell im Memory-Tier des
+// geteilten Analysis-Caches gehaltenen Keys - ein Admin-Endpoint für
+// Operator, die nachsehen wollen, was der Pipeline-Cache gerade hält, ohne
+// auf die Platte/den Redis-Server zugreifen zu müssen.
+func cacheInspectHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"stats": pipeline.GetGlobalCacheStats(),
+		"keys":  pipeline.CacheKeys(),
+	})
+}
+
+// cachePurgeEntryHandler entfernt einen einzelnen Analysis-Cache-Eintrag
+// über seinen Inhalts-Hash - z.B. um ein fehlerhaft zwischengespeichertes
+// Ergebnis gezielt zu verwerfen, ohne den gesamten Cache zu leeren.
+func cachePurgeEntryHandler(c *gin.Context) {
+	hash := c.Param("hash")
+	if hash == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "hash is required"})
+		return
+	}
+
+	if !pipeline.PurgeCacheEntry(hash) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no cache entry for hash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"purged": hash})
+}
+
+// cachePurgeAllHandler entfernt alle Keys aus dem Memory-Tier des geteilten
+// Analysis-Caches - grobschlächtiger als cachePurgeEntryHandler, aber
+// nützlich nach einem Deploy, bei dem DetectorVersion nicht hochgezählt
+// wurde, obwohl sich Analyzer-Verhalten geändert hat.
+func cachePurgeAllHandler(c *gin.Context) {
+	keys := pipeline.CacheKeys()
+	purged := 0
+	for _, key := range keys {
+		if pipeline.PurgeCacheKey(key) {
+			purged++
+		}
+	}
 
-	response["timestamp"] = time.Now().Unix()
+	c.JSON(http.StatusOK, gin.H{"purged": purged})
+}
 
-	c.Header("Content-Type", "application/json")
-	c.JSON(http.StatusOK, response)
+// wantsJSONMetrics liefert true, wenn der Client explizit application/json
+// anfragt - der Default (kein Accept-Header, "*/*", oder "text/plain", wie
+// ihn Prometheus-Scraper senden) liefert die Exposition im Textformat.
+func wantsJSONMetrics(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), "application/json")
 }
 
 func healthHandler(c *gin.Context) {
@@ -183,12 +723,92 @@ func healthHandler(c *gin.Context) {
 
 func RegisterHandlers(r *gin.Engine, logger *logging.Logger) *monitoring.Metrics {
 	SetGlobalLogger(logger)
+	analysis.SetGlobalMetrics(metrics)
+
+	verdict.OnCalibrationReload = func() {
+		rescored, err := verdictstore.GlobalStore().RescoreAll()
+		if err != nil {
+			fmt.Printf("WARNING: verdict rescore job failed after %d records: %v\n", rescored, err)
+			return
+		}
+		fmt.Printf("Rescored %d stored verdicts against the reloaded calibration profile\n", rescored)
+	}
+
+	verdict.OnVerdictQuality = func(quality float64, _ string) {
+		metrics.RecordAnalysisQuality(quality)
+	}
+
+	alertManager = newAlertManager(metrics)
+	alertManager.Start(context.Background(), 30*time.Second)
+
 	r.POST("/upload", uploadHandler)
+	r.POST("/analyze/stream", analyzeStreamHandler)
+	r.POST("/upload/check", uploadCheckHandler)
+	r.POST("/upload/batch", batchUploadHandler)
+	r.POST("/analyze-remote", remoteUploadHandler)
+	r.POST("/sanitize", sanitizeHandler)
+	r.GET("/api/verdicts/:hash", verdictLookupHandler)
+	r.GET("/jobs/:id", jobStatusHandler)
+	r.GET("/jobs/:id/stream", jobStreamHandler)
 	r.GET("/metrics", metricsHandler)
+	r.GET("/cache", cacheInspectHandler)
+	r.DELETE("/cache", cachePurgeAllHandler)
+	r.DELETE("/cache/:hash", cachePurgeEntryHandler)
 	r.GET("/health", healthHandler)
+	r.GET("/alerts", alertsHandler)
 	return metrics
 }
 
+// alertsHandler liefert den aktuell aktiven Alert-Satz des alertManager -
+// anders als /health (ein einzelnes "healthy"-Bool) mit Rule-Name, Severity
+// und der zugrundeliegenden Metrik je Alert.
+func alertsHandler(c *gin.Context) {
+	active := alertManager.Active()
+	c.JSON(http.StatusOK, gin.H{
+		"active": active,
+		"count":  len(active),
+	})
+}
+
+// newAlertManager baut den Standard-Satz an Alert-Rules und verdrahtet die
+// über Umgebungsvariablen konfigurierten Sinks - analog zur REDIS_ADDR-
+// Verdrahtung in cmd/server/main.go. stderr ist immer aktiv, Webhook/
+// Alertmanager sind optional.
+func newAlertManager(metrics *monitoring.Metrics) *alerts.Manager {
+	sinks := []alerts.Sink{alerts.StderrSink{}}
+
+	if webhookURL := os.Getenv("ALERT_WEBHOOK_URL"); webhookURL != "" {
+		format := alerts.WebhookFormat(os.Getenv("ALERT_WEBHOOK_FORMAT"))
+		if format == "" {
+			format = alerts.WebhookFormatGeneric
+		}
+		sinks = append(sinks, alerts.NewWebhookSink(webhookURL, format))
+	}
+
+	if alertmanagerURL := os.Getenv("ALERTMANAGER_URL"); alertmanagerURL != "" {
+		sinks = append(sinks, alerts.NewAlertmanagerSink(alertmanagerURL))
+	}
+
+	rules := []alerts.Rule{
+		alerts.ErrorRateRule{AnalysisType: "pipeline", Threshold: 0.1, Sev: alerts.SeverityCritical, CooldownFor: 5 * time.Minute},
+		alerts.ErrorRateRule{AnalysisType: "upload", Threshold: 0.1, Sev: alerts.SeverityWarning, CooldownFor: 5 * time.Minute},
+		alerts.LatencyRule{AnalysisType: "pipeline", Percentile: "p95", Threshold: 30 * time.Second, Sev: alerts.SeverityWarning, CooldownFor: 10 * time.Minute},
+		alerts.LowQualityStreakRule{QualityThreshold: 0.5, MinStreak: 5, Sev: alerts.SeverityWarning, CooldownFor: 15 * time.Minute},
+		&alerts.VerdictDriftRule{Threshold: 0.25, Sev: alerts.SeverityWarning, CooldownFor: 15 * time.Minute},
+	}
+
+	for name := range verdict.GetDetectorConfigStatus().DetectorWeights {
+		rules = append(rules, alerts.DetectorFailureRateRule{
+			Detector:    name,
+			Threshold:   0.5,
+			Sev:         alerts.SeverityWarning,
+			CooldownFor: 15 * time.Minute,
+		})
+	}
+
+	return alerts.NewManager(metrics, sinks, rules...)
+}
+
 // package handlers
 
 // import (
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// detectedFormat ist ein per Magic-Bytes erkanntes Container-Format, das vor
+// der eigentlichen Analyse erst normalisiert werden muss.
+type detectedFormat int
+
+const (
+	formatNone detectedFormat = iota
+	formatHEIF
+	formatAVIF
+	formatDNG
+)
+
+// convertIfNeeded erkennt HEIC/HEIF/AVIF/DNG anhand der Magic Bytes und
+// transcodiert sie zu einem normalisierten JPEG (DNG zu PNG, um das
+// verlustfreie Raw-Material nicht zusätzlich verlustbehaftet zu
+// komprimieren), bevor die restliche Pipeline sie zu Gesicht bekommt. Ist
+// das Format bereits eines, das die Pipeline direkt versteht, wird der Pfad
+// unverändert zurückgegeben.
+func convertIfNeeded(path string) (string, error) {
+	format, err := detectContainerFormat(path)
+	if err != nil {
+		return "", err
+	}
+
+	switch format {
+	case formatHEIF, formatAVIF:
+		return transcode(path, ".jpg", "heif-convert", "-q", "92")
+	case formatDNG:
+		return transcode(path, ".png", "dcraw", "-c", "-T")
+	default:
+		return path, nil
+	}
+}
+
+func detectContainerFormat(path string) (detectedFormat, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return formatNone, err
+	}
+	defer f.Close()
+
+	header := make([]byte, 16)
+	n, _ := f.Read(header)
+	header = header[:n]
+
+	if n >= 12 && bytes.Equal(header[4:8], []byte("ftyp")) {
+		brand := header[8:12]
+		switch {
+		case bytes.Equal(brand, []byte("heic")), bytes.Equal(brand, []byte("heix")), bytes.Equal(brand, []byte("mif1")):
+			return formatHEIF, nil
+		case bytes.Equal(brand, []byte("avif")), bytes.Equal(brand, []byte("avis")):
+			return formatAVIF, nil
+		}
+	}
+
+	// DNG ist ein TIFF mit der DNGVersion-Tag - an dieser Stelle reicht es,
+	// die TIFF-Magic zu erkennen, anhand der Dateiendung zu bestätigen dass
+	// es sich um DNG statt eines regulären TIFF handelt.
+	isTIFF := (n >= 4 && bytes.Equal(header[0:4], []byte{0x49, 0x49, 0x2A, 0x00})) ||
+		(n >= 4 && bytes.Equal(header[0:4], []byte{0x4D, 0x4D, 0x00, 0x2A}))
+	if isTIFF && len(path) > 4 && toLower(path[len(path)-4:]) == ".dng" {
+		return formatDNG, nil
+	}
+
+	return formatNone, nil
+}
+
+func toLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + ('a' - 'A')
+		}
+	}
+	return string(b)
+}
+
+// transcode ruft das passende Kommandozeilenwerkzeug auf, um path in das
+// Zielformat zu konvertieren, und liefert den Pfad der konvertierten Datei.
+func transcode(path string, targetExt string, tool string, args ...string) (string, error) {
+	out := path + "." + fmt.Sprint(time.Now().UnixNano()) + targetExt
+	cmdArgs := append(append([]string{}, args...), path)
+
+	var cmd *exec.Cmd
+	if tool == "dcraw" {
+		// dcraw schreibt per -c nach stdout.
+		cmd = exec.Command(tool, cmdArgs...)
+		outFile, err := os.Create(out)
+		if err != nil {
+			return "", err
+		}
+		defer outFile.Close()
+		cmd.Stdout = outFile
+	} else {
+		cmdArgs = append(cmdArgs, out)
+		cmd = exec.Command(tool, cmdArgs...)
+	}
+
+	if combined, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(out)
+		return "", fmt.Errorf("%s failed: %v\n%s", tool, err, combined)
+	}
+
+	if _, err := os.Stat(out); err != nil {
+		return "", fmt.Errorf("%s did not produce an output file", tool)
+	}
+
+	return out, nil
+}
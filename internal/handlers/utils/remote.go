@@ -0,0 +1,310 @@
+package utils
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// dataURLPattern erkennt RFC-2397-Data-URLs: data:[<mediatype>][;base64],<data>
+var dataURLPattern = regexp.MustCompile(`^data:([a-zA-Z0-9.+-]+/[a-zA-Z0-9.+-]+)?(;base64)?,`)
+
+// ParseDataURL dekodiert eine RFC-2397 Data-URL und liefert den deklarierten
+// Media-Type sowie die dekodierten Bytes zurück. Die Größe wird gegen
+// MaxFileSize geprüft, bevor der Aufrufer die Bytes überhaupt anfasst.
+func ParseDataURL(dataURL string) (string, []byte, error) {
+	loc := dataURLPattern.FindStringSubmatchIndex(dataURL)
+	if loc == nil {
+		return "", nil, fmt.Errorf("not a valid data URL")
+	}
+
+	mediaType := ""
+	if loc[2] != -1 {
+		mediaType = dataURL[loc[2]:loc[3]]
+	}
+	isBase64 := loc[4] != -1
+	payload := dataURL[loc[1]:]
+
+	var data []byte
+	var err error
+	if isBase64 {
+		data, err = base64.StdEncoding.DecodeString(payload)
+	} else {
+		var unescaped string
+		unescaped, err = url.QueryUnescape(payload)
+		data = []byte(unescaped)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to decode data URL payload: %w", err)
+	}
+
+	if int64(len(data)) > MaxFileSize {
+		return "", nil, fmt.Errorf("decoded image exceeds max size of %d bytes", MaxFileSize)
+	}
+
+	return mediaType, data, nil
+}
+
+// ErrPrivateAddress wird zurückgegeben, wenn image_url auf eine private,
+// Loopback- oder Link-Local-Adresse auflöst - das verhindert, dass der
+// Server als SSRF-Proxy in interne Netze missbraucht wird.
+var ErrPrivateAddress = fmt.Errorf("refusing to fetch from a private or loopback address")
+
+// ErrBlockedHost wird zurückgegeben, wenn der Host von image_url auf
+// IMAGE_FETCH_DENIED_HOSTS steht oder - falls IMAGE_FETCH_ALLOWED_HOSTS
+// gesetzt ist - nicht Teil dieser Allowlist ist.
+var ErrBlockedHost = fmt.Errorf("image_url host is not permitted")
+
+// ErrDNSFailure wird zurückgegeben, wenn der Host von image_url sich nicht
+// auflösen lässt.
+var ErrDNSFailure = fmt.Errorf("failed to resolve image_url host")
+
+// ErrTooLarge wird zurückgegeben, wenn die Remote-Antwort MaxFileSize
+// überschreitet.
+var ErrTooLarge = fmt.Errorf("remote image exceeds max size of %d bytes", MaxFileSize)
+
+// allowedFetchHosts/deniedFetchHosts konfigurieren, von welchen Hosts
+// image_url überhaupt geladen werden darf - zusätzlich zur IP-basierten
+// SSRF-Prüfung in remoteImageClient, z.B. um Fetches auf bekannte
+// CDN/S3-Hosts einzugrenzen. Leer (Default) heißt: keine Einschränkung
+// außer der IP-basierten Prüfung.
+var (
+	allowedFetchHosts = ParseHostList(os.Getenv("IMAGE_FETCH_ALLOWED_HOSTS"))
+	deniedFetchHosts  = ParseHostList(os.Getenv("IMAGE_FETCH_DENIED_HOSTS"))
+)
+
+// ParseHostList zerlegt eine kommaseparierte Host-Liste aus der Umgebung
+// (z.B. IMAGE_FETCH_ALLOWED_HOSTS) in ein Lookup-Set - exportiert, damit
+// andere SSRF-gehärtete Aufrufer (z.B. jobs.deliverCallback) ihre eigene
+// Allow-/Denylist im selben Format konfigurieren können.
+func ParseHostList(raw string) map[string]bool {
+	hosts := make(map[string]bool)
+	for _, h := range strings.Split(raw, ",") {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h != "" {
+			hosts[h] = true
+		}
+	}
+	return hosts
+}
+
+func fetchHostAllowed(host string) bool {
+	host = strings.ToLower(host)
+	if deniedFetchHosts[host] {
+		return false
+	}
+	if len(allowedFetchHosts) > 0 && !allowedFetchHosts[host] {
+		return false
+	}
+	return true
+}
+
+// FetchRemoteImage lädt rawURL über einen auf Bildgrößen begrenzten,
+// SSRF-gehärteten HTTP-Client: Timeout, begrenzte Redirects (jeder Hop
+// durchläuft erneut remoteImageClient's DialContext-Prüfung), Host-
+// Allow-/Denylist, Auflösung vor Verbindungsaufbau mit Blockliste für
+// private/Loopback/Link-Local-Adressen, und ein io.LimitReader, der exakt
+// bei MaxFileSize+1 Bytes abbricht. Fehler sind mit errors.Is gegen
+// ErrBlockedHost/ErrPrivateAddress/ErrDNSFailure/ErrTooLarge prüfbar (siehe
+// FetchErrorCategory).
+func FetchRemoteImage(rawURL string) (string, []byte, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid image_url: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", nil, fmt.Errorf("unsupported image_url scheme: %s", parsed.Scheme)
+	}
+	if !fetchHostAllowed(parsed.Hostname()) {
+		return "", nil, ErrBlockedHost
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := remoteImageClient().Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch image_url: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("image_url returned status %d", resp.StatusCode)
+	}
+
+	limited := io.LimitReader(resp.Body, MaxFileSize+1)
+	data, err := io.ReadAll(limited)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read image_url response: %w", err)
+	}
+	if int64(len(data)) > MaxFileSize {
+		return "", nil, ErrTooLarge
+	}
+
+	return resp.Header.Get("Content-Type"), data, nil
+}
+
+// FetchErrorCategory ordnet einen von FetchRemoteImage zurückgegebenen Fehler
+// einer der von metrics.RecordError erwarteten Kategorien zu, damit Aufrufer
+// fetch-spezifische Fehler getrennt von generischen Upload-Fehlern zählen
+// können.
+func FetchErrorCategory(err error) string {
+	switch {
+	case errors.Is(err, ErrTooLarge):
+		return "too_large"
+	case errors.Is(err, ErrBlockedHost), errors.Is(err, ErrPrivateAddress):
+		return "blocked_host"
+	case errors.Is(err, ErrDNSFailure):
+		return "dns"
+	case isFetchTimeout(err):
+		return "timeout"
+	default:
+		return "fetch"
+	}
+}
+
+func isFetchTimeout(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+	return false
+}
+
+func remoteImageClient() *http.Client {
+	return NewSSRFGuardedClient(10*time.Second, fetchHostAllowed)
+}
+
+// NewSSRFGuardedClient baut einen http.Client, dessen DialContext jede für
+// einen Hostnamen aufgelöste IP gegen isPrivateOrReservedIP prüft
+// (ErrPrivateAddress) und dessen CheckRedirect zusätzlich jeden Redirect-Hop
+// gegen hostAllowed validiert (ErrBlockedHost) - Grundlage sowohl für
+// FetchRemoteImage (image_url, hostAllowed=fetchHostAllowed) als auch für
+// jobs.deliverCallback (callback_url, eigene Allow-/Denylist), die beide
+// denselben SSRF-Schutz brauchen, aber unterschiedliche Hostlisten
+// durchsetzen. hostAllowed darf nil sein, dann gilt nur die IP-basierte
+// Prüfung.
+func NewSSRFGuardedClient(timeout time.Duration, hostAllowed func(host string) bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+
+			ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrDNSFailure, err)
+			}
+
+			for _, ip := range ips {
+				if isPrivateOrReservedIP(ip) {
+					return nil, ErrPrivateAddress
+				}
+			}
+
+			return dialer.DialContext(ctx, network, net.JoinHostPort(ips[0].String(), port))
+		},
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 3 {
+				return fmt.Errorf("too many redirects")
+			}
+			// Jeder Redirect-Hop wird erneut gegen hostAllowed geprüft -
+			// sonst wäre ein Redirect auf einen gesperrten Host nur noch
+			// durch die IP-basierte DialContext-Prüfung abgedeckt, nicht
+			// mehr durch die explizite Hostliste.
+			if hostAllowed != nil && !hostAllowed(req.URL.Hostname()) {
+				return ErrBlockedHost
+			}
+			return nil
+		},
+	}
+}
+
+func isPrivateOrReservedIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() ||
+		ip.IsMulticast()
+}
+
+// imageMediaTypeFamily normalisiert übliche Alias-Schreibweisen
+// ("image/jpg" vs. "image/jpeg"), damit der deklarierte Media-Type einer
+// Data-URL/Response gegen das Ergebnis von http.DetectContentType
+// verglichen werden kann, ohne an Formalismen zu scheitern.
+func imageMediaTypeFamily(mediaType string) string {
+	mt := strings.ToLower(strings.TrimSpace(mediaType))
+	if idx := strings.Index(mt, ";"); idx >= 0 {
+		mt = mt[:idx]
+	}
+	if mt == "image/jpg" {
+		mt = "image/jpeg"
+	}
+	return mt
+}
+
+// ValidateDeclaredMediaType stellt sicher, dass ein vom Client deklarierter
+// Media-Type (Data-URL-Header oder Content-Type-Response-Header) zur
+// tatsächlichen Byte-Signatur passt, die http.DetectContentType ermittelt.
+// Ein leerer deklarierter Type wird übersprungen - er ist bei image_url
+// ohnehin optional.
+func ValidateDeclaredMediaType(declared string, data []byte) error {
+	if declared == "" {
+		return nil
+	}
+
+	detected := http.DetectContentType(data)
+	if imageMediaTypeFamily(declared) != imageMediaTypeFamily(detected) {
+		return fmt.Errorf("declared media type %q does not match detected content %q", declared, detected)
+	}
+	return nil
+}
+
+// ExtensionForMediaType bildet einen erkannten Bild-Media-Type auf eine
+// Dateiendung ab, damit CreateSecureTempFileFromBytes denselben
+// erweiterungsbasierten Konvertierungspfad (convertIfNeeded) wie ein
+// regulärer multipart-Upload durchläuft.
+func ExtensionForMediaType(mediaType string) string {
+	switch imageMediaTypeFamily(mediaType) {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/bmp":
+		return ".bmp"
+	case "image/tiff":
+		return ".tiff"
+	case "image/webp":
+		return ".webp"
+	case "image/heic":
+		return ".heic"
+	case "image/heif":
+		return ".heif"
+	case "image/avif":
+		return ".avif"
+	default:
+		return ""
+	}
+}
@@ -31,7 +31,12 @@ func ValidateFile(header *multipart.FileHeader) error {
 
 	// Check file extension
 	ext := strings.ToLower(filepath.Ext(header.Filename))
-	allowedExts := []string{".jpg", ".jpeg", ".png", ".gif", ".bmp", ".tiff", ".webp"}
+	allowedExts := []string{
+		".jpg", ".jpeg", ".png", ".gif", ".bmp", ".tiff", ".webp",
+		// Container-Formate werden von CreateSecureTempFile vor der
+		// Content-Validierung nach JPEG/PNG transcodiert (siehe convert.go).
+		".heic", ".heif", ".avif", ".dng",
+	}
 
 	validExt := false
 	for _, allowedExt := range allowedExts {
@@ -109,6 +114,50 @@ func CreateSecureTempFile(file multipart.File, header *multipart.FileHeader) (st
 		os.Remove(tempFile)
 		return "", fmt.Errorf("failed to copy file: %w", err)
 	}
+	out.Close()
+
+	converted, err := convertIfNeeded(tempFile)
+	if err != nil {
+		os.Remove(tempFile)
+		return "", fmt.Errorf("failed to convert %s to a supported format: %w", filepath.Ext(header.Filename), err)
+	}
+	if converted != tempFile {
+		os.Remove(tempFile)
+		return converted, nil
+	}
+
+	return tempFile, nil
+}
+
+// CreateSecureTempFileFromBytes ist das Gegenstück zu CreateSecureTempFile
+// für Bilder, die nicht als multipart-Upload, sondern als Data-URL oder
+// über image_url eingereicht wurden (siehe remote.go). Es teilt denselben
+// zufälligen Dateinamen und denselben convertIfNeeded-Konvertierungspfad,
+// damit beide Einreichungswege identisch validiert und verarbeitet werden.
+func CreateSecureTempFileFromBytes(data []byte, ext string) (string, error) {
+	randomBytes := make([]byte, 16)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", fmt.Errorf("failed to generate random filename: %w", err)
+	}
+
+	if ext == "" {
+		ext = ".jpg"
+	}
+	tempFile := fmt.Sprintf("/tmp/analyzer_%x%s", randomBytes, ext)
+
+	if err := os.WriteFile(tempFile, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	converted, err := convertIfNeeded(tempFile)
+	if err != nil {
+		os.Remove(tempFile)
+		return "", fmt.Errorf("failed to convert image to a supported format: %w", err)
+	}
+	if converted != tempFile {
+		os.Remove(tempFile)
+		return converted, nil
+	}
 
 	return tempFile, nil
 }
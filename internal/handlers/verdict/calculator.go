@@ -3,12 +3,27 @@ package verdict
 import (
 	"fmt"
 	"math"
-	"strings"
 
+	"github.com/BramseDev/imageAnalyzer/pkg/analyzer/consensus"
+	"github.com/BramseDev/imageAnalyzer/pkg/analyzer/explain"
+	"github.com/BramseDev/imageAnalyzer/pkg/analyzer/fusion"
 	"github.com/BramseDev/imageAnalyzer/pkg/analyzer/pipeline"
+	"github.com/BramseDev/imageAnalyzer/pkg/verdict/bayes"
+	"github.com/BramseDev/imageAnalyzer/pkg/verdict/reliability"
 )
 
-func CalculateOverallVerdict(results *pipeline.PipelineResult) map[string]interface{} {
+// bayesModel hält das einmal geladene Bayes-Netz (siehe pkg/verdict/bayes)
+// im Prozessspeicher, damit computeVerdict nicht bei jeder Anfrage die
+// Gob-Datei erneut von der Platte lesen muss - analog zur gecachten
+// activeDetectorConfig. Ein Neuladen nach fit-bayes-model erfordert aktuell
+// einen Prozess-Neustart, wie auch bei den Calibrator-JSONs.
+var bayesModel = bayes.NewFusion(bayes.LoadModel())
+
+// CalculateOverallVerdict wertet results gegen envCtx aus. envCtx ist der
+// vom Aufrufer deklarierte Umgebungs-Kontext (Quellen-Vertrauen, Bildtyp,
+// Ziel-Sensitivität) - Aufrufer ohne eigene Angabe verwenden
+// DefaultEnvironmentalContext().
+func CalculateOverallVerdict(results *pipeline.PipelineResult, envCtx EnvironmentalContext) map[string]interface{} {
 	scores := make(map[string]float64)
 	reasoning := []string{}
 
@@ -24,6 +39,7 @@ func CalculateOverallVerdict(results *pipeline.PipelineResult) map[string]interf
 		"object-coherence",
 		"compression",
 		"metadata",
+		"metadata-structured",
 		"c2pa",
 		"exif",
 		"metadata-quick",
@@ -40,25 +56,14 @@ func CalculateOverallVerdict(results *pipeline.PipelineResult) map[string]interf
 	// DEBUG: Log alle rohen Scores
 	fmt.Printf("\n=== DEBUG SCORES ===\n")
 
-	weights := map[string]float64{
-		"ai-model": 6.0,
+	// weights kommt aus der aktiven DetectorConfig (siehe
+	// detector_config.go) statt hartkodiert zu sein - Operator können die
+	// Pro-Detektor-Gewichte per detector.yaml nachjustieren. MergeFormatWeights
+	// wendet zusätzlich die Registry-Overrides für results.MimeType an (z.B.
+	// stärkere Metadata-Gewichtung bei GIF, siehe pipeline/registry.go).
+	weights := pipeline.MergeFormatWeights(results.MimeType, currentDetectorConfig().DetectorWeights)
 
-		"compression":        4.0,
-		"lighting-analysis":  3.5,
-		"artifacts":          3.0,
-		"advanced-artifacts": 3.0,
-		"color-balance":      3.0,
-
-		"metadata":       2.5,
-		"pixel-analysis": 2.5,
-		"c2pa":           2.0,
-
-		"object-coherence": 0.5,
-		"exif":             1.0,
-		"metadata-quick":   0.8,
-	}
-
-	var definitiveScore float64 = -1
+	rawResults := make(map[string]map[string]interface{})
 
 	for name, result := range results.Results {
 		var score float64 = -1
@@ -92,6 +97,8 @@ func CalculateOverallVerdict(results *pipeline.PipelineResult) map[string]interf
 			score = calculateEXIFScore(resultData)
 		case "metadata":
 			score = calculateMetadataScore(resultData)
+		case "metadata-structured":
+			score = calculateMetadataStructuredScore(resultData)
 		case "metadata-quick":
 			score = calculateMetadataQuickScore(resultData)
 		case "c2pa":
@@ -103,6 +110,7 @@ func CalculateOverallVerdict(results *pipeline.PipelineResult) map[string]interf
 
 		if score >= 0 {
 			scores[name] = score
+			rawResults[name] = resultData
 
 			// Kategorisiere Score - NUR NOCH 2 KATEGORIEN
 			if contains(computerVisionMethods, name) {
@@ -113,138 +121,177 @@ func CalculateOverallVerdict(results *pipeline.PipelineResult) map[string]interf
 
 			// DEBUG: Log jeden Score
 			fmt.Printf("RAW %s: %.3f\n", name, score)
-
-			if name == "metadata" && score >= 0.95 {
-				definitiveScore = 1.0
-				reasoning = append(reasoning, "Definitive AI metadata found")
-				fmt.Printf("DEFINITIVE: Metadata AI found (%.3f)\n", score)
-				break
-			}
-			if name == "c2pa" && score >= 0.95 {
-				definitiveScore = 1.0
-				reasoning = append(reasoning, "C2PA certificate confirms AI generation")
-				fmt.Printf("DEFINITIVE: C2PA AI found (%.3f)\n", score)
-				break
-			}
 		}
 	}
 
 	// Berechne separate Scores - NUR NOCH 2 KATEGORIEN
 	computerVisionScore := calculateComputerVisionScore(computerVisionScores)
 	aiAnalysisScore := calculateAIAnalysisScore(aiScores)
+	metadataScore := calculateMetadataAverageScore(scores)
+
+	// consensus.Analyze ersetzt das frühere analyzeMethodAgreement-Zählen
+	// durch eine CSP-Bogenkonsistenzprüfung über traditional/ai-model/
+	// metadata (siehe pkg/analyzer/consensus).
+	consensusResult := consensus.Analyze(computerVisionScore, aiAnalysisScore, metadataScore)
 
 	// DEBUG: Log separate scores
 	fmt.Printf("\n=== SEPARATE SCORES ===\n")
 	fmt.Printf("Computer Vision (incl. Metadata): %.3f\n", computerVisionScore)
 	fmt.Printf("AI Deep Learning: %.3f\n", aiAnalysisScore)
 
-	var finalScore float64
-	var verdict string
-	var confidence float64
-
-	if definitiveScore >= 0 {
-		finalScore = definitiveScore
-		verdict = "AI Generated"
-		confidence = 0.95
-	} else {
-		// Kalibrierte Scores
-		calibratedScores := applyBalancedCalibration(scores)
-
-		fmt.Printf("\n=== CALIBRATED SCORES ===\n")
-		for name, score := range calibratedScores {
-			fmt.Printf("CAL %s: %.3f (was %.3f)\n", name, score, scores[name])
+	finalScore, verdict, confidence, calibratedScores, logitContributions, computedReasoning, ok := computeVerdict(scores, weights)
+	if !ok {
+		results.Verdict = pipeline.VerdictUnknown
+		notifyVerdictQuality(0.0, "Analysis Failed")
+		return map[string]interface{}{
+			"verdict":     "Analysis Failed",
+			"probability": 0.0,
+			"confidence":  0.0,
+			"summary":     "No usable analysis results obtained",
+			"reasoning":   []string{"Technical error during analysis"},
+			"scores":      scores,
 		}
-
-		// Pattern-Boost
-		patternBoost := calculateAdvancedBoost(calibratedScores)
-		fmt.Printf("\nPATTERN BOOST: %.3f\n", patternBoost)
-
-		var weightedSum float64
-		var totalWeight float64
-
-		for name, score := range calibratedScores {
-			weight := weights[name]
-			if weight == 0 {
-				continue
-			}
-
-			if name == "ai-model" {
-				continue // Skip AI-Model für finalScore
-			}
-
-			adaptiveWeight := weight
-			if score >= 0.8 {
-				adaptiveWeight *= 1.2
-			} else if score <= 0.2 {
-				adaptiveWeight *= 1.3
-			}
-
-			contribution := score * adaptiveWeight
-			weightedSum += contribution
-			totalWeight += adaptiveWeight
-
-			fmt.Printf("CONTRIB %s: score=%.3f * weight=%.3f = %.3f\n",
-				name, score, adaptiveWeight, contribution)
-
-			if score >= 0.7 {
-				reasoning = append(reasoning, fmt.Sprintf("%s: Strong AI indicators (%.0f%% probability)", name, score*100))
-			} else if score <= 0.3 {
-				reasoning = append(reasoning, fmt.Sprintf("%s: Authenticity indicators (%.0f%% authentic)", name, (1-score)*100))
-			} else {
-				reasoning = append(reasoning, fmt.Sprintf("%s: Moderate signals (%.0f%% probability)", name, score*100))
+	}
+	reasoning = append(reasoning, computedReasoning...)
+
+	// baseScore ist der rohe Detektor-Fusion-Score aus computeVerdict, vor
+	// der Temporal-/Environmental-Korrektur unten - mirror CVSS' Base Score.
+	// temporalCoeff korrigiert ihn um die Reife der verfügbaren Evidenz
+	// (computeTemporalCoefficient), environmentalCoeff um den vom Aufrufer
+	// deklarierten Einsatzkontext (computeEnvironmentalCoefficient). Beide
+	// sind beschränkte multiplikative Faktoren, appliedModifiers dokumentiert
+	// für die Antwort, welche konkret gegriffen haben.
+	baseScore := finalScore
+	temporalCoeff, temporalModifiers := computeTemporalCoefficient(results, scores)
+	environmentalCoeff, environmentalModifiers := computeEnvironmentalCoefficient(envCtx)
+	appliedModifiers := append(append([]string{}, temporalModifiers...), environmentalModifiers...)
+
+	finalScore = baseScore * temporalCoeff * environmentalCoeff
+	if finalScore > 1 {
+		finalScore = 1
+	} else if finalScore < 0 {
+		finalScore = 0
+	}
+	finalScore = math.Round(finalScore*1000) / 1000 // round probability to 1 decimal place (%)
+
+	// coverageWeights beschränkt weights für die evidence_coverage-Prüfung
+	// auf die laut Registry für results.MimeType vorgesehenen Detektoren -
+	// weights selbst enthält immer die volle DetectorConfig, auch für
+	// Detektoren, die für dieses Format gar nicht laufen (z.B. "compression"
+	// bei GIF), was sonst jede Analyse dieses Formats künstlich Richtung
+	// Inconclusive drücken würde, analog zu calculateAnalysisQuality oben.
+	coverageWeights := weights
+	if names, ok := pipeline.ExpectedDetectors(results.MimeType); ok {
+		coverageWeights = make(map[string]float64, len(names))
+		for _, name := range names {
+			if w, exists := weights[name]; exists {
+				coverageWeights[name] = w
 			}
 		}
+	}
 
-		if totalWeight == 0 {
-			return map[string]interface{}{
-				"verdict":     "Analysis Failed",
-				"probability": 0.0,
-				"confidence":  0.0,
-				"summary":     "No usable analysis results obtained",
-				"reasoning":   []string{"Technical error during analysis"},
-				"scores":      scores,
+	var evidenceCoverageRatio float64
+	var abstainReason string
+	verdict, confidence, evidenceCoverageRatio, abstainReason = determineBalancedVerdict(finalScore, calibratedScores, coverageWeights)
+
+	// Zweiter Abstain-Auslöser neben evidence_coverage: determineBalancedVerdict
+	// sieht nur den flachen Detektor-Score-Vektor, nicht die separate
+	// computer_vision/ai_model-Aufteilung, die erst hier verfügbar ist. Ist
+	// der ai-model-Detektor gar nicht gelaufen, fehlt das informativste
+	// Signal unabhängig vom Rest - das ist ein eigener, spezifischerer Grund
+	// als ein allgemeines "detector_disagreement". Ist er gelaufen, aber
+	// widerspricht er der Computer-Vision-Kategorie stark (siehe
+	// calculateAgreementLevel), UND liegt keine der beiden Kategorien
+	// eindeutig auf einer Seite (Kategorie-Konfidenz unter
+	// AbstainConfidenceFloor), gilt das Ergebnis ebenfalls als nicht
+	// belastbar genug für eines der vier regulären Verdicts.
+	if verdict != "Inconclusive" {
+		thresholds := currentDetectorConfig().Thresholds
+		if computerVisionScore >= 0 && aiAnalysisScore < 0 {
+			verdict = "Inconclusive"
+			abstainReason = AbstainAIModelUnavailable
+		} else if calculateAgreementLevel(computerVisionScore, aiAnalysisScore) == "strong_disagreement" {
+			cvConfidence := math.Abs(computerVisionScore-0.5) * 2
+			aiConfidence := math.Abs(aiAnalysisScore-0.5) * 2
+			if cvConfidence < thresholds.AbstainConfidenceFloor && aiConfidence < thresholds.AbstainConfidenceFloor {
+				verdict = "Inconclusive"
+				abstainReason = AbstainDetectorDisagreement
 			}
 		}
+	}
 
-		baseScore := weightedSum / totalWeight
-		fmt.Printf("\nBASE SCORE: %.3f (weightedSum=%.3f / totalWeight=%.3f)\n",
-			baseScore, weightedSum, totalWeight)
-
-		baseScore *= patternBoost
-		fmt.Printf("AFTER BOOST: %.3f\n", baseScore)
-
-		analysisQuality := float64(len(scores)) / 10.0
-		qualityBonus := 1.0
-		if analysisQuality >= 0.8 {
-			qualityBonus = 1.05
-		} else if analysisQuality < 0.5 {
-			qualityBonus = 0.95
-		}
+	// results.Verdict trägt das typisierte Enum auf das PipelineResult, aus
+	// dem scores stammen - Metrics.RecordVerdict liest es direkt statt den
+	// "verdict"-String unten erneut zu parsen.
+	results.Verdict = pipeline.ParseVerdict(verdict)
 
-		finalScore = baseScore * qualityBonus
-		fmt.Printf("FINAL SCORE: %.3f (quality=%.3f)\n", finalScore, qualityBonus)
+	fmt.Printf("VERDICT: %s (%.1f%%)\n", verdict, finalScore*100)
+	fmt.Printf("==================\n\n")
 
-		// Clamp auf 0-1 Bereich
-		if finalScore > 1.0 {
-			finalScore = 1.0
-		} else if finalScore < 0.0 {
-			finalScore = 0.0
-		}
+	// evidence ersetzt die handgeschriebenen generate*Explanation-Zweige:
+	// jeder Detektor liefert ein Evidence-Objekt, gewichtet mit seinem
+	// Logit-Beitrag aus der Fusion (siehe pkg/analyzer/explain). Die
+	// gerenderte Prosa unten ist nur eine von mehreren Darstellungen
+	// derselben, stabilen "evidence"-Liste.
+	evidence := make([]explain.Evidence, 0, len(calibratedScores))
+	for name, score := range calibratedScores {
+		evidence = append(evidence, explain.Evidence{
+			Detector:  name,
+			Direction: explain.DirectionFor(score),
+			Magnitude: logitContributions[name],
+			Tokens:    explain.TokensFor(name, score, rawResults[name]),
+		})
+	}
 
-		verdict, confidence = determineBalancedVerdict(finalScore, calibratedScores)
+	computerVisionSummary, err := explain.RenderSummary(filterEvidence(evidence, computerVisionMethods), "en")
+	if err != nil {
+		computerVisionSummary = "No computer vision or metadata analysis available"
+	}
+	aiSummary, err := explain.RenderSummary(filterEvidence(evidence, aiMethods), "en")
+	if err != nil {
+		aiSummary = "No AI deep learning analysis available"
 	}
 
-	fmt.Printf("VERDICT: %s (%.1f%%)\n", verdict, finalScore*100)
-	fmt.Printf("==================\n\n")
+	// Bei bekanntem MimeType ist der Nenner die laut Registry für dieses
+	// Format erwartete Detektor-Anzahl statt schlicht "was gelaufen ist" -
+	// sonst würde ein Format mit von vornherein ausgeschlossenen Detektoren
+	// (z.B. "compression" bei PNG) keine niedrigere analysis_quality
+	// bekommen, selbst wenn ein für dieses Format vorgesehener Detektor
+	// tatsächlich fehlgeschlagen ist.
+	totalMethods := len(results.Results)
+	if expected, ok := pipeline.ExpectedDetectorCount(results.MimeType); ok {
+		totalMethods = expected
+	}
+	analysisQuality := calculateAnalysisQuality(totalMethods, len(scores))
+	notifyVerdictQuality(analysisQuality, verdict)
 
 	return map[string]interface{}{
-		"verdict":          verdict,
-		"probability":      finalScore * 100,
-		"confidence":       confidence,
-		"summary":          fmt.Sprintf("%s - %.0f AI points with %.0f%% confidence", verdict, finalScore*100, confidence*100),
-		"reasoning":        reasoning,
-		"scores":           scores,
-		"analysis_quality": calculateAnalysisQuality(len(results.Results), len(scores)),
+		"verdict":     verdict,
+		"probability": finalScore * 100,
+		"confidence":  confidence,
+
+		// CVSS-artige Aufschlüsselung in Base/Temporal/Environmental, damit
+		// nachvollziehbar bleibt, warum die finale probability vom rohen
+		// Detektor-Fusion-Score abweicht - siehe scoring_dimensions.go.
+		"base_score":          baseScore * 100,
+		"temporal_score":      temporalCoeff,
+		"environmental_score": environmentalCoeff,
+		"applied_modifiers":   appliedModifiers,
+
+		// evidence_coverage/abstain_reason begründen ein "Inconclusive"-Verdict
+		// maschinenlesbar (siehe determineBalancedVerdict) - abstain_reason
+		// bleibt bei jedem regulären Verdict leer.
+		"evidence_coverage": evidenceCoverageRatio,
+		"abstain_reason":    abstainReason,
+
+		"summary":                fmt.Sprintf("%s - %.0f AI points with %.0f%% confidence", verdict, finalScore*100, confidence*100),
+		"reasoning":              reasoning,
+		"scores":                 scores,
+		"calibrated_scores":      calibratedScores,
+		"weights_used":           logitContributions,
+		"analysis_quality":       analysisQuality,
+		"analysis_quality_label": analysisQualityLabel(analysisQuality),
+		"evidence":               explain.RankByMagnitude(evidence),
 
 		// NEUE SEPARATE BEWERTUNGEN - NUR NOCH 2 KATEGORIEN
 		"separate_analysis": map[string]interface{}{
@@ -253,14 +300,14 @@ func CalculateOverallVerdict(results *pipeline.PipelineResult) map[string]interf
 				"percentage":  computerVisionScore * 100,
 				"methods":     computerVisionScores,
 				"verdict":     getCategoryVerdict(computerVisionScore),
-				"explanation": generateComputerVisionExplanation(computerVisionScores),
+				"explanation": computerVisionSummary,
 			},
 			"ai_analysis": map[string]interface{}{
 				"score":       aiAnalysisScore,
 				"percentage":  aiAnalysisScore * 100,
 				"methods":     aiScores,
 				"verdict":     getCategoryVerdict(aiAnalysisScore),
-				"explanation": generateAIExplanation(aiScores),
+				"explanation": aiSummary,
 			},
 			"comparison": map[string]interface{}{
 				"cv_vs_ai_difference": calculateDifference(computerVisionScore, aiAnalysisScore),
@@ -270,13 +317,20 @@ func CalculateOverallVerdict(results *pipeline.PipelineResult) map[string]interf
 		},
 
 		"detailed_breakdown": map[string]interface{}{
-			"weighted_scores": calculateWeightedBreakdown(scores, weights),
+			"weighted_scores":     calculateWeightedBreakdown(scores, weights),
+			"logit_contributions": logitContributions, // Pro-Detektor Log-Odds-Beitrag aus fusion.CombineLogOdds - ersetzt die alten CONTRIB-Debug-Prints
 			"method_groups": map[string]interface{}{
 				"computer_vision":  computerVisionScores, // Enthält jetzt auch Metadata
 				"ai_deep_learning": aiScores,
 			},
 			"strength_indicators": analyzeStrengthIndicators(scores),
-			"consistency_check":   checkConsistency(scores),
+			"consistency_check":   checkConsistency(scores, weights, computerVisionScore, aiAnalysisScore),
+			"method_agreement": map[string]interface{}{
+				"domains":            consensusResult.Domains,
+				"consistent":         consensusResult.Consistent,
+				"removals":           consensusResult.Removals,
+				"consensus_strength": consensusResult.ConsensusStrength,
+			},
 		},
 	}
 }
@@ -306,6 +360,27 @@ func calculateAIAnalysisScore(aiScores map[string]float64) float64 {
 	return sum / float64(len(aiScores))
 }
 
+// metadataMethods sind die Scores aus computerVisionScores, die der
+// consensus-Analyse als eigene "metadata"-Kategorie dienen - getrennt von
+// den übrigen Computer-Vision-Methoden, obwohl sie in computerVisionScores
+// für den verdict-Durchschnitt mitgezählt werden.
+var metadataMethods = []string{"metadata", "metadata-structured", "c2pa", "exif", "metadata-quick"}
+
+func calculateMetadataAverageScore(scores map[string]float64) float64 {
+	var sum float64
+	var count int
+	for _, name := range metadataMethods {
+		if score, exists := scores[name]; exists {
+			sum += score
+			count++
+		}
+	}
+	if count == 0 {
+		return -1
+	}
+	return sum / float64(count)
+}
+
 func getCategoryVerdict(score float64) string {
 	if score < 0 {
 		return "No Data"
@@ -325,23 +400,6 @@ func calculateDifference(score1, score2 float64) float64 {
 	return math.Abs(score1 - score2)
 }
 
-func calculateAgreementLevel(cvScore, aiScore float64) string {
-	if cvScore < 0 || aiScore < 0 {
-		return "insufficient_data"
-	}
-
-	diff := math.Abs(cvScore - aiScore)
-	if diff <= 0.1 {
-		return "strong_agreement"
-	} else if diff <= 0.3 {
-		return "moderate_agreement"
-	} else if diff <= 0.5 {
-		return "weak_agreement"
-	} else {
-		return "strong_disagreement"
-	}
-}
-
 func getDominantMethodSimple(cvScore, aiScore float64) string {
 	if cvScore < 0 && aiScore < 0 {
 		return "no_data"
@@ -358,31 +416,18 @@ func getDominantMethodSimple(cvScore, aiScore float64) string {
 	}
 }
 
-func generateComputerVisionExplanation(scores map[string]float64) string {
-	if len(scores) == 0 {
-		return "No computer vision or metadata analysis available"
-	}
-
-	var explanations []string
-	for method, score := range scores {
-		if score >= 0.7 {
-			if method == "metadata" || method == "c2pa" || method == "exif" {
-				explanations = append(explanations, fmt.Sprintf("%s found strong AI markers (%.1f%%)", method, score*100))
-			} else {
-				explanations = append(explanations, fmt.Sprintf("%s indicates strong AI artifacts (%.1f%%)", method, score*100))
-			}
-		} else if score >= 0.3 {
-			explanations = append(explanations, fmt.Sprintf("%s shows mixed signals (%.1f%%)", method, score*100))
-		} else {
-			if method == "metadata" || method == "c2pa" || method == "exif" {
-				explanations = append(explanations, fmt.Sprintf("%s found clean metadata (%.1f%%)", method, score*100))
-			} else {
-				explanations = append(explanations, fmt.Sprintf("%s suggests human origin (%.1f%%)", method, score*100))
-			}
+// filterEvidence liefert die Teilmenge von evidence, deren Detector-Name in
+// methods vorkommt - Grundlage für die pro-Kategorie gerenderten
+// explain.RenderSummary-Aufrufe in CalculateOverallVerdict, die die früheren
+// generateComputerVisionExplanation/generateAIExplanation ersetzen.
+func filterEvidence(evidence []explain.Evidence, methods []string) []explain.Evidence {
+	filtered := make([]explain.Evidence, 0, len(evidence))
+	for _, e := range evidence {
+		if contains(methods, e.Detector) {
+			filtered = append(filtered, e)
 		}
 	}
-
-	return strings.Join(explanations, "; ")
+	return filtered
 }
 
 // Bestehende Helper-Funktionen
@@ -402,16 +447,46 @@ func calculateAnalysisQuality(totalMethods, successfulMethods int) float64 {
 	return float64(successfulMethods) / float64(totalMethods)
 }
 
+// analysisQualityLabel ordnet ratio anhand der QualityBands der aktiven
+// DetectorConfig (siehe detector_config.go) in ein Label ein - vormals
+// hartkodierte 0.8/0.5-Bänder.
+func analysisQualityLabel(ratio float64) string {
+	bands := currentDetectorConfig().QualityBands
+	if ratio >= bands.HighThreshold {
+		return "high"
+	} else if ratio >= bands.LowThreshold {
+		return "medium"
+	}
+	return "low"
+}
+
 func calculateWeightedBreakdown(scores map[string]float64, weights map[string]float64) map[string]float64 {
 	breakdown := make(map[string]float64)
 	for name, score := range scores {
-		if weight, exists := weights[name]; exists {
-			breakdown[name] = score * weight
+		if _, exists := weights[name]; exists {
+			breakdown[name] = score * effectiveDetectorWeight(name, weights[name], score)
 		}
 	}
 	return breakdown
 }
 
+// effectiveDetectorWeight liefert das Gewicht, mit dem Detektor name in die
+// weighted_scores-Aufschlüsselung eingeht: existiert ein eingebettetes
+// pkg/verdict/reliability-Modell für name, ersetzt dessen an score gemessene
+// Informativität den statischen staticWeight aus DetectorConfig.
+// staticWeight == 0 (Operator hat den Detektor per detector.yaml
+// deaktiviert) bleibt davon unberührt - Reliability kann die Magnitude
+// eines Gewichts anpassen, aber keinen manuellen Aus-Schalter überstimmen.
+func effectiveDetectorWeight(name string, staticWeight float64, score float64) float64 {
+	if staticWeight == 0 {
+		return 0
+	}
+	if reliability.HasModel(name) {
+		return reliability.Weight(name, score)
+	}
+	return staticWeight
+}
+
 func analyzeStrengthIndicators(scores map[string]float64) []string {
 	indicators := []string{}
 
@@ -426,67 +501,158 @@ func analyzeStrengthIndicators(scores map[string]float64) []string {
 	return indicators
 }
 
-func checkConsistency(scores map[string]float64) map[string]interface{} {
-	if len(scores) < 2 {
-		return map[string]interface{}{
-			"level":      "insufficient_data",
-			"variance":   0.0,
-			"assessment": "Need more methods for consistency check",
-		}
+// computeVerdict kapselt den eigentlichen Kalibrierungs-/Fusions-
+// /Verdict-Schritt aus CalculateOverallVerdict, damit er auch außerhalb
+// einer frischen Pipeline-Ausführung wiederverwendet werden kann - etwa von
+// RescoreFromRawScores, das gespeicherte Rohscores gegen ein neues
+// Kalibrierungsprofil neu bewertet, ohne die Analyzer erneut laufen zu
+// lassen. Solange DetectorConfig.UseBayesFusion deaktiviert ist (Default),
+// bleiben die "definitive metadata/C2PA"-Zweige als harte Overrides vor der
+// Fusion bestehen, und fusion.CombineLogOdds kombiniert die kalibrierten
+// Wahrscheinlichkeiten per naive-Bayes-artiger Log-Odds-Fusion -
+// contributions ist der auditierbare Logit-Beitrag jedes Detektors, der die
+// alten CONTRIB-Debug-Prints ersetzt. Ist das Flag aktiv, übernimmt
+// stattdessen pkg/verdict/bayes die komplette Fusion inklusive
+// metadata/C2PA, die dort als starke statt als definitive Likelihood in die
+// Posterior einfließen (siehe bayesVerdict unten). ok=false bedeutet, dass
+// kein Score ein Gewicht > 0 hatte.
+func computeVerdict(scores map[string]float64, weights map[string]float64) (finalScore float64, verdictLabel string, confidence float64, calibratedScores map[string]float64, contributions map[string]float64, reasoning []string, ok bool) {
+	reasoning = []string{}
+	contributions = map[string]float64{}
+
+	if currentDetectorConfig().UseBayesFusion {
+		return bayesVerdict(scores, weights)
 	}
 
-	// Berechne Varianz
-	var sum float64
-	for _, score := range scores {
-		sum += score
+	if metaScore, exists := scores["metadata"]; exists && metaScore >= 0.95 {
+		reasoning = append(reasoning, "Definitive AI metadata found")
+		return 1.0, "AI Generated", 0.95, applyBalancedCalibration(scores), contributions, reasoning, true
 	}
-	mean := sum / float64(len(scores))
-
-	var variance float64
-	for _, score := range scores {
-		diff := score - mean
-		variance += diff * diff
+	if c2paScore, exists := scores["c2pa"]; exists && c2paScore >= 0.95 {
+		reasoning = append(reasoning, "C2PA certificate confirms AI generation")
+		return 1.0, "AI Generated", 0.95, applyBalancedCalibration(scores), contributions, reasoning, true
 	}
-	variance /= float64(len(scores))
 
-	var level string
-	var assessment string
+	calibratedScores = applyBalancedCalibration(scores)
 
-	if variance <= 0.1 {
-		level = "high"
-		assessment = "Methods show strong agreement"
-	} else if variance <= 0.3 {
-		level = "moderate"
-		assessment = "Methods show reasonable consistency"
-	} else {
-		level = "low"
-		assessment = "Methods show significant disagreement"
+	fusionInput := make(map[string]float64)
+	for name, score := range calibratedScores {
+		if weights[name] == 0 {
+			continue
+		}
+		if name == "ai-model" {
+			continue // Skip AI-Model für finalScore - separat unter ai_analysis ausgewiesen
+		}
+		fusionInput[name] = score
 	}
 
-	return map[string]interface{}{
-		"level":      level,
-		"variance":   variance,
-		"mean":       mean,
-		"assessment": assessment,
+	if len(fusionInput) == 0 {
+		return 0, "", 0, calibratedScores, contributions, reasoning, false
 	}
+
+	finalScore, contributions = fusion.CombineLogOdds(fusionInput)
+
+	for name := range fusionInput {
+		score := calibratedScores[name]
+		if score >= 0.7 {
+			reasoning = append(reasoning, fmt.Sprintf("%s: Strong AI indicators (%.0f%% probability)", name, score*100))
+		} else if score <= 0.3 {
+			reasoning = append(reasoning, fmt.Sprintf("%s: Authenticity indicators (%.0f%% authentic)", name, (1-score)*100))
+		} else {
+			reasoning = append(reasoning, fmt.Sprintf("%s: Moderate signals (%.0f%% probability)", name, score*100))
+		}
+	}
+
+	// Für die evidence_coverage-Prüfung in determineBalancedVerdict zählt die
+	// tatsächliche Detektor-Gewichtung aus der DetectorConfig, nicht das hier
+	// übergebene weights - RescoreFromRawScores ruft computeVerdict z.B. mit
+	// record.WeightsUsed auf, das trotz seines Namens die Logit-Beiträge aus
+	// fusion.CombineLogOdds sind (siehe "weights_used" unten), nicht die
+	// konfigurierten Gewichte, und wäre als evidence_coverage-Nenner/Zähler
+	// bedeutungslos.
+	verdictLabel, confidence, _, _ = determineBalancedVerdict(finalScore, calibratedScores, currentDetectorConfig().DetectorWeights)
+	return finalScore, verdictLabel, confidence, calibratedScores, contributions, reasoning, true
 }
 
-func generateAIExplanation(scores map[string]float64) string {
-	if len(scores) == 0 {
-		return "No AI deep learning analysis available"
+// bayesVerdict ersetzt den Log-Odds-Pfad von computeVerdict durch
+// pkg/verdict/bayes, sobald DetectorConfig.UseBayesFusion aktiv ist. Anders
+// als die >= 0.95 Kurzschluss-Zweige oben fließen metadata/c2pa hier nur
+// als starke (aber nicht automatisch verdict-entscheidende) Likelihood in
+// die Posterior ein - ein sehr hoher metadata-Score kann durch genügend
+// gegenteilige Detektor-Evidenz theoretisch noch überstimmt werden, statt
+// das Verdict unabhängig von allen anderen Signalen festzuschreiben.
+func bayesVerdict(scores map[string]float64, weights map[string]float64) (finalScore float64, verdictLabel string, confidence float64, calibratedScores map[string]float64, contributions map[string]float64, reasoning []string, ok bool) {
+	reasoning = []string{}
+	contributions = map[string]float64{}
+	calibratedScores = applyBalancedCalibration(scores)
+
+	fusionInput := make(map[string]float64)
+	for name, score := range calibratedScores {
+		if weights[name] == 0 {
+			continue
+		}
+		if name == "ai-model" {
+			continue // Skip AI-Model für finalScore - separat unter ai_analysis ausgewiesen
+		}
+		fusionInput[name] = score
 	}
 
-	for _, score := range scores {
-		if score >= 0.9 {
-			return fmt.Sprintf("Neural network strongly predicts AI-generated (%.1f%% confidence). Very high certainty from deep learning model.", score*100)
-		} else if score >= 0.7 {
-			return fmt.Sprintf("Neural network indicates likely AI-generated (%.1f%% confidence). Strong AI detection signals.", score*100)
-		} else if score >= 0.3 {
-			return fmt.Sprintf("Neural network shows mixed results (%.1f%% confidence). Uncertain classification from AI model.", score*100)
+	if len(fusionInput) == 0 {
+		return 0, "", 0, calibratedScores, contributions, reasoning, false
+	}
+
+	posterior, bayesConfidence := bayesModel.Combine(fusionInput)
+	finalScore = posterior
+
+	for name, score := range fusionInput {
+		contributions[name] = score
+		if score >= 0.7 {
+			reasoning = append(reasoning, fmt.Sprintf("%s: Strong AI indicators (%.0f%% probability)", name, score*100))
+		} else if score <= 0.3 {
+			reasoning = append(reasoning, fmt.Sprintf("%s: Authenticity indicators (%.0f%% authentic)", name, (1-score)*100))
 		} else {
-			return fmt.Sprintf("Neural network suggests human origin (%.1f%% authentic). Low AI detection confidence.", (1-score)*100)
+			reasoning = append(reasoning, fmt.Sprintf("%s: Moderate signals (%.0f%% probability)", name, score*100))
 		}
 	}
 
-	return "AI deep learning analysis completed"
+	// Siehe computeVerdict oben: evidence_coverage muss gegen die echte
+	// DetectorConfig laufen, nicht gegen das hier übergebene weights.
+	verdictLabel, confidenceFromThresholds, _, _ := determineBalancedVerdict(finalScore, calibratedScores, currentDetectorConfig().DetectorWeights)
+	confidence = math.Max(confidenceFromThresholds, bayesConfidence)
+	return finalScore, verdictLabel, confidence, calibratedScores, contributions, reasoning, true
+}
+
+// RescoreFromRawScores wendet das aktuell aktive Kalibrierungsprofil (siehe
+// calibration_config.go) auf zuvor gespeicherte Rohscores an, ohne die
+// Analyzer erneut auszuführen. Wird vom History-Rescoring-Job verwendet,
+// wenn Operator das Kalibrierungsprofil ändern und wissen wollen, wie sich
+// das auf vergangene Verdicts ausgewirkt hätte.
+func RescoreFromRawScores(rawScores map[string]float64, weights map[string]float64) (map[string]interface{}, error) {
+	finalScore, verdictLabel, confidence, calibratedScores, usedWeights, reasoning, ok := computeVerdict(rawScores, weights)
+	if !ok {
+		return nil, fmt.Errorf("no usable scores with non-zero weight")
+	}
+
+	return map[string]interface{}{
+		"verdict":           verdictLabel,
+		"probability":       finalScore * 100,
+		"confidence":        confidence,
+		"reasoning":         reasoning,
+		"scores":            rawScores,
+		"weights_used":      usedWeights,
+		"calibrated_scores": calibratedScores,
+	}, nil
+}
+
+// OnVerdictQuality wird, falls gesetzt, nach jedem in CalculateOverallVerdict
+// berechneten Verdict mit dem analysis_quality-Verhältnis und dem Verdict-
+// Label aufgerufen - analog zu OnCalibrationReload in calibration_config.go.
+// Verdraht von handlers.RegisterHandlers an das alerts-Paket, damit dieses
+// Paket weder monitoring noch alerts importieren muss.
+var OnVerdictQuality func(quality float64, verdictLabel string)
+
+func notifyVerdictQuality(quality float64, verdictLabel string) {
+	if OnVerdictQuality != nil {
+		OnVerdictQuality(quality, verdictLabel)
+	}
 }
@@ -1,33 +1,39 @@
 package verdict
 
-import "math"
-
+import (
+	"math"
+
+	"github.com/BramseDev/imageAnalyzer/pkg/analyzer/calibration"
+	"github.com/BramseDev/imageAnalyzer/pkg/verdict/reliability"
+)
+
+// applyBalancedCalibration kalibriert jeden Rohscore in der folgenden
+// Rangfolge: zuerst ein gelernter Per-Detektor-Calibrator (siehe
+// pkg/analyzer/calibration - Platt/Isotonic/Temperature Scaling, gefittet
+// von cmd/fit-calibrators gegen annotierte Ground-Truth-Daten); liegt dafür
+// keine gefittete Datei vor, die eingebetteten Platt-Koeffizienten aus
+// pkg/verdict/reliability (siehe cmd/train-reliability); existiert auch
+// dafür kein Fit, der statische Faktor aus dem aktiven CalibrationProfile
+// (siehe calibration_config.go / config/calibration.yaml); fehlt auch der,
+// bleibt der Score unverändert.
 func applyBalancedCalibration(scores map[string]float64) map[string]float64 {
-	calibratedScores := make(map[string]float64)
-
-	calibrationFactors := map[string]float64{
-		// Boost the working methods even more
-		"compression":        1.0,
-		"artifacts":          1.0,
-		"pixel-analysis":     1.4,
-		"lighting-analysis":  1.5,
-		"color-balance":      1.4,
-		"advanced-artifacts": 1.0,
+	learned := calibration.CalibrateAll(scores)
+	profile := currentCalibration()
 
-		"c2pa":           0.9,
-		"exif":           0.85,
-		"metadata":       1.0,
-		"metadata-quick": 0.8,
-
-		"object-coherence": 0.8,
+	calibratedScores := make(map[string]float64)
+	for name, score := range scores {
+		if calibration.HasFit(name) {
+			calibratedScores[name] = learned[name]
+			continue
+		}
 
-		"ai-model": 1.2,
-	}
+		if calibrated, ok := reliability.Calibrate(name, score); ok {
+			calibratedScores[name] = calibrated
+			continue
+		}
 
-	for name, score := range scores {
-		if factor, exists := calibrationFactors[name]; exists {
-			calibratedScore := score * factor
-			calibratedScores[name] = math.Min(1.0, calibratedScore)
+		if factor, exists := profile.Factors[name]; exists {
+			calibratedScores[name] = math.Min(1.0, score*factor)
 		} else {
 			calibratedScores[name] = score // FALLBACK für fehlende Faktoren
 		}
@@ -36,6 +42,10 @@ func applyBalancedCalibration(scores map[string]float64) map[string]float64 {
 	return calibratedScores
 }
 
+// applyDynamicWeights wendet die bedingten Regeln aus dem aktiven
+// CalibrationProfile auf die Basisgewichte an. Mehrere Regeln für denselben
+// Analyzer werden der Reihe nach angewendet, genau wie die vorher
+// hartkodierten if/else-Ketten.
 func applyDynamicWeights(weights map[string]float64, scores map[string]float64) map[string]float64 {
 	adjustedWeights := make(map[string]float64)
 
@@ -44,32 +54,18 @@ func applyDynamicWeights(weights map[string]float64, scores map[string]float64)
 		adjustedWeights[method] = weight
 	}
 
-	if exifScore, exists := scores["exif"]; exists {
-		if exifScore >= 0.8 {
-			adjustedWeights["exif"] *= 1.4
-		} else if exifScore <= 0.2 {
-			adjustedWeights["exif"] *= 1.3
+	profile := currentCalibration()
+	for _, rule := range profile.Rules {
+		score, exists := scores[rule.Analyzer]
+		if !exists {
+			continue
 		}
-	}
-
-	if colorScore, exists := scores["color-balance"]; exists {
-		if colorScore >= 0.7 {
-			adjustedWeights["color-balance"] *= 1.3
-		} else if colorScore <= 0.3 {
-			adjustedWeights["color-balance"] *= 1.2
+		if _, hasWeight := adjustedWeights[rule.Analyzer]; !hasWeight {
+			continue
+		}
+		if evaluateRule(rule.Op, score, rule.Threshold) {
+			adjustedWeights[rule.Analyzer] *= rule.Multiplier
 		}
-	}
-
-	if lightingScore, exists := scores["lighting-analysis"]; exists && lightingScore >= 0.6 {
-		adjustedWeights["lighting-analysis"] *= 1.3
-	}
-
-	if compressionScore, exists := scores["compression"]; exists && compressionScore >= 0.4 {
-		adjustedWeights["compression"] *= 0.5
-	}
-
-	if aiScore, exists := scores["ai-model"]; exists && aiScore >= 0 {
-		adjustedWeights["ai-model"] *= 1.5
 	}
 
 	return adjustedWeights
@@ -0,0 +1,330 @@
+package verdict
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// CalibrationRule ist eine bedingte Gewichtsanpassung: wird der kalibrierte
+// Score von Analyzer mit Op gegen Threshold erfuellt, wird das Gewicht des
+// Analyzers mit Multiplier multipliziert.
+type CalibrationRule struct {
+	Analyzer   string
+	Op         string
+	Threshold  float64
+	Multiplier float64
+}
+
+// CalibrationProfile fasst die externe Kalibrierungskonfiguration zusammen -
+// ersetzt die frueher hartkodierten Faktoren/Schwellwerte in
+// applyBalancedCalibration und applyDynamicWeights.
+type CalibrationProfile struct {
+	Factors map[string]float64
+	Rules   []CalibrationRule
+}
+
+// defaultCalibrationPath ist der Pfad, der geladen wird, wenn kein anderer
+// über SetCalibrationConfigPath gesetzt wurde. Kann per Umgebungsvariable
+// überschrieben werden, damit A/B-Deployments unterschiedliche Profile
+// nebeneinander fahren können.
+const defaultCalibrationPath = "config/calibration.yaml"
+
+var (
+	calibrationMu     sync.RWMutex
+	calibrationPath   = defaultCalibrationPath
+	activeCalibration = defaultCalibrationProfile()
+	lastCalibrationAt time.Time
+	lastCalibrationErr error
+	watchOnce         sync.Once
+)
+
+func defaultCalibrationProfile() CalibrationProfile {
+	return CalibrationProfile{
+		Factors: map[string]float64{
+			"compression":        1.0,
+			"artifacts":          1.0,
+			"pixel-analysis":     1.4,
+			"lighting-analysis":  1.5,
+			"color-balance":      1.4,
+			"advanced-artifacts": 1.0,
+			"c2pa":               0.9,
+			"exif":               0.85,
+			"metadata":           1.0,
+			"metadata-quick":     0.8,
+			"object-coherence":   0.8,
+			"ai-model":           1.2,
+		},
+		Rules: []CalibrationRule{
+			{Analyzer: "exif", Op: ">=", Threshold: 0.8, Multiplier: 1.4},
+			{Analyzer: "exif", Op: "<=", Threshold: 0.2, Multiplier: 1.3},
+			{Analyzer: "color-balance", Op: ">=", Threshold: 0.7, Multiplier: 1.3},
+			{Analyzer: "color-balance", Op: "<=", Threshold: 0.3, Multiplier: 1.2},
+			{Analyzer: "lighting-analysis", Op: ">=", Threshold: 0.6, Multiplier: 1.3},
+			{Analyzer: "compression", Op: ">=", Threshold: 0.4, Multiplier: 0.5},
+			{Analyzer: "ai-model", Op: ">=", Threshold: 0.0, Multiplier: 1.5},
+		},
+	}
+}
+
+// InitCalibrationConfig lädt das Kalibrierungsprofil von path (leer = Default
+// aus defaultCalibrationPath), registriert einen SIGHUP-Handler und startet
+// ein Polling, das die Datei bei Änderungen automatisch neu einliest. Wird
+// path nicht gefunden, bleibt das eingebaute Default-Profil aktiv - ein
+// fehlendes Config-File ist kein Fehler, nur ein Hinweis, dass Operator noch
+// nichts angepasst haben.
+func InitCalibrationConfig(path string) {
+	calibrationMu.Lock()
+	if path != "" {
+		calibrationPath = path
+	}
+	calibrationMu.Unlock()
+
+	reloadCalibrationConfig()
+
+	watchOnce.Do(func() {
+		sighup := make(chan os.Signal, 1)
+		signal.Notify(sighup, syscall.SIGHUP)
+		go func() {
+			for range sighup {
+				fmt.Println("SIGHUP empfangen, lade Kalibrierungsprofil neu:", calibrationPath)
+				reloadCalibrationConfig()
+			}
+		}()
+
+		go watchCalibrationFile()
+	})
+}
+
+func watchCalibrationFile() {
+	var lastMod time.Time
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		calibrationMu.RLock()
+		path := calibrationPath
+		calibrationMu.RUnlock()
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(lastMod) {
+			lastMod = info.ModTime()
+			if !lastMod.IsZero() {
+				reloadCalibrationConfig()
+			} else {
+				lastMod = info.ModTime()
+			}
+		}
+	}
+}
+
+func reloadCalibrationConfig() {
+	calibrationMu.RLock()
+	path := calibrationPath
+	calibrationMu.RUnlock()
+
+	profile, err := loadCalibrationProfile(path)
+
+	calibrationMu.Lock()
+	defer calibrationMu.Unlock()
+	lastCalibrationAt = time.Now()
+	if err != nil {
+		lastCalibrationErr = err
+		fmt.Printf("WARNING: Kalibrierungsprofil %s konnte nicht geladen werden (%v), behalte aktives Profil\n", path, err)
+		return
+	}
+	lastCalibrationErr = nil
+	activeCalibration = profile
+
+	if OnCalibrationReload != nil {
+		go OnCalibrationReload()
+	}
+}
+
+// OnCalibrationReload wird, falls gesetzt, nach jedem erfolgreichen
+// Kalibrierungs-Reload in einer eigenen Goroutine aufgerufen. Der
+// Verdict-Store registriert hier seinen Rescore-Hintergrundjob, der alle
+// gespeicherten Rohscores gegen das neue Profil neu bewertet - ohne dass
+// dieses Paket eine Abhängigkeit auf pkg/verdictstore braucht.
+var OnCalibrationReload func()
+
+func currentCalibration() CalibrationProfile {
+	calibrationMu.RLock()
+	defer calibrationMu.RUnlock()
+	return activeCalibration
+}
+
+// CalibrationStatus wird vom /dashboard/calibration Endpoint ausgeliefert.
+type CalibrationStatus struct {
+	Path        string            `json:"path"`
+	LastReload  time.Time         `json:"last_reload"`
+	LastError   string            `json:"last_error,omitempty"`
+	Factors     map[string]float64 `json:"factors"`
+	Rules       []CalibrationRule `json:"rules"`
+}
+
+// GetCalibrationStatus liefert das aktuell aktive Profil samt Zeitpunkt des
+// letzten (erfolgreichen oder fehlgeschlagenen) Reload-Versuchs.
+func GetCalibrationStatus() CalibrationStatus {
+	calibrationMu.RLock()
+	defer calibrationMu.RUnlock()
+
+	errMsg := ""
+	if lastCalibrationErr != nil {
+		errMsg = lastCalibrationErr.Error()
+	}
+
+	return CalibrationStatus{
+		Path:       calibrationPath,
+		LastReload: lastCalibrationAt,
+		LastError:  errMsg,
+		Factors:    activeCalibration.Factors,
+		Rules:      activeCalibration.Rules,
+	}
+}
+
+// loadCalibrationProfile parst das minimalistische YAML-Subset, das
+// calibration.yaml verwendet (zwei flache Abschnitte: factors als
+// "key: value"-Paare, rules als Liste von Objekten). Ein voller YAML-Parser
+// ist hierfür nicht nötig und würde eine externe Abhängigkeit erfordern, die
+// dieses Repo (ohne go.mod/Vendoring) nicht handhaben kann.
+func loadCalibrationProfile(path string) (CalibrationProfile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return CalibrationProfile{}, err
+	}
+	defer f.Close()
+
+	profile := CalibrationProfile{Factors: map[string]float64{}}
+
+	section := ""
+	var currentRule *CalibrationRule
+
+	flushRule := func() {
+		if currentRule != nil {
+			profile.Rules = append(profile.Rules, *currentRule)
+			currentRule = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		rawLine := scanner.Text()
+		line := strings.TrimRight(rawLine, " \t")
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if trimmed == "factors:" {
+			flushRule()
+			section = "factors"
+			continue
+		}
+		if trimmed == "rules:" {
+			flushRule()
+			section = "rules"
+			continue
+		}
+
+		switch section {
+		case "factors":
+			key, value, ok := splitYAMLKeyValue(trimmed)
+			if !ok {
+				continue
+			}
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return CalibrationProfile{}, fmt.Errorf("invalid factor value for %q: %w", key, err)
+			}
+			profile.Factors[key] = f
+
+		case "rules":
+			item := trimmed
+			if strings.HasPrefix(item, "- ") {
+				flushRule()
+				currentRule = &CalibrationRule{}
+				item = strings.TrimPrefix(item, "- ")
+			}
+			if currentRule == nil {
+				continue
+			}
+			key, value, ok := splitYAMLKeyValue(item)
+			if !ok {
+				continue
+			}
+			switch key {
+			case "analyzer":
+				currentRule.Analyzer = value
+			case "op":
+				currentRule.Op = value
+			case "threshold":
+				v, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return CalibrationProfile{}, fmt.Errorf("invalid rule threshold: %w", err)
+				}
+				currentRule.Threshold = v
+			case "multiplier":
+				v, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return CalibrationProfile{}, fmt.Errorf("invalid rule multiplier: %w", err)
+				}
+				currentRule.Multiplier = v
+			}
+		}
+	}
+	flushRule()
+
+	if err := scanner.Err(); err != nil {
+		return CalibrationProfile{}, err
+	}
+
+	if len(profile.Factors) == 0 {
+		return CalibrationProfile{}, fmt.Errorf("calibration profile %s has no factors", path)
+	}
+
+	return profile, nil
+}
+
+// splitYAMLKeyValue zerlegt "key: value" bzw. "key: \"value\"" in Schlüssel
+// und unquotedem Wert.
+func splitYAMLKeyValue(line string) (string, string, bool) {
+	idx := strings.Index(line, ":")
+	if idx < 0 {
+		return "", "", false
+	}
+	key := strings.TrimSpace(line[:idx])
+	value := strings.TrimSpace(line[idx+1:])
+	value = strings.Trim(value, `"`)
+	if key == "" || value == "" {
+		return "", "", false
+	}
+	return key, value, true
+}
+
+func evaluateRule(op string, score, threshold float64) bool {
+	switch op {
+	case ">=":
+		return score >= threshold
+	case "<=":
+		return score <= threshold
+	case ">":
+		return score > threshold
+	case "<":
+		return score < threshold
+	case "==":
+		return score == threshold
+	default:
+		return false
+	}
+}
@@ -0,0 +1,189 @@
+package verdict
+
+import (
+	"math"
+
+	pkgverdict "github.com/BramseDev/imageAnalyzer/pkg/verdict"
+)
+
+// weightFor liefert das Gewicht von name aus weights, oder das neutrale
+// Default-Gewicht 1.0, falls weights nil ist oder keinen (oder einen
+// deaktivierenden 0-) Eintrag für name hat - analog zu fusion.weightFor.
+func weightFor(weights map[string]float64, name string) float64 {
+	if w, exists := weights[name]; exists && w != 0 {
+		return w
+	}
+	return 1.0
+}
+
+// weightedMeanVariance berechnet Mittelwert und Varianz von scores,
+// gewichtet mit derselben weights-Map, die computeVerdict für die Fusion
+// verwendet - ein Detektor mit hohem Gewicht zieht den Mittelwert stärker
+// zu seinem eigenen Score, statt dass jeder Detektor unabhängig von seiner
+// Bedeutung für den Verdict gleich stark zählt.
+func weightedMeanVariance(scores map[string]float64, weights map[string]float64) (mean float64, variance float64) {
+	var weightSum float64
+	for name, score := range scores {
+		w := weightFor(weights, name)
+		mean += w * score
+		weightSum += w
+	}
+	if weightSum == 0 {
+		return 0, 0
+	}
+	mean /= weightSum
+
+	for name, score := range scores {
+		w := weightFor(weights, name)
+		diff := score - mean
+		variance += w * diff * diff
+	}
+	variance /= weightSum
+
+	return mean, variance
+}
+
+// circularDispersion behandelt jeden Score in scores als Winkel auf einem
+// Halbkreis: 0 (eindeutig authentisch) und 1 (eindeutig AI) liegen sich
+// dabei bei den Winkeln 0 und π gegenüber, 0.5 (unentschieden) liegt genau
+// dazwischen bei π/2. Eine lineare Varianz mittelt zwei Detektoren bei 0.05
+// und 0.95 auf den "unentschiedenen" Wert 0.5 und erscheint damit wie eine
+// moderate statt einer maximalen Uneinigkeit - der gewichtete
+// Resultierenden-Vektor dieser Winkel dagegen hat für genau dieses Paar
+// eine Länge nahe 0, weil sich die beiden Vektoren auf dem Halbkreis fast
+// aufheben. Das Ergebnis ist 1 minus dieser Resultierenden-Länge, damit
+// hohe Werte wie bei weightedMeanVariance "mehr Uneinigkeit" bedeuten.
+func circularDispersion(scores map[string]float64, weights map[string]float64) float64 {
+	var sumX, sumY, weightSum float64
+	for name, score := range scores {
+		w := weightFor(weights, name)
+		angle := score * math.Pi
+		sumX += w * math.Cos(angle)
+		sumY += w * math.Sin(angle)
+		weightSum += w
+	}
+	if weightSum == 0 {
+		return 0
+	}
+
+	meanX := sumX / weightSum
+	meanY := sumY / weightSum
+	resultantLength := math.Hypot(meanX, meanY)
+
+	return 1 - resultantLength
+}
+
+// pearsonCorrelation berechnet den Pearson-Korrelationskoeffizienten
+// zwischen dem computer_vision- und dem ai_model-Score über obs - genutzt
+// von checkConsistency, um zu beobachten, ob beide über mehrere Analysen
+// hinweg tendenziell übereinstimmen, statt nur innerhalb eines einzelnen
+// Laufs verglichen zu werden. Liefert 0 bei weniger als 2 Beobachtungen
+// oder wenn eine der beiden Reihen noch keine Varianz hat (z.B. zu wenige
+// unterschiedliche Analysen im Fenster).
+func pearsonCorrelation(obs []pkgverdict.Observation) float64 {
+	n := float64(len(obs))
+	if n < 2 {
+		return 0
+	}
+
+	var sumCV, sumAI float64
+	for _, o := range obs {
+		sumCV += o.ComputerVision
+		sumAI += o.AIModel
+	}
+	meanCV := sumCV / n
+	meanAI := sumAI / n
+
+	var covariance, varCV, varAI float64
+	for _, o := range obs {
+		dCV := o.ComputerVision - meanCV
+		dAI := o.AIModel - meanAI
+		covariance += dCV * dAI
+		varCV += dCV * dCV
+		varAI += dAI * dAI
+	}
+	if varCV == 0 || varAI == 0 {
+		return 0
+	}
+
+	return covariance / math.Sqrt(varCV*varAI)
+}
+
+// checkConsistency bewertet, wie einig sich scores innerhalb dieses einen
+// Analyse-Laufs sind (weightedMeanVariance, circularDispersion) und, über
+// pkgverdict.DefaultHistory, wie gut computer_vision- und ai_model-Score
+// über die letzten pkgverdict.HistoryWindow Analysen hinweg korrelieren
+// (pearsonCorrelation). cvScore/aiScore < 0 (keine Daten für eine der
+// beiden Kategorien) werden nicht in die History aufgenommen, aber die
+// Intra-Run-Statistik über scores bleibt trotzdem verfügbar. level/
+// assessment bleiben als Strings erhalten, die übrigen Felder sind die
+// numerischen Werte, mit denen Aufrufer einen Trend plotten können.
+func checkConsistency(scores map[string]float64, weights map[string]float64, cvScore, aiScore float64) map[string]interface{} {
+	if len(scores) < 2 {
+		return map[string]interface{}{
+			"level":               "insufficient_data",
+			"weighted_variance":   0.0,
+			"circular_dispersion": 0.0,
+			"rolling_correlation": 0.0,
+			"assessment":          "Need more methods for consistency check",
+		}
+	}
+
+	mean, variance := weightedMeanVariance(scores, weights)
+	dispersion := circularDispersion(scores, weights)
+
+	if cvScore >= 0 && aiScore >= 0 {
+		pkgverdict.DefaultHistory.Add(pkgverdict.Observation{ComputerVision: cvScore, AIModel: aiScore})
+	}
+	rollingObs := pkgverdict.DefaultHistory.Snapshot()
+	correlation := pearsonCorrelation(rollingObs)
+
+	// dispersion dominiert die Einstufung, sobald sie hoch ist - eine
+	// niedrige lineare Varianz bei hoher Circular-Dispersion ist genau der
+	// Fall (Scores nahe 0 und nahe 1), den die lineare Varianz allein
+	// verschleiert.
+	combined := math.Max(variance, dispersion)
+
+	var level, assessment string
+	switch {
+	case combined <= 0.1:
+		level, assessment = "high", "Methods show strong agreement"
+	case combined <= 0.3:
+		level, assessment = "moderate", "Methods show reasonable consistency"
+	default:
+		level, assessment = "low", "Methods show significant disagreement"
+	}
+
+	return map[string]interface{}{
+		"level":               level,
+		"weighted_mean":       mean,
+		"weighted_variance":   variance,
+		"circular_dispersion": dispersion,
+		"rolling_correlation": correlation,
+		"rolling_window_size": len(rollingObs),
+		"assessment":          assessment,
+	}
+}
+
+// calculateAgreementLevel stuft das Zusammenspiel von cvScore und aiScore
+// über deren circularDispersion ein (siehe dort) statt über ihre simple
+// lineare Differenz - zwei bei 0.05 und 0.95 eindeutig, aber gegenläufig
+// urteilende Scores liegen sich auf dem Halbkreis fast gegenüber und gelten
+// damit als starker statt als bloß "mittlerer" Widerspruch.
+func calculateAgreementLevel(cvScore, aiScore float64) string {
+	if cvScore < 0 || aiScore < 0 {
+		return "insufficient_data"
+	}
+
+	dispersion := circularDispersion(map[string]float64{"cv": cvScore, "ai": aiScore}, nil)
+	switch {
+	case dispersion <= 0.05:
+		return "strong_agreement"
+	case dispersion <= 0.25:
+		return "moderate_agreement"
+	case dispersion <= 0.55:
+		return "weak_agreement"
+	default:
+		return "strong_disagreement"
+	}
+}
@@ -0,0 +1,403 @@
+package verdict
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// VerdictThresholds sind die Score-Schwellen und Confidence-Deckel, die
+// determineBalancedVerdict zur binären Einordnung verwendet - vormals
+// hartkodierte Konstanten (0.75/0.60/0.59/0.40 etc.) in determination.go.
+type VerdictThresholds struct {
+	AIGenerated            float64
+	LikelyAIGenerated      float64
+	LikelyAuthentic        float64
+	SmallSampleAdjustment  float64
+	SmallSampleCutoff      int
+	ConfidenceCapAI        float64
+	ConfidenceCapLikely    float64
+	ConfidenceCapAuthentic float64
+
+	// MinEvidenceCoverage ist der Anteil des insgesamt möglichen
+	// Detektor-Gewichts (Summe aller DetectorWeights-Einträge mit Gewicht
+	// != 0), der tatsächlich einen Score geliefert haben muss - fällt die
+	// evidence_coverage darunter, liefert determineBalancedVerdict
+	// "Inconclusive" statt eines der vier regulären Verdicts.
+	MinEvidenceCoverage float64
+
+	// AbstainConfidenceFloor ist die Schwelle für die
+	// Kategorie-Konfidenz (|score-0.5|*2) von computer_vision- und
+	// ai_model-Score, unterhalb derer ein "strong_disagreement" zwischen
+	// beiden (siehe calculateAgreementLevel) ebenfalls zu "Inconclusive"
+	// statt eines der vier regulären Verdicts führt.
+	AbstainConfidenceFloor float64
+}
+
+// QualityBands sind die Bänder, über die calculateAnalysisQuality sein
+// Roh-Verhältnis (erfolgreiche / erwartete Methoden) in ein Label einordnet.
+type QualityBands struct {
+	HighThreshold float64
+	LowThreshold  float64
+}
+
+// DetectorConfig fasst die externe, hot-reloadbare Scoring-Konfiguration
+// zusammen: Verdict-Schwellen, Analyse-Qualitätsbänder und Pro-Detektor-
+// Gewichte (vormals die hartkodierte `weights`-Map oben in
+// CalculateOverallVerdict). Operators können damit die Sensitivität
+// nachjustieren, ohne neu zu kompilieren - /metrics surfaced den aktiven
+// Stand (siehe GetDetectorConfigStatus).
+type DetectorConfig struct {
+	Thresholds      VerdictThresholds
+	QualityBands    QualityBands
+	DetectorWeights map[string]float64
+	UseBayesFusion  bool
+}
+
+const defaultDetectorConfigPath = "config/detector.yaml"
+
+var (
+	detectorMu            sync.RWMutex
+	detectorConfigPath    = defaultDetectorConfigPath
+	activeDetectorConfig  = defaultDetectorConfig()
+	lastDetectorConfigAt  time.Time
+	lastDetectorConfigErr error
+	detectorWatchOnce     sync.Once
+)
+
+func defaultDetectorConfig() DetectorConfig {
+	return DetectorConfig{
+		Thresholds: VerdictThresholds{
+			AIGenerated:            0.75,
+			LikelyAIGenerated:      0.60,
+			LikelyAuthentic:        0.59,
+			SmallSampleAdjustment:  0.05,
+			SmallSampleCutoff:      4,
+			ConfidenceCapAI:        0.95,
+			ConfidenceCapLikely:    0.85,
+			ConfidenceCapAuthentic: 0.40,
+			MinEvidenceCoverage:    0.4,
+			AbstainConfidenceFloor: 0.55,
+		},
+		QualityBands: QualityBands{
+			HighThreshold: 0.8,
+			LowThreshold:  0.5,
+		},
+		DetectorWeights: map[string]float64{
+			"ai-model": 6.0,
+
+			"compression":        4.0,
+			"lighting-analysis":  3.5,
+			"artifacts":          3.0,
+			"advanced-artifacts": 3.0,
+			"color-balance":      3.0,
+
+			"metadata":       2.5,
+			"pixel-analysis": 2.5,
+			"c2pa":           2.0,
+
+			"object-coherence": 0.5,
+			"exif":             1.0,
+			"metadata-quick":   0.8,
+		},
+		UseBayesFusion: false,
+	}
+}
+
+// InitDetectorConfig lädt die Detector-Konfiguration von path (leer =
+// Default aus defaultDetectorConfigPath), registriert einen SIGHUP-Handler
+// und startet ein Polling, das die Datei bei Änderungen automatisch neu
+// einliest - analog zu InitCalibrationConfig. Wird path nicht gefunden,
+// bleibt die eingebaute Default-Konfiguration aktiv.
+func InitDetectorConfig(path string) {
+	detectorMu.Lock()
+	if path != "" {
+		detectorConfigPath = path
+	}
+	detectorMu.Unlock()
+
+	reloadDetectorConfig()
+
+	detectorWatchOnce.Do(func() {
+		sighup := make(chan os.Signal, 1)
+		signal.Notify(sighup, syscall.SIGHUP)
+		go func() {
+			for range sighup {
+				fmt.Println("SIGHUP empfangen, lade Detector-Konfiguration neu:", detectorConfigPath)
+				reloadDetectorConfig()
+			}
+		}()
+
+		go watchDetectorConfigFile()
+	})
+}
+
+func watchDetectorConfigFile() {
+	var lastMod time.Time
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		detectorMu.RLock()
+		path := detectorConfigPath
+		detectorMu.RUnlock()
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(lastMod) {
+			lastMod = info.ModTime()
+			if !lastMod.IsZero() {
+				reloadDetectorConfig()
+			}
+		}
+	}
+}
+
+func reloadDetectorConfig() {
+	detectorMu.RLock()
+	path := detectorConfigPath
+	detectorMu.RUnlock()
+
+	config, err := loadDetectorConfig(path)
+
+	detectorMu.Lock()
+	defer detectorMu.Unlock()
+	lastDetectorConfigAt = time.Now()
+	if err != nil {
+		lastDetectorConfigErr = err
+		fmt.Printf("WARNING: Detector-Konfiguration %s konnte nicht geladen werden (%v), behalte aktive Konfiguration\n", path, err)
+		return
+	}
+	lastDetectorConfigErr = nil
+	activeDetectorConfig = config
+}
+
+func currentDetectorConfig() DetectorConfig {
+	detectorMu.RLock()
+	defer detectorMu.RUnlock()
+	return activeDetectorConfig
+}
+
+// DetectorConfigStatus wird vom /metrics Endpoint ausgeliefert, damit
+// Operator die aktive Sensitivität einsehen können, ohne den Quellcode zu
+// lesen.
+type DetectorConfigStatus struct {
+	Path            string             `json:"path"`
+	LastReload      time.Time          `json:"last_reload"`
+	LastError       string             `json:"last_error,omitempty"`
+	Thresholds      VerdictThresholds  `json:"thresholds"`
+	QualityBands    QualityBands       `json:"quality_bands"`
+	DetectorWeights map[string]float64 `json:"detector_weights"`
+	UseBayesFusion  bool               `json:"use_bayes_fusion"`
+}
+
+// GetDetectorConfigStatus liefert die aktuell aktive Detector-Konfiguration
+// samt Zeitpunkt des letzten Reload-Versuchs.
+func GetDetectorConfigStatus() DetectorConfigStatus {
+	detectorMu.RLock()
+	defer detectorMu.RUnlock()
+
+	errMsg := ""
+	if lastDetectorConfigErr != nil {
+		errMsg = lastDetectorConfigErr.Error()
+	}
+
+	return DetectorConfigStatus{
+		Path:            detectorConfigPath,
+		LastReload:      lastDetectorConfigAt,
+		LastError:       errMsg,
+		Thresholds:      activeDetectorConfig.Thresholds,
+		QualityBands:    activeDetectorConfig.QualityBands,
+		DetectorWeights: activeDetectorConfig.DetectorWeights,
+		UseBayesFusion:  activeDetectorConfig.UseBayesFusion,
+	}
+}
+
+// ConfigFingerprint liefert einen kurzen SHA-256-Hash über die aktuell
+// aktive Detector-Konfiguration und das Kalibrierungsprofil. Wird von
+// main.go an pipeline.ConfigFingerprint verdrahtet (siehe dort), damit der
+// Analysis-Cache (pkg/analyzer/pipeline) ungültig wird, sobald Operator
+// detector.yaml oder calibration.yaml ändern - ohne dass das pipeline-Paket
+// dieses Paket importieren müsste.
+func ConfigFingerprint() string {
+	detector := currentDetectorConfig()
+	calibration := currentCalibration()
+
+	h := sha256.New()
+	fmt.Fprintf(h, "thresholds:%+v\n", detector.Thresholds)
+	fmt.Fprintf(h, "quality_bands:%+v\n", detector.QualityBands)
+	fmt.Fprintf(h, "use_bayes_fusion:%v\n", detector.UseBayesFusion)
+
+	for _, name := range sortedKeys(detector.DetectorWeights) {
+		fmt.Fprintf(h, "weight:%s=%v\n", name, detector.DetectorWeights[name])
+	}
+	for _, name := range sortedKeys(calibration.Factors) {
+		fmt.Fprintf(h, "factor:%s=%v\n", name, calibration.Factors[name])
+	}
+	for _, rule := range calibration.Rules {
+		fmt.Fprintf(h, "rule:%+v\n", rule)
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))[:16]
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// loadDetectorConfig parst dasselbe minimalistische YAML-Subset wie
+// loadCalibrationProfile: drei flache Abschnitte ("thresholds:",
+// "quality_bands:", "weights:"), jeweils "key: value"-Paare.
+func loadDetectorConfig(path string) (DetectorConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return DetectorConfig{}, err
+	}
+	defer f.Close()
+
+	config := defaultDetectorConfig()
+	config.DetectorWeights = map[string]float64{}
+
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		rawLine := scanner.Text()
+		line := strings.TrimRight(rawLine, " \t")
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		switch trimmed {
+		case "thresholds:":
+			section = "thresholds"
+			continue
+		case "quality_bands:":
+			section = "quality_bands"
+			continue
+		case "weights:":
+			section = "weights"
+			continue
+		case "fusion:":
+			section = "fusion"
+			continue
+		}
+
+		key, value, ok := splitYAMLKeyValue(trimmed)
+		if !ok {
+			continue
+		}
+
+		switch section {
+		case "thresholds":
+			if err := setThresholdField(&config.Thresholds, key, value); err != nil {
+				return DetectorConfig{}, err
+			}
+		case "quality_bands":
+			if err := setQualityBandField(&config.QualityBands, key, value); err != nil {
+				return DetectorConfig{}, err
+			}
+		case "weights":
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return DetectorConfig{}, fmt.Errorf("invalid weight value for %q: %w", key, err)
+			}
+			config.DetectorWeights[key] = v
+		case "fusion":
+			if err := setFusionField(&config, key, value); err != nil {
+				return DetectorConfig{}, err
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return DetectorConfig{}, err
+	}
+
+	if len(config.DetectorWeights) == 0 {
+		return DetectorConfig{}, fmt.Errorf("detector config %s has no weights", path)
+	}
+
+	return config, nil
+}
+
+func setThresholdField(t *VerdictThresholds, key, value string) error {
+	switch key {
+	case "ai_generated":
+		return parseFloatInto(&t.AIGenerated, value)
+	case "likely_ai_generated":
+		return parseFloatInto(&t.LikelyAIGenerated, value)
+	case "likely_authentic":
+		return parseFloatInto(&t.LikelyAuthentic, value)
+	case "small_sample_adjustment":
+		return parseFloatInto(&t.SmallSampleAdjustment, value)
+	case "small_sample_cutoff":
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid small_sample_cutoff: %w", err)
+		}
+		t.SmallSampleCutoff = v
+		return nil
+	case "confidence_cap_ai":
+		return parseFloatInto(&t.ConfidenceCapAI, value)
+	case "confidence_cap_likely":
+		return parseFloatInto(&t.ConfidenceCapLikely, value)
+	case "confidence_cap_authentic":
+		return parseFloatInto(&t.ConfidenceCapAuthentic, value)
+	case "min_evidence_coverage":
+		return parseFloatInto(&t.MinEvidenceCoverage, value)
+	case "abstain_confidence_floor":
+		return parseFloatInto(&t.AbstainConfidenceFloor, value)
+	}
+	return nil
+}
+
+// setFusionField parst die "fusion:"-Sektion - bisher nur das eine Flag,
+// ob computeVerdict über die Bayes-Netz-Fusion (pkg/verdict/bayes) statt
+// über fusion.CombineLogOdds rechnet.
+func setFusionField(c *DetectorConfig, key, value string) error {
+	switch key {
+	case "use_bayes":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid use_bayes: %w", err)
+		}
+		c.UseBayesFusion = v
+	}
+	return nil
+}
+
+func setQualityBandField(b *QualityBands, key, value string) error {
+	switch key {
+	case "high_threshold":
+		return parseFloatInto(&b.HighThreshold, value)
+	case "low_threshold":
+		return parseFloatInto(&b.LowThreshold, value)
+	}
+	return nil
+}
+
+func parseFloatInto(dst *float64, value string) error {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid float value %q: %w", value, err)
+	}
+	*dst = v
+	return nil
+}
@@ -2,32 +2,79 @@ package verdict
 
 import "math"
 
-func determineBalancedVerdict(score float64, scores map[string]float64) (string, float64) {
+// Abstain-Reason-Enum für "Inconclusive"-Verdicts (siehe
+// determineBalancedVerdict) - maschinenlesbar, damit API-Clients
+// entscheiden können, ob sich ein Retry mit teureren Analyzern lohnt.
+const (
+	AbstainInsufficientDetectors = "insufficient_detectors"
+	AbstainDetectorDisagreement  = "detector_disagreement"
+	AbstainAIModelUnavailable    = "ai_model_unavailable"
+)
+
+// determineBalancedVerdict bestimmt Verdict-Label, Confidence und
+// evidence_coverage anhand der VerdictThresholds aus der aktiven
+// DetectorConfig (siehe detector_config.go). Deckt evidence_coverage - der
+// Anteil des insgesamt möglichen Detektor-Gewichts, der tatsächlich einen
+// Score geliefert hat - thresholds.MinEvidenceCoverage nicht, liefert
+// determineBalancedVerdict "Inconclusive" statt eines der vier regulären
+// Verdicts, mit abstainReason AbstainInsufficientDetectors - zu wenige
+// Detektoren sind gelaufen, um dem Ergebnis zu trauen, unabhängig davon,
+// wie eindeutig ihr Score ausfällt.
+func determineBalancedVerdict(score float64, scores map[string]float64, weights map[string]float64) (verdictLabel string, confidence float64, coverage float64, abstainReason string) {
 	baseConfidence := calculateConfidence(scores, len(scores))
+	thresholds := currentDetectorConfig().Thresholds
+
+	coverage = evidenceCoverage(scores, weights)
+	if coverage < thresholds.MinEvidenceCoverage {
+		return "Inconclusive", baseConfidence, coverage, AbstainInsufficientDetectors
+	}
 
 	thresholdAdjustment := 0.0
-	if len(scores) < 4 {
-		thresholdAdjustment = 0.05
+	if len(scores) < thresholds.SmallSampleCutoff {
+		thresholdAdjustment = thresholds.SmallSampleAdjustment
 	}
 
-	if score >= (0.75 + thresholdAdjustment) {
-		confidence := math.Min(0.95, baseConfidence+0.15)
-		return "AI Generated", confidence
-	} else if score >= (0.60 + thresholdAdjustment) {
-		confidence := math.Min(0.85, baseConfidence+0.10)
-		return "Likely AI Generated", confidence
-	} else if score >= (0.59 + thresholdAdjustment) {
-		confidence := math.Min(0.85, baseConfidence+0.10)
-		return "Likely Authentic", confidence
+	if score >= (thresholds.AIGenerated + thresholdAdjustment) {
+		confidence := math.Min(thresholds.ConfidenceCapAI, baseConfidence+0.15)
+		return "AI Generated", confidence, coverage, ""
+	} else if score >= (thresholds.LikelyAIGenerated + thresholdAdjustment) {
+		confidence := math.Min(thresholds.ConfidenceCapLikely, baseConfidence+0.10)
+		return "Likely AI Generated", confidence, coverage, ""
+	} else if score >= (thresholds.LikelyAuthentic + thresholdAdjustment) {
+		confidence := math.Min(thresholds.ConfidenceCapLikely, baseConfidence+0.10)
+		return "Likely Authentic", confidence, coverage, ""
 	} else {
-		confidence := math.Min(0.40, baseConfidence+0.15)
-		return "Authentic", confidence
+		confidence := math.Min(thresholds.ConfidenceCapAuthentic, baseConfidence+0.15)
+		return "Authentic", confidence, coverage, ""
+	}
+}
+
+// evidenceCoverage ist die Summe der Gewichte der Detektoren, die
+// tatsächlich einen Score in scores geliefert haben, geteilt durch die
+// Summe aller nicht deaktivierten (Gewicht != 0) Gewichte in weights -
+// unabhängig davon, wie eindeutig die gelieferten Scores selbst ausfallen.
+// Ein vom Operator deaktivierter Detektor (Gewicht 0) zählt weder zum
+// Zähler noch zum Nenner.
+func evidenceCoverage(scores map[string]float64, weights map[string]float64) float64 {
+	var reported, total float64
+	for name, w := range weights {
+		if w == 0 {
+			continue
+		}
+		total += w
+		if _, exists := scores[name]; exists {
+			reported += w
+		}
+	}
+	if total == 0 {
+		return 0
 	}
+	return reported / total
 }
 
 func calculateConfidence(scores map[string]float64, totalMethods int) float64 {
 	if totalMethods == 0 {
-		return 0.5
+		return 0.0
 	}
 
 	methodConfidence := float64(len(scores)) / float64(totalMethods)
@@ -36,8 +83,8 @@ func calculateConfidence(scores map[string]float64, totalMethods int) float64 {
 
 	confidence := (methodConfidence * 0.7) + (consistencyBonus * 0.3)
 
-	if confidence < 0.5 {
-		confidence = 0.5
+	if confidence < 0 {
+		confidence = 0
 	}
 	if confidence > 0.99 {
 		confidence = 0.99
@@ -46,24 +93,14 @@ func calculateConfidence(scores map[string]float64, totalMethods int) float64 {
 	return confidence
 }
 
+// calculateConsistency misst die Übereinstimmung der Detektoren über ihre
+// circularDispersion (siehe consistency.go) statt über eine lineare Varianz
+// - zwei Detektoren bei 0.05 und 0.95 mitteln sich linear zum
+// "unentschiedenen" Wert 0.5 und wirken damit fälschlich konsistent, obwohl
+// sie sich maximal widersprechen.
 func calculateConsistency(scores map[string]float64) float64 {
 	if len(scores) < 2 {
 		return 0.5
 	}
-
-	var sum float64
-	for _, score := range scores {
-		sum += score
-	}
-	mean := sum / float64(len(scores))
-
-	var variance float64
-	for _, score := range scores {
-		diff := score - mean
-		variance += diff * diff
-	}
-	variance /= float64(len(scores))
-
-	consistency := math.Max(0, 1.0-variance)
-	return consistency
+	return math.Max(0, 1.0-circularDispersion(scores, nil))
 }
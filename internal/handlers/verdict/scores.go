@@ -4,7 +4,24 @@ import (
 	"github.com/BramseDev/imageAnalyzer/internal/handlers/utils"
 )
 
+// knownAIDigitalSourceTypes sind die IPTC DigitalSourceType-Werte, die Adobe
+// und Google für generative/synthetische Bilder vergeben (siehe IPTC
+// Photo Metadata Standard, "Digital Source Type" NewsCodes).
+var knownAIDigitalSourceTypes = []string{
+	"trainedAlgorithmicMedia",
+	"compositeSynthetic",
+	"algorithmicMedia",
+}
+
 func calculateEXIFScore(data map[string]interface{}) float64 {
+	if sourceType, exists := utils.GetStringValue(data, "digital_source_type"); exists {
+		for _, aiType := range knownAIDigitalSourceTypes {
+			if sourceType == aiType {
+				return 1.0 // Definitiver AI-Marker in den IPTC-Metadaten
+			}
+		}
+	}
+
 	if hasCameraInfo, exists := data["has_camera_info"]; exists {
 		if has, ok := hasCameraInfo.(bool); ok && has {
 			return 0.0 // Authentisch - Camera-Info vorhanden
@@ -15,6 +32,36 @@ func calculateEXIFScore(data map[string]interface{}) float64 {
 	return -1 // Ignorieren - kein EXIF-Check möglich
 }
 
+// calculateMetadataStructuredScore wertet den von
+// exifanalyzer.AnalyzeStructuredMetadata gelieferten MetadataReport aus
+// (serialisiert über MetadataReport.ToResultMap) - anders als
+// calculateEXIFScore reagiert das hier auch auf XMP/IPTC-Felder und die
+// stripped-metadata-Heuristik, nicht nur auf IPTC DigitalSourceType und
+// reine Kamera-Präsenz.
+func calculateMetadataStructuredScore(data map[string]interface{}) float64 {
+	if generator, exists := utils.GetStringValue(data, "known_ai_generator"); exists && generator != "" {
+		return 1.0 // Definitiver AI-Generator-Tag in Software/XMP/IPTC gefunden
+	}
+
+	if combos, exists := data["suspicious_combinations"].([]interface{}); exists && len(combos) > 0 {
+		return 0.55 // z.B. Kameramodell ohne Objektiv/GPS - verdächtig, aber nicht definitiv
+	}
+
+	if stripped, exists := data["stripped_metadata"]; exists {
+		if isStripped, ok := stripped.(bool); ok && isStripped {
+			return 0.6 // Keine Metadaten-Container gefunden - viele legitime Tools entfernen ebenfalls Metadaten
+		}
+	}
+
+	if hasCamera, exists := data["has_camera_info"]; exists {
+		if has, ok := hasCamera.(bool); ok && has {
+			return 0.0 // Authentische Kamera-Metadaten vorhanden
+		}
+	}
+
+	return -1 // Ignorieren - keine verwertbaren Signale
+}
+
 func calculateMetadataScore(data map[string]interface{}) float64 {
 	if hasMetadata, exists := data["has_metadata"]; exists {
 		if has, ok := hasMetadata.(bool); ok && has {
@@ -0,0 +1,177 @@
+package verdict
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/BramseDev/imageAnalyzer/pkg/analyzer/pipeline"
+	"github.com/BramseDev/imageAnalyzer/pkg/rustrunner"
+)
+
+// EnvironmentalContext sind die vom Aufrufer je Request mitgegebenen
+// Rahmenbedingungen, unter denen das Bild analysiert wird - mirror der
+// CVSS Environmental-Metrics, die die intrinsische Severity um den
+// konkreten Deployment-Kontext korrigieren. Felder ohne (oder mit
+// ungültiger) Angabe bleiben auf ihrem neutralen Default stehen, siehe
+// NewEnvironmentalContext.
+type EnvironmentalContext struct {
+	// SourceDomainTrust ist, wie sehr der Aufrufer der Herkunft des Bildes
+	// vertraut (z.B. verifizierte Presse-Domain vs. anonymer Upload), im
+	// Bereich [0,1]. Default 0.5 (neutral/unbekannt).
+	SourceDomainTrust float64
+
+	// DeclaredContext ist eine vom Aufrufer deklarierte Erwartung an den
+	// Bildtyp: "photo", "screenshot", "artwork" oder "" (keine Angabe).
+	DeclaredContext string
+
+	// TargetSensitivity ist, wie konservativ der Aufrufer bei
+	// Falsch-Negativen sein will: "low", "standard" oder "high".
+	TargetSensitivity string
+}
+
+// DefaultEnvironmentalContext liefert einen vollständig neutralen Kontext -
+// verwendet für Aufrufer, die keine Umgebungs-Parameter mitgeben (Batch-Jobs,
+// der asynchrone Job-Queue-Worker, Cache-Treffer).
+func DefaultEnvironmentalContext() EnvironmentalContext {
+	return EnvironmentalContext{
+		SourceDomainTrust: 0.5,
+		DeclaredContext:   "",
+		TargetSensitivity: "standard",
+	}
+}
+
+// NewEnvironmentalContext baut einen EnvironmentalContext aus rohen, vom
+// Aufrufer (z.B. Query-Parametern) stammenden Werten. Ungültige Werte werden
+// stillschweigend durch den neutralen Default ersetzt statt den Request
+// abzulehnen - ein unplausibler Umgebungs-Parameter soll das Ergebnis
+// lediglich neutral statt den ganzen Request fehlschlagen lassen.
+func NewEnvironmentalContext(sourceDomainTrust float64, declaredContext, targetSensitivity string) EnvironmentalContext {
+	ctx := DefaultEnvironmentalContext()
+
+	if sourceDomainTrust >= 0 && sourceDomainTrust <= 1 {
+		ctx.SourceDomainTrust = sourceDomainTrust
+	}
+
+	switch declaredContext {
+	case "photo", "screenshot", "artwork":
+		ctx.DeclaredContext = declaredContext
+	}
+
+	switch targetSensitivity {
+	case "low", "standard", "high":
+		ctx.TargetSensitivity = targetSensitivity
+	}
+
+	return ctx
+}
+
+// environmentalCoeffMin/Max begrenzen, wie stark die Environmental-Dimension
+// den Base-Score insgesamt verschieben darf - wie CVSS verhindert das, dass
+// ein einzelner Deployment-Faktor die intrinsische Severity dominiert.
+const (
+	environmentalCoeffMin = 0.85
+	environmentalCoeffMax = 1.15
+)
+
+// computeEnvironmentalCoefficient bildet ctx auf einen beschränkten
+// multiplikativen Faktor ab, zusammen mit einer menschenlesbaren Liste der
+// angewendeten Modifier für die Verdict-Antwort (siehe
+// CalculateOverallVerdict, Feld "applied_modifiers").
+func computeEnvironmentalCoefficient(ctx EnvironmentalContext) (float64, []string) {
+	coeff := 1.0
+	var modifiers []string
+
+	// Symmetrisch um den neutralen Default 0.5: eine wenig vertrauenswürdige
+	// Quelle erhöht den Score leicht, eine verifizierte Quelle senkt ihn.
+	if trustAdjustment := (0.5 - ctx.SourceDomainTrust) * 0.2; trustAdjustment != 0 {
+		coeff += trustAdjustment
+		modifiers = append(modifiers, fmt.Sprintf("source_domain_trust=%.2f (%+.0f%%)", ctx.SourceDomainTrust, trustAdjustment*100))
+	}
+
+	switch ctx.DeclaredContext {
+	case "screenshot":
+		// Screenshots enthalten oft UI-Rendering-Artefakte, die
+		// Compression-/Pixel-Detektoren fälschlich als AI-Artefakte lesen.
+		coeff -= 0.05
+		modifiers = append(modifiers, "declared_context=screenshot (-5%)")
+	case "artwork":
+		// Digitale Kunst hat legitimerweise untypische Farb-/Lichtmuster -
+		// schwächere Absenkung als screenshot.
+		coeff -= 0.03
+		modifiers = append(modifiers, "declared_context=artwork (-3%)")
+	}
+
+	switch ctx.TargetSensitivity {
+	case "high":
+		// Hochsensibler Einsatzzweck (z.B. Nachrichtenredaktion) soll eher
+		// zu oft "AI" als zu oft "Authentic" sagen.
+		coeff += 0.05
+		modifiers = append(modifiers, "target_sensitivity=high (+5%)")
+	case "low":
+		coeff -= 0.05
+		modifiers = append(modifiers, "target_sensitivity=low (-5%)")
+	}
+
+	if coeff < environmentalCoeffMin {
+		coeff = environmentalCoeffMin
+	}
+	if coeff > environmentalCoeffMax {
+		coeff = environmentalCoeffMax
+	}
+
+	return coeff, modifiers
+}
+
+// temporalCoeffMin/Max begrenzen die Temporal-Dimension analog zu
+// environmentalCoeffMin/Max.
+const (
+	temporalCoeffMin = 0.85
+	temporalCoeffMax = 1.05
+
+	// trustListStaleAfter ist das Alter, ab dem eine nicht aktualisierte
+	// C2PA-Trust-Liste (rustrunner.TrustedSignersUpdatedAt) als veraltet
+	// gilt und die Temporal-Dimension leicht abwertet.
+	trustListStaleAfter = 30 * 24 * time.Hour
+)
+
+// computeTemporalCoefficient bildet die "Reife" der für dieses Ergebnis
+// verfügbaren Evidenz auf einen beschränkten multiplikativen Faktor ab: wie
+// viele der für results.MimeType erwarteten Detektoren tatsächlich gelaufen
+// sind, ob der separate ai-model-Klassifikator verfügbar war, und wie
+// aktuell die C2PA-Trust-Liste ist - mirror der CVSS Temporal-Metrics, die
+// die Base-Severity um die aktuelle Exploit-Reife korrigieren.
+func computeTemporalCoefficient(results *pipeline.PipelineResult, scores map[string]float64) (float64, []string) {
+	coeff := 1.0
+	var modifiers []string
+
+	totalExpected := len(results.Results)
+	if expected, ok := pipeline.ExpectedDetectorCount(results.MimeType); ok {
+		totalExpected = expected
+	}
+	if totalExpected > 0 {
+		if ratio := float64(len(scores)) / float64(totalExpected); ratio < 1.0 {
+			penalty := (1.0 - ratio) * 0.1
+			coeff -= penalty
+			modifiers = append(modifiers, fmt.Sprintf("evidence_maturity=%.0f%% of expected detectors ran (-%.0f%%)", ratio*100, penalty*100))
+		}
+	}
+
+	if _, exists := scores["ai-model"]; !exists {
+		coeff -= 0.05
+		modifiers = append(modifiers, "ai-model detector unavailable (-5%)")
+	}
+
+	if age := time.Since(rustrunner.TrustedSignersUpdatedAt); age > trustListStaleAfter {
+		coeff -= 0.03
+		modifiers = append(modifiers, fmt.Sprintf("c2pa trust list stale (%s old, -3%%)", age.Round(time.Hour)))
+	}
+
+	if coeff < temporalCoeffMin {
+		coeff = temporalCoeffMin
+	}
+	if coeff > temporalCoeffMax {
+		coeff = temporalCoeffMax
+	}
+
+	return coeff, modifiers
+}
@@ -51,11 +51,22 @@ func (l *Logger) LogAnalysisComplete(analysisType string, duration time.Duration
 	)
 }
 
-func (l *Logger) LogPipelineMetrics(stagesRun []string, totalDuration time.Duration, earlyExit bool) {
+// StageMetric ist das logging-seitige Gegenstück zu
+// pipeline.StageMetric - bewusst ohne Abhängigkeit auf das pipeline-Paket
+// gehalten, damit der Aufrufer einfache Werte statt eines fremden Typs
+// übergibt.
+type StageMetric struct {
+	CPUNanos   int64
+	AllocBytes int64
+	AllocCount uint64
+}
+
+func (l *Logger) LogPipelineMetrics(stagesRun []string, totalDuration time.Duration, earlyExit bool, stageMetrics map[string]StageMetric) {
 	l.Info("Pipeline metrics",
 		"stages_completed", len(stagesRun),
 		"stages", stagesRun,
 		"total_duration_ms", totalDuration.Milliseconds(),
 		"early_exit", earlyExit,
+		"stage_metrics", stageMetrics,
 	)
 }
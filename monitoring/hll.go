@@ -0,0 +1,68 @@
+package monitoring
+
+import "math"
+
+// hllRegisters bestimmt die Genauigkeit der Unique-Visitor-Schätzung: 2^hllBits
+// Register ergeben einen Standardfehler von ca. 1.04/sqrt(2^hllBits) (~3.3%
+// bei 1024 Registern) bei konstantem Speicherbedarf, unabhängig von der
+// tatsächlichen Anzahl Besucher.
+const hllBits = 10
+const hllRegisters = 1 << hllBits
+
+// hll ist ein minimales HyperLogLog-Sketch zum Zählen eindeutiger
+// Besucher pro Zeitfenster, ohne jede gesehene Session-ID im Speicher zu
+// halten.
+type hll struct {
+	registers [hllRegisters]uint8
+}
+
+func newHLL() *hll {
+	return &hll{}
+}
+
+func (h *hll) add(hash uint64) {
+	idx := hash & (hllRegisters - 1)
+	rest := hash >> hllBits
+	rho := uint8(1)
+	for rest&1 == 0 && rho < 64-hllBits {
+		rest >>= 1
+		rho++
+	}
+	if rho > h.registers[idx] {
+		h.registers[idx] = rho
+	}
+}
+
+func (h *hll) merge(other *hll) *hll {
+	merged := newHLL()
+	for i := range h.registers {
+		v := h.registers[i]
+		if other.registers[i] > v {
+			v = other.registers[i]
+		}
+		merged.registers[i] = v
+	}
+	return merged
+}
+
+// estimate liefert die Kardinalitätsschätzung nach dem Standard-HLL-Verfahren
+// mit linear-counting-Korrektur für kleine Kardinalitäten.
+func (h *hll) estimate() float64 {
+	m := float64(hllRegisters)
+	sum := 0.0
+	zeros := 0
+	for _, v := range h.registers {
+		sum += 1.0 / math.Pow(2, float64(v))
+		if v == 0 {
+			zeros++
+		}
+	}
+
+	alpha := 0.7213 / (1 + 1.079/m)
+	raw := alpha * m * m / sum
+
+	if raw <= 2.5*m && zeros > 0 {
+		return m * math.Log(m/float64(zeros))
+	}
+	return raw
+}
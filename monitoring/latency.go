@@ -0,0 +1,134 @@
+package monitoring
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyReservoirSize begrenzt, wie viele Roh-Samples pro Analysis-Type für
+// die Perzentil-Schätzung vorgehalten werden. Ein Ringpuffer fester Größe
+// vermeidet den O(n) Slice-Shift der ursprünglichen gekappten
+// []time.Duration-Puffer, while still reichend Auflösung für p50..p9999
+// liefert.
+const latencyReservoirSize = 1000
+
+// latencyPercentiles sind die vom Backlog geforderten Perzentile, in der
+// Reihenfolge, in der sie exportiert werden.
+var latencyPercentiles = []struct {
+	label string
+	p     float64
+}{
+	{"p50", 0.50},
+	{"p75", 0.75},
+	{"p95", 0.95},
+	{"p99", 0.99},
+	{"p999", 0.999},
+	{"p9999", 0.9999},
+}
+
+// latencyHistogram verfolgt je Analysis-Type eine Reservoir-Stichprobe
+// (für Perzentile) sowie laufende Min/Max/Mean/Varianz per Welford-Verfahren
+// (O(1) pro Add, keine Neuberechnung über alle Samples nötig).
+type latencyHistogram struct {
+	mu sync.Mutex
+
+	samples []float64
+	next    int
+
+	count int64
+	mean  float64
+	m2    float64
+	min   float64
+	max   float64
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{samples: make([]float64, 0, latencyReservoirSize)}
+}
+
+// Add nimmt eine Dauer in Sekunden auf.
+func (h *latencyHistogram) Add(seconds float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.count++
+	delta := seconds - h.mean
+	h.mean += delta / float64(h.count)
+	h.m2 += delta * (seconds - h.mean)
+
+	if h.count == 1 || seconds < h.min {
+		h.min = seconds
+	}
+	if h.count == 1 || seconds > h.max {
+		h.max = seconds
+	}
+
+	if len(h.samples) < latencyReservoirSize {
+		h.samples = append(h.samples, seconds)
+	} else {
+		h.samples[h.next] = seconds
+		h.next = (h.next + 1) % latencyReservoirSize
+	}
+}
+
+// LatencyStats ist eine unter dem histogrammeigenen Mutex kopierte
+// Momentaufnahme aus Perzentilen (als Sekunden, sortiert aus der
+// Reservoir-Stichprobe geschätzt) und laufenden Min/Max/Mean/Varianz-Werten.
+type LatencyStats struct {
+	Count      int64
+	Min        time.Duration
+	Max        time.Duration
+	Mean       time.Duration
+	Variance   float64 // Sekunden^2, da eine Dauer hier keine sinnvolle Einheit hat
+	StdDev     time.Duration
+	Percentile map[string]time.Duration
+}
+
+// Stats berechnet die Perzentile aus der aktuellen Reservoir-Stichprobe plus
+// die laufenden Min/Max/Mean/Varianz-Werte.
+func (h *latencyHistogram) Stats() LatencyStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stats := LatencyStats{
+		Count:      h.count,
+		Min:        time.Duration(h.min * float64(time.Second)),
+		Max:        time.Duration(h.max * float64(time.Second)),
+		Mean:       time.Duration(h.mean * float64(time.Second)),
+		Percentile: make(map[string]time.Duration, len(latencyPercentiles)),
+	}
+	if h.count > 1 {
+		stats.Variance = h.m2 / float64(h.count-1)
+		stats.StdDev = time.Duration(math.Sqrt(stats.Variance) * float64(time.Second))
+	}
+
+	if len(h.samples) == 0 {
+		for _, pct := range latencyPercentiles {
+			stats.Percentile[pct.label] = 0
+		}
+		return stats
+	}
+
+	sorted := make([]float64, len(h.samples))
+	copy(sorted, h.samples)
+	sort.Float64s(sorted)
+
+	for _, pct := range latencyPercentiles {
+		idx := int(pct.p * float64(len(sorted)-1))
+		stats.Percentile[pct.label] = time.Duration(sorted[idx] * float64(time.Second))
+	}
+	return stats
+}
+
+// Samples liefert eine Kopie der aktuellen Reservoir-Stichprobe (Sekunden) -
+// genutzt vom Prometheus-Exporter, um Bucket-Histogramme zu rendern, ohne
+// unbounded Rohdaten vorhalten zu müssen.
+func (h *latencyHistogram) Samples() []float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]float64, len(h.samples))
+	copy(out, h.samples)
+	return out
+}
@@ -1,20 +1,36 @@
 package monitoring
 
 import (
-	"fmt"
+	"context"
 	"sync"
 	"time"
 
+	"github.com/BramseDev/imageAnalyzer/cache"
 	analyzer "github.com/BramseDev/imageAnalyzer/pkg/analyzer/pipeline"
 )
 
+// visitorBucketCount Minuten-Buckets werden vorgehalten - genug, um das 1h-Fenster
+// als gleitende Summe der letzten 60 Minuten-HLLs zu bilden.
+const visitorBucketCount = 60
+
 type Metrics struct {
 	mu sync.RWMutex
 
 	// Performance Metrics
-	AnalysisCount    map[string]int64
-	AnalysisDuration map[string][]time.Duration
-	ErrorCount       map[string]int64
+	AnalysisCount map[string]int64
+
+	// durationMeters verfolgt je Pipeline-Stage eine EWMA-geglättete Rate an
+	// Sekunden Verarbeitungszeit pro Sekunde (siehe rateMeter) - ersetzt die
+	// früheren gekappten []time.Duration-Ringpuffer, die bei jedem Aufruf
+	// einen O(n) Slice-Shift verursacht haben.
+	durationMeters map[string]*rateMeter
+	ErrorCount     map[string]int64
+
+	// latencyHistograms verfolgt je Pipeline-Stage eine latencyHistogram
+	// (Reservoir-Stichprobe + laufende Min/Max/Mean/Varianz) - liefert die
+	// Tail-Latenz (p50..p9999), die durationMeters' einzelner EWMA-Wert nicht
+	// zeigen kann.
+	latencyHistograms map[string]*latencyHistogram
 
 	// Business Metrics - FIXED
 	TotalAnalyses   int64
@@ -25,26 +41,181 @@ type Metrics struct {
 	CacheHitRate    float64
 
 	// System Metrics
-	MemoryUsage       []float64
-	CPUUsage          []float64
+	// memoryMeter/cpuMeter glätten die rohen Gauge-Messwerte aus
+	// RecordSystemMetrics per EWMA (siehe rateMeter) - ersetzt die früheren
+	// gekappten []float64-Ringpuffer.
+	memoryMeter       *rateMeter
+	cpuMeter          *rateMeter
 	ActiveConnections int64
 
+	// DiskUsage/NetIO sind die zuletzt von StartSystemSampler gesampelten
+	// Gauges - im Gegensatz zu memoryMeter/cpuMeter werden sie nicht per EWMA
+	// geglättet, da Plattenbelegung und kumulative Netzwerk-Zähler sich nicht
+	// als Rate pro Sekunde interpretieren lassen.
+	DiskUsage DiskUsageStats
+	NetIO     NetIOStats
+
+	// ActiveInFlight ist die echte Anzahl gerade laufender Analysen (statt
+	// "Nutzer, die in den letzten N Minuten geklickt haben") - eignet sich
+	// als echtes Load-Shedding-Signal.
+	ActiveInFlight int64
+
+	// visitorBuckets ist ein Ring von Minuten-HLLs für die eindeutigen
+	// Besucher je Sliding-Window (1m/5m/1h), rotiert von rotateVisitorBuckets.
+	visitorMu      sync.Mutex
+	visitorBuckets [visitorBucketCount]*hll
+	visitorHead    int
+
 	// Cache Metrics
 	CacheHits   int64
 	CacheMisses int64
+	CacheStats  cache.Stats
+
+	// VerdictCount zählt finale Verdicts nach Verdict-Label - Grundlage für
+	// die nach "verdict" gelabelten Counter im Prometheus-Export
+	// (monitoring/prom).
+	VerdictCount map[string]int64
+
+	// VerdictDistribution zählt dieselben Verdicts nach dem typisierten
+	// analyzer.Verdict-Enum statt nach dem Label-String - erlaubt
+	// Breakdown-Abfragen (z.B. confirmed vs. possibly AI) unabhängig davon,
+	// wie das Label gerade formuliert ist.
+	VerdictDistribution map[analyzer.Verdict]int64
+
+	// BatchCount/BatchFileCount/BatchFailureCount verfolgen POST
+	// /upload/batch getrennt von AnalysisCount["batch"]: BatchCount zählt
+	// Batch-Requests, BatchFileCount/BatchFailureCount summieren die
+	// eingereichten bzw. fehlgeschlagenen Einzeldateien über alle Batches.
+	BatchCount        int64
+	BatchFileCount    int64
+	BatchFailureCount int64
 
 	// Additional tracking
 	LastUpdate time.Time
+
+	// qualityHistory hält die letzten qualityHistoryLimit analysis_quality-
+	// Verhältnisse (siehe RecordAnalysisQuality) als Ringpuffer - Grundlage
+	// für alerts.LowQualityStreakRule, das eine Serie niedriger Werte als
+	// möglichen Hinweis auf ausgefallene Detektoren erkennt.
+	qualityHistory []float64
+
+	// DetectorRunCount/DetectorErrorCount zählen je Detektor-Stage (z.B.
+	// "ai-model", "compression") Durchläufe und Fehlschläge - anders als
+	// AnalysisCount/ErrorCount, die nach Request-Art ("pipeline", "upload")
+	// zählen. Füllt sich über pipeline.MetricsRecorder.RecordDetectorResult.
+	DetectorRunCount   map[string]int64
+	DetectorErrorCount map[string]int64
+
+	// EarlyExitReasons zählt Early Exits nach der Stage, die sie ausgelöst
+	// hat ("metadata-quick"/"c2pa") - füllt sich über
+	// pipeline.MetricsRecorder.RecordEarlyExit, getrennt von EarlyExitCount,
+	// das nur die Gesamtzahl führt.
+	EarlyExitReasons map[string]int64
+
+	// stageScoreHistograms verfolgt je Pipeline-Stage eine latencyHistogram
+	// der von deren ScoreExtractor gelieferten Rohscores (siehe
+	// combineStageConfidence in pkg/analyzer/pipeline) - dieselbe
+	// Reservoir+Welford-Struktur wie latencyHistograms, hier aber über Scores
+	// statt Sekunden, da latencyHistogram.Add bereits generisch über float64
+	// arbeitet.
+	stageScoreHistograms map[string]*latencyHistogram
 }
 
+// qualityHistoryLimit begrenzt qualityHistory auf die letzten N Analysen.
+const qualityHistoryLimit = 20
+
 func NewMetrics() *Metrics {
-	return &Metrics{
-		AnalysisCount:    make(map[string]int64),
-		AnalysisDuration: make(map[string][]time.Duration),
-		ErrorCount:       make(map[string]int64),
-		MemoryUsage:      make([]float64, 0),
-		CPUUsage:         make([]float64, 0),
-		LastUpdate:       time.Now(),
+	m := &Metrics{
+		AnalysisCount:        make(map[string]int64),
+		durationMeters:       make(map[string]*rateMeter),
+		latencyHistograms:    make(map[string]*latencyHistogram),
+		ErrorCount:           make(map[string]int64),
+		VerdictCount:         make(map[string]int64),
+		VerdictDistribution:  make(map[analyzer.Verdict]int64),
+		DetectorRunCount:     make(map[string]int64),
+		DetectorErrorCount:   make(map[string]int64),
+		EarlyExitReasons:     make(map[string]int64),
+		stageScoreHistograms: make(map[string]*latencyHistogram),
+		memoryMeter:          newRateMeter(),
+		cpuMeter:             newRateMeter(),
+		LastUpdate:           time.Now(),
+	}
+	for i := range m.visitorBuckets {
+		m.visitorBuckets[i] = newHLL()
+	}
+	return m
+}
+
+// IncrementInFlight/DecrementInFlight verfolgen die tatsächlich gerade
+// laufenden Analysen - im Gegensatz zu ActiveConnections (Dashboard-Klicks)
+// ist das ein valides Signal für Load-Shedding im Upload-Handler.
+func (m *Metrics) IncrementInFlight() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ActiveInFlight++
+}
+
+func (m *Metrics) DecrementInFlight() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ActiveInFlight > 0 {
+		m.ActiveInFlight--
+	}
+}
+
+func (m *Metrics) GetActiveInFlight() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ActiveInFlight
+}
+
+// RecordVisitor fügt den Hash einer (gehashten) Session-Cookie-ID dem
+// aktuellen Minuten-Bucket hinzu.
+func (m *Metrics) RecordVisitor(sessionHash uint64) {
+	m.visitorMu.Lock()
+	defer m.visitorMu.Unlock()
+	m.visitorBuckets[m.visitorHead].add(sessionHash)
+}
+
+// StartVisitorWindowRotation rotiert die Minuten-Buckets im Hintergrund - ruft
+// man einmal beim Serverstart auf.
+func (m *Metrics) StartVisitorWindowRotation(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.visitorMu.Lock()
+				m.visitorHead = (m.visitorHead + 1) % visitorBucketCount
+				m.visitorBuckets[m.visitorHead] = newHLL()
+				m.visitorMu.Unlock()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// GetUniqueVisitors schätzt eindeutige Besucher über die letzten
+// 1/5/60 Minuten-Buckets.
+func (m *Metrics) GetUniqueVisitors() map[string]float64 {
+	m.visitorMu.Lock()
+	defer m.visitorMu.Unlock()
+
+	estimate := func(windowMinutes int) float64 {
+		merged := newHLL()
+		for i := 0; i < windowMinutes; i++ {
+			idx := ((m.visitorHead-i)%visitorBucketCount + visitorBucketCount) % visitorBucketCount
+			merged = merged.merge(m.visitorBuckets[idx])
+		}
+		return merged.estimate()
+	}
+
+	return map[string]float64{
+		"1m": estimate(1),
+		"5m": estimate(5),
+		"1h": estimate(visitorBucketCount),
 	}
 }
 
@@ -110,21 +281,29 @@ func (m *Metrics) UpdateBusinessMetrics(result *analyzer.PipelineResult) {
 	m.LastUpdate = time.Now()
 }
 
-func (m *Metrics) RecordVerdict(verdict string, isEarlyExit bool) {
+// RecordVerdict records a final verdict by its typed analyzer.Verdict enum -
+// replaces the previous string comparison against label variants that
+// determineBalancedVerdict never actually returned (so AIDetectedCount
+// silently undercounted). Callers hold analyzer.PipelineResult.Verdict
+// directly after verdict.CalculateOverallVerdict runs.
+func (m *Metrics) RecordVerdict(verdict analyzer.Verdict, isEarlyExit bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.TotalAnalyses++ // ← MUSS WIEDER AKTIVIERT WERDEN
+	m.TotalAnalyses++
 
-	fmt.Printf("DEBUG RecordVerdict: verdict=%s, TotalAnalyses=%d\n", verdict, m.TotalAnalyses)
+	if m.VerdictCount == nil {
+		m.VerdictCount = make(map[string]int64)
+	}
+	m.VerdictCount[verdict.String()]++
 
-	// Determine if AI based on verdict string
-	if verdict == "AI Generated (Confirmed)" ||
-		verdict == "Very Likely AI Generated" ||
-		verdict == "Likely AI Generated" ||
-		verdict == "Possibly AI Generated" {
+	if m.VerdictDistribution == nil {
+		m.VerdictDistribution = make(map[analyzer.Verdict]int64)
+	}
+	m.VerdictDistribution[verdict]++
+
+	if verdict.IsAIGenerated() {
 		m.AIDetectedCount++
-		fmt.Printf("DEBUG: AI detected! Count now: %d\n", m.AIDetectedCount)
 	}
 
 	if isEarlyExit {
@@ -137,11 +316,18 @@ func (m *Metrics) RecordVerdict(verdict string, isEarlyExit bool) {
 		m.EarlyExitRate = float64(m.EarlyExitCount) / float64(m.TotalAnalyses)
 	}
 
-	fmt.Printf("DEBUG: AI Detection Rate: %.3f (%d/%d)\n", m.AIDetectionRate, m.AIDetectedCount, m.TotalAnalyses)
-
 	m.LastUpdate = time.Now()
 }
 
+// RecordVerdictLabel parses a legacy string verdict label (as still returned
+// by verdict.CalculateOverallVerdict's "verdict" API field) into
+// analyzer.Verdict and forwards to RecordVerdict. Kept for one release for
+// callers that haven't moved to PipelineResult.Verdict yet; remove once none
+// remain.
+func (m *Metrics) RecordVerdictLabel(label string, isEarlyExit bool) {
+	m.RecordVerdict(analyzer.ParseVerdict(label), isEarlyExit)
+}
+
 // Cache tracking methods
 func (m *Metrics) RecordCacheHit() {
 	m.mu.Lock()
@@ -169,48 +355,152 @@ func (m *Metrics) RecordCacheMiss() {
 	}
 }
 
+// durationMeterLocked liefert (und legt bei Bedarf an) den Rate-Meter für
+// analysisType. Muss mit gehaltenem m.mu aufgerufen werden; der Meter selbst
+// hat sein eigenes Mutex, Add() kann also danach ohne m.mu erfolgen.
+func (m *Metrics) durationMeterLocked(analysisType string) *rateMeter {
+	meter, exists := m.durationMeters[analysisType]
+	if !exists {
+		meter = newRateMeter()
+		m.durationMeters[analysisType] = meter
+	}
+	return meter
+}
+
+// latencyHistogramLocked liefert (und legt bei Bedarf an) das
+// latencyHistogram für analysisType. Muss mit gehaltenem m.mu aufgerufen
+// werden.
+func (m *Metrics) latencyHistogramLocked(analysisType string) *latencyHistogram {
+	hist, exists := m.latencyHistograms[analysisType]
+	if !exists {
+		hist = newLatencyHistogram()
+		m.latencyHistograms[analysisType] = hist
+	}
+	return hist
+}
+
 // Performance tracking methods
 func (m *Metrics) RecordAnalysis(analysisType string, duration time.Duration, err error) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	// Count analysis
 	m.AnalysisCount[analysisType]++
-
-	// Record duration
-	if m.AnalysisDuration[analysisType] == nil {
-		m.AnalysisDuration[analysisType] = make([]time.Duration, 0)
+	meter := m.durationMeterLocked(analysisType)
+	hist := m.latencyHistogramLocked(analysisType)
+	if err != nil {
+		m.ErrorCount[analysisType]++
 	}
-	m.AnalysisDuration[analysisType] = append(m.AnalysisDuration[analysisType], duration)
+	m.LastUpdate = time.Now()
+	m.mu.Unlock()
 
-	// Keep only last 100 durations to prevent memory growth
-	if len(m.AnalysisDuration[analysisType]) > 100 {
-		m.AnalysisDuration[analysisType] = m.AnalysisDuration[analysisType][1:]
-	}
+	meter.Add(duration.Seconds())
+	hist.Add(duration.Seconds())
+}
 
-	// Record errors
-	if err != nil {
-		m.ErrorCount[analysisType]++
+// RecordStageDuration verbucht die Laufzeit einer einzelnen Pipeline-Stage
+// unter ihrem Namen - implementiert pipeline.MetricsRecorder.
+// RecordStageDuration und teilt sich die Rate-Meter/Latency-Histogramme
+// (und damit den Prometheus-Export in monitoring/prom) mit RecordAnalysis,
+// das bislang nur von mittlerweile auskommentierten Call-Sites in
+// internal/handlers/upload.go gefüttert wurde.
+func (m *Metrics) RecordStageDuration(name string, duration time.Duration, err error) {
+	m.RecordAnalysis(name, duration, err)
+}
+
+// stageScoreHistogramLocked liefert (und legt bei Bedarf an) das
+// stageScoreHistogram für stage. Muss mit gehaltenem m.mu aufgerufen werden.
+func (m *Metrics) stageScoreHistogramLocked(stage string) *latencyHistogram {
+	hist, exists := m.stageScoreHistograms[stage]
+	if !exists {
+		hist = newLatencyHistogram()
+		m.stageScoreHistograms[stage] = hist
 	}
+	return hist
+}
 
+// RecordStageScore verbucht den von der Stage-ScoreExtractor gelieferten
+// Rohscore - implementiert pipeline.MetricsRecorder.RecordStageScore.
+func (m *Metrics) RecordStageScore(name string, score float64) {
+	m.mu.Lock()
+	hist := m.stageScoreHistogramLocked(name)
 	m.LastUpdate = time.Now()
+	m.mu.Unlock()
+
+	hist.Add(score)
 }
 
-func (m *Metrics) RecordSystemMetrics(memUsage, cpuUsage float64) {
+// RecordEarlyExit verbucht einen Early Exit, gelabelt mit der Stage, die ihn
+// ausgelöst hat - implementiert pipeline.MetricsRecorder.RecordEarlyExit.
+// EarlyExitCount (die bereits über RecordVerdict/RecordAnalysisResult/
+// UpdateBusinessMetrics gezählte Gesamtzahl) bleibt unverändert, damit keine
+// der bestehenden Stellen doppelt zählt.
+func (m *Metrics) RecordEarlyExit(reason string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.MemoryUsage = append(m.MemoryUsage, memUsage)
-	m.CPUUsage = append(m.CPUUsage, cpuUsage)
+	if m.EarlyExitReasons == nil {
+		m.EarlyExitReasons = make(map[string]int64)
+	}
+	m.EarlyExitReasons[reason]++
+	m.LastUpdate = time.Now()
+}
+
+// RecordPipelineDuration verbucht die Gesamtlaufzeit eines RunAnalysis-
+// Aufrufs unter "pipeline_cache_hit" bzw. "pipeline_cache_miss" - implementiert
+// pipeline.MetricsRecorder.RecordPipelineDuration. Getrennt von
+// RecordAnalysis("pipeline", ...), da dessen Call-Sites in
+// internal/handlers/upload.go nicht zwischen Cache-Hit und -Miss
+// unterscheiden.
+func (m *Metrics) RecordPipelineDuration(duration time.Duration, cacheHit bool) {
+	analysisType := "pipeline_cache_miss"
+	if cacheHit {
+		analysisType = "pipeline_cache_hit"
+	}
+	m.RecordAnalysis(analysisType, duration, nil)
+}
 
-	// Keep only last 100 measurements
-	if len(m.MemoryUsage) > 100 {
-		m.MemoryUsage = m.MemoryUsage[1:]
+// GetLatencyPercentiles liefert p50/p75/p95/p99/p999/p9999 für analysisType,
+// geschätzt aus der Reservoir-Stichprobe der letzten latencyReservoirSize
+// Aufrufe. Gibt eine leere Map zurück, falls für analysisType noch keine
+// Analyse aufgezeichnet wurde.
+func (m *Metrics) GetLatencyPercentiles(analysisType string) map[string]time.Duration {
+	m.mu.RLock()
+	hist, exists := m.latencyHistograms[analysisType]
+	m.mu.RUnlock()
+	if !exists {
+		return map[string]time.Duration{}
 	}
-	if len(m.CPUUsage) > 100 {
-		m.CPUUsage = m.CPUUsage[1:]
+	return hist.Stats().Percentile
+}
+
+// GetLatencyStats liefert die vollen Latenz-Statistiken (Perzentile plus
+// Min/Max/Mean/Varianz/StdDev) für analysisType.
+func (m *Metrics) GetLatencyStats(analysisType string) LatencyStats {
+	m.mu.RLock()
+	hist, exists := m.latencyHistograms[analysisType]
+	m.mu.RUnlock()
+	if !exists {
+		return LatencyStats{Percentile: map[string]time.Duration{}}
 	}
+	return hist.Stats()
+}
 
+func (m *Metrics) RecordSystemMetrics(memUsage, cpuUsage float64) {
+	m.mu.Lock()
+	memMeter := m.memoryMeter
+	cpuMeter := m.cpuMeter
+	m.LastUpdate = time.Now()
+	m.mu.Unlock()
+
+	memMeter.Add(memUsage)
+	cpuMeter.Add(cpuUsage)
+}
+
+// RecordCacheStats übernimmt einen Snapshot der zweistufigen Analysis-Cache
+// (Memory+Disk), damit das Dashboard Cache-Wirksamkeit ohne eigene
+// Zählvariablen anzeigen kann.
+func (m *Metrics) RecordCacheStats(stats cache.Stats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CacheStats = stats
 	m.LastUpdate = time.Now()
 }
 
@@ -222,21 +512,20 @@ func (m *Metrics) UpdateActiveConnections(count int64) {
 	m.LastUpdate = time.Now()
 }
 
-// Helper methods for calculations
+// GetAverageDuration liefert die EWMA-geglättete 1m-Rate an
+// Verarbeitungssekunden pro Sekunde für analysisType, als time.Duration
+// ausgedrückt. Das ist keine strikte Mittelung über die letzten N Aufrufe
+// mehr (siehe durationMeters), sondern ein geglätteter Lastwert - für den
+// bisherigen "durchschnittliche Dauer"-Anwendungsfall (Dashboard/API) aber
+// weiterhin die richtige Größe.
 func (m *Metrics) GetAverageDuration(analysisType string) time.Duration {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	durations := m.AnalysisDuration[analysisType]
-	if len(durations) == 0 {
+	meter, exists := m.durationMeters[analysisType]
+	m.mu.RUnlock()
+	if !exists {
 		return 0
 	}
-
-	var total time.Duration
-	for _, d := range durations {
-		total += d
-	}
-	return total / time.Duration(len(durations))
+	return time.Duration(meter.Rate1m() * float64(time.Second))
 }
 
 func (m *Metrics) GetErrorRate(analysisType string) float64 {
@@ -254,22 +543,16 @@ func (m *Metrics) GetErrorRate(analysisType string) float64 {
 
 func (m *Metrics) GetCurrentMemoryUsage() float64 {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	if len(m.MemoryUsage) == 0 {
-		return 0
-	}
-	return m.MemoryUsage[len(m.MemoryUsage)-1]
+	meter := m.memoryMeter
+	m.mu.RUnlock()
+	return meter.Rate1m()
 }
 
 func (m *Metrics) GetCurrentCPUUsage() float64 {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	if len(m.CPUUsage) == 0 {
-		return 0
-	}
-	return m.CPUUsage[len(m.CPUUsage)-1]
+	meter := m.cpuMeter
+	m.mu.RUnlock()
+	return meter.Rate1m()
 }
 
 // Export methods for API
@@ -277,6 +560,11 @@ func (m *Metrics) GetBusinessMetrics() map[string]interface{} {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	verdictBreakdown := make(map[string]int64, len(m.VerdictDistribution))
+	for v, count := range m.VerdictDistribution {
+		verdictBreakdown[v.String()] = count
+	}
+
 	return map[string]interface{}{
 		"ai_detection_rate": m.AIDetectionRate,
 		"cache_hit_rate":    m.CacheHitRate,
@@ -284,6 +572,7 @@ func (m *Metrics) GetBusinessMetrics() map[string]interface{} {
 		"total_analyses":    m.TotalAnalyses,
 		"ai_detected_count": m.AIDetectedCount,
 		"early_exit_count":  m.EarlyExitCount,
+		"verdict_breakdown": verdictBreakdown,
 	}
 }
 
@@ -328,6 +617,7 @@ func (m *Metrics) GetPipelineMetrics() map[string]interface{} {
 		"error_rate":       errorRate,
 		"total_count":      totalCount,
 		"error_count":      errorCount,
+		"latency":          latencyStatsMillis(m.GetLatencyStats("pipeline")),
 	}
 }
 
@@ -345,6 +635,40 @@ func (m *Metrics) GetUploadMetrics() map[string]interface{} {
 		"error_rate":       errorRate,
 		"total_count":      totalCount,
 		"error_count":      errorCount,
+		"latency":          latencyStatsMillis(m.GetLatencyStats("upload")),
+	}
+}
+
+func (m *Metrics) GetBatchMetrics() map[string]interface{} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	avgDuration := m.GetAverageDuration("batch")
+	totalCount := m.AnalysisCount["batch"]
+
+	return map[string]interface{}{
+		"average_duration": avgDuration.Milliseconds(),
+		"total_count":      totalCount,
+		"file_count":       m.BatchFileCount,
+		"failure_count":    m.BatchFailureCount,
+	}
+}
+
+// latencyStatsMillis rendert LatencyStats als JSON-taugliche Millisekunden-
+// Werte, im selben Stil wie das bestehende average_duration-Feld.
+func latencyStatsMillis(stats LatencyStats) map[string]interface{} {
+	percentiles := make(map[string]int64, len(stats.Percentile))
+	for label, d := range stats.Percentile {
+		percentiles[label] = d.Milliseconds()
+	}
+	return map[string]interface{}{
+		"count":       stats.Count,
+		"min":         stats.Min.Milliseconds(),
+		"max":         stats.Max.Milliseconds(),
+		"mean":        stats.Mean.Milliseconds(),
+		"stddev":      stats.StdDev.Milliseconds(),
+		"variance":    stats.Variance,
+		"percentiles": percentiles,
 	}
 }
 
@@ -354,10 +678,15 @@ func (m *Metrics) GetSystemMetrics() map[string]interface{} {
 
 	return map[string]interface{}{
 		"active_connections": m.ActiveConnections,
+		"active_in_flight":   m.GetActiveInFlight(),
+		"unique_visitors":    m.GetUniqueVisitors(),
 		"cache_hits":         m.CacheHits,
 		"cache_misses":       m.CacheMisses,
+		"cache_stats":        m.CacheStats,
 		"memory_usage":       m.GetCurrentMemoryUsage(),
 		"cpu_usage":          m.GetCurrentCPUUsage(),
+		"disk_usage":         m.DiskUsage,
+		"net_io":             m.NetIO,
 	}
 }
 
@@ -397,18 +726,120 @@ func (m *Metrics) GetMetricsSummary() map[string]interface{} {
 		"overall":  m.GetOverallMetrics(),
 		"pipeline": m.GetPipelineMetrics(),
 		"upload":   m.GetUploadMetrics(),
+		"batch":    m.GetBatchMetrics(),
 		"system":   m.GetSystemMetrics(),
 	}
 }
 
+// RateSnapshot ist eine Momentaufnahme der drei EWMA-Fenster eines rateMeter.
+type RateSnapshot struct {
+	Rate1m  float64
+	Rate5m  float64
+	Rate15m float64
+}
+
+func snapshotMeter(meter *rateMeter) RateSnapshot {
+	return RateSnapshot{
+		Rate1m:  meter.Rate1m(),
+		Rate5m:  meter.Rate5m(),
+		Rate15m: meter.Rate15m(),
+	}
+}
+
+// Snapshot ist eine unter RLock kopierte Momentaufnahme aller Zähler/Gauges,
+// die monitoring/prom ohne eigenen Zugriff auf das interne Mutex in
+// Prometheus-Text-Exposition umwandeln kann.
+type Snapshot struct {
+	AnalysisCount     map[string]int64
+	DurationRates     map[string]RateSnapshot
+	LatencyStats      map[string]LatencyStats
+	LatencySamples    map[string][]float64
+	ErrorCount        map[string]int64
+	VerdictCount      map[string]int64
+	EarlyExitReasons  map[string]int64
+	StageScoreSamples map[string][]float64
+	CacheHits         int64
+	CacheMisses       int64
+	ActiveConnections int64
+	ActiveInFlight    int64
+	MemoryUsageRate   RateSnapshot
+	CPUUsageRate      RateSnapshot
+	DiskUsage         DiskUsageStats
+	NetIO             NetIOStats
+}
+
+func (m *Metrics) Snapshot() Snapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	analysisCount := make(map[string]int64, len(m.AnalysisCount))
+	for k, v := range m.AnalysisCount {
+		analysisCount[k] = v
+	}
+
+	durationRates := make(map[string]RateSnapshot, len(m.durationMeters))
+	for k, meter := range m.durationMeters {
+		durationRates[k] = snapshotMeter(meter)
+	}
+
+	latencyStats := make(map[string]LatencyStats, len(m.latencyHistograms))
+	latencySamples := make(map[string][]float64, len(m.latencyHistograms))
+	for k, hist := range m.latencyHistograms {
+		latencyStats[k] = hist.Stats()
+		latencySamples[k] = hist.Samples()
+	}
+
+	errorCount := make(map[string]int64, len(m.ErrorCount))
+	for k, v := range m.ErrorCount {
+		errorCount[k] = v
+	}
+
+	verdictCount := make(map[string]int64, len(m.VerdictCount))
+	for k, v := range m.VerdictCount {
+		verdictCount[k] = v
+	}
+
+	earlyExitReasons := make(map[string]int64, len(m.EarlyExitReasons))
+	for k, v := range m.EarlyExitReasons {
+		earlyExitReasons[k] = v
+	}
+
+	stageScoreSamples := make(map[string][]float64, len(m.stageScoreHistograms))
+	for k, hist := range m.stageScoreHistograms {
+		stageScoreSamples[k] = hist.Samples()
+	}
+
+	return Snapshot{
+		AnalysisCount:     analysisCount,
+		DurationRates:     durationRates,
+		LatencyStats:      latencyStats,
+		LatencySamples:    latencySamples,
+		ErrorCount:        errorCount,
+		VerdictCount:      verdictCount,
+		EarlyExitReasons:  earlyExitReasons,
+		StageScoreSamples: stageScoreSamples,
+		CacheHits:         m.CacheHits,
+		CacheMisses:       m.CacheMisses,
+		ActiveConnections: m.ActiveConnections,
+		ActiveInFlight:    m.ActiveInFlight,
+		MemoryUsageRate:   snapshotMeter(m.memoryMeter),
+		CPUUsageRate:      snapshotMeter(m.cpuMeter),
+		DiskUsage:         m.DiskUsage,
+		NetIO:             m.NetIO,
+	}
+}
+
 // Reset metrics (useful for testing or periodic resets)
 func (m *Metrics) Reset() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	m.AnalysisCount = make(map[string]int64)
-	m.AnalysisDuration = make(map[string][]time.Duration)
+	m.durationMeters = make(map[string]*rateMeter)
+	m.latencyHistograms = make(map[string]*latencyHistogram)
+	m.stageScoreHistograms = make(map[string]*latencyHistogram)
 	m.ErrorCount = make(map[string]int64)
+	m.EarlyExitReasons = make(map[string]int64)
 	m.TotalAnalyses = 0
 	m.AIDetectedCount = 0
 	m.EarlyExitCount = 0
@@ -417,8 +848,8 @@ func (m *Metrics) Reset() {
 	m.CacheHitRate = 0
 	m.CacheHits = 0
 	m.CacheMisses = 0
-	m.MemoryUsage = make([]float64, 0)
-	m.CPUUsage = make([]float64, 0)
+	m.memoryMeter = newRateMeter()
+	m.cpuMeter = newRateMeter()
 	m.ActiveConnections = 0
 	m.LastUpdate = time.Now()
 }
@@ -464,20 +895,102 @@ func (m *Metrics) RecordSuccess(category string) {
 
 // Duration tracking methods - HINZUFÜGEN
 func (m *Metrics) RecordDuration(category string, duration time.Duration) {
+	m.mu.Lock()
+	if m.durationMeters == nil {
+		m.durationMeters = make(map[string]*rateMeter)
+	}
+	meter := m.durationMeterLocked(category)
+	m.LastUpdate = time.Now()
+	m.mu.Unlock()
+
+	meter.Add(duration.Seconds())
+}
+
+// RecordDetectorResult verbucht einen Durchlauf der Detektor-Stage name -
+// implementiert pipeline.MetricsRecorder.RecordDetectorResult.
+func (m *Metrics) RecordDetectorResult(name string, err error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if m.AnalysisDuration == nil {
-		m.AnalysisDuration = make(map[string][]time.Duration)
+	if m.DetectorRunCount == nil {
+		m.DetectorRunCount = make(map[string]int64)
+	}
+	if m.DetectorErrorCount == nil {
+		m.DetectorErrorCount = make(map[string]int64)
+	}
+	m.DetectorRunCount[name]++
+	if err != nil {
+		m.DetectorErrorCount[name]++
 	}
+	m.LastUpdate = time.Now()
+}
+
+// GetDetectorFailureRate liefert den Anteil fehlgeschlagener Durchläufe der
+// Detektor-Stage name seit dem letzten Reset, oder 0, falls sie noch nie
+// gelaufen ist.
+func (m *Metrics) GetDetectorFailureRate(name string) float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
-	// Keep only last 100 durations for memory efficiency
-	durations := m.AnalysisDuration[category]
-	if len(durations) >= 100 {
-		durations = durations[1:]
+	total := m.DetectorRunCount[name]
+	if total == 0 {
+		return 0
+	}
+	return float64(m.DetectorErrorCount[name]) / float64(total)
+}
+
+// RecordAnalysisQuality verbucht das analysis_quality-Verhältnis (siehe
+// verdict.CalculateOverallVerdict) eines abgeschlossenen Requests im
+// qualityHistory-Ringpuffer - verdrahtet über verdict.OnVerdictQuality, da
+// das verdict-Paket monitoring nicht importiert.
+func (m *Metrics) RecordAnalysisQuality(ratio float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.qualityHistory = append(m.qualityHistory, ratio)
+	if len(m.qualityHistory) > qualityHistoryLimit {
+		m.qualityHistory = m.qualityHistory[len(m.qualityHistory)-qualityHistoryLimit:]
+	}
+	m.LastUpdate = time.Now()
+}
+
+// LowQualityStreak liefert, wie viele der zuletzt aufgezeichneten Analysen in
+// Folge (ausgehend vom jüngsten Wert) eine analysis_quality unter threshold
+// hatten - 0, falls der jüngste Wert bereits darüber liegt oder noch keine
+// Analyse aufgezeichnet wurde.
+func (m *Metrics) LowQualityStreak(threshold float64) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	streak := 0
+	for i := len(m.qualityHistory) - 1; i >= 0; i-- {
+		if m.qualityHistory[i] >= threshold {
+			break
+		}
+		streak++
 	}
-	durations = append(durations, duration)
-	m.AnalysisDuration[category] = durations
+	return streak
+}
 
+// RecordBatch verbucht einen abgeschlossenen POST /upload/batch-Request:
+// size ist die Anzahl eingereichter Dateien, failureCount die Anzahl
+// davon, die einzeln fehlgeschlagen sind (ValidateFile/ValidateFileContent
+// oder RunSecureAnalyses) - eine einzelne fehlgeschlagene Datei lässt den
+// restlichen Batch weiterlaufen, zählt hier aber getrennt von
+// AnalysisCount["batch"]/ErrorCount["batch"], die den Request als Ganzes
+// betreffen.
+func (m *Metrics) RecordBatch(size int, duration time.Duration, failureCount int) {
+	m.mu.Lock()
+	m.BatchCount++
+	m.BatchFileCount += int64(size)
+	m.BatchFailureCount += int64(failureCount)
+	if m.AnalysisCount == nil {
+		m.AnalysisCount = make(map[string]int64)
+	}
+	m.AnalysisCount["batch"]++
+	meter := m.durationMeterLocked("batch")
 	m.LastUpdate = time.Now()
+	m.mu.Unlock()
+
+	meter.Add(duration.Seconds())
 }
@@ -0,0 +1,214 @@
+// Package prom rendert einen monitoring.Metrics-Snapshot als
+// Prometheus-Text-Exposition (https://prometheus.io/docs/instrumenting/exposition_formats/).
+// Es ersetzt die zuvor direkt in dashboard/handlers.go verdrahteten
+// fmt.Fprintf-Zeilen durch eine eigenständige, wiederverwendbare Registry, die
+// zusätzlich nach analysis_type und verdict gelabelte Counter, EWMA-Rate-
+// Gauges (1m/5m/15m) für AnalysisDuration/Memory/CPU, sowie ein
+// Latenz-Histogramm je Pipeline-Stage exportiert.
+package prom
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/BramseDev/imageAnalyzer/monitoring"
+)
+
+// durationBucketsSeconds sind die kumulativen "le"-Grenzen für das
+// Analysis-Duration-Histogramm - grob an den typischen Laufzeiten der
+// langsameren Analyzer (Pixel-/Artefakt-Analyse) orientiert. Gespeist wird
+// es aus der gekappten Reservoir-Stichprobe in monitoring.LatencySamples,
+// nicht aus unbounded Rohdaten.
+var durationBucketsSeconds = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// rateWindows sind die von monitoring.RateSnapshot gelieferten EWMA-Fenster,
+// je mit dem Label-Wert, unter dem sie als "window" exponiert werden.
+var rateWindows = []struct {
+	label string
+	value func(monitoring.RateSnapshot) float64
+}{
+	{"1m", func(r monitoring.RateSnapshot) float64 { return r.Rate1m }},
+	{"5m", func(r monitoring.RateSnapshot) float64 { return r.Rate5m }},
+	{"15m", func(r monitoring.RateSnapshot) float64 { return r.Rate15m }},
+}
+
+// Registry wrappt einen monitoring.Metrics und exponiert dessen Zähler/Gauges
+// im Prometheus-Textformat. Mehrere Registries können denselben Metrics
+// sicher teilen, da Snapshot() bereits unter RLock kopiert.
+type Registry struct {
+	metrics *monitoring.Metrics
+}
+
+// NewRegistry erstellt eine Registry für metrics.
+func NewRegistry(metrics *monitoring.Metrics) *Registry {
+	return &Registry{metrics: metrics}
+}
+
+// WriteTo schreibt den aktuellen Metrics-Stand im Prometheus-Textformat nach
+// w - geeignet, um direkt in einen http.ResponseWriter zu schreiben.
+func (r *Registry) WriteTo(w io.Writer) error {
+	snap := r.metrics.Snapshot()
+
+	fmt.Fprintf(w, "# HELP analyzer_analyses_total Analyses run, labeled by pipeline stage.\n")
+	fmt.Fprintf(w, "# TYPE analyzer_analyses_total counter\n")
+	for _, name := range sortedInt64Keys(snap.AnalysisCount) {
+		fmt.Fprintf(w, "analyzer_analyses_total{analysis_type=%q} %d\n", name, snap.AnalysisCount[name])
+	}
+
+	fmt.Fprintf(w, "# HELP analyzer_analysis_errors_total Analysis errors, labeled by pipeline stage.\n")
+	fmt.Fprintf(w, "# TYPE analyzer_analysis_errors_total counter\n")
+	for _, name := range sortedInt64Keys(snap.ErrorCount) {
+		fmt.Fprintf(w, "analyzer_analysis_errors_total{analysis_type=%q} %d\n", name, snap.ErrorCount[name])
+	}
+
+	fmt.Fprintf(w, "# HELP analyzer_verdicts_total Final verdicts returned to clients, labeled by verdict band.\n")
+	fmt.Fprintf(w, "# TYPE analyzer_verdicts_total counter\n")
+	for _, name := range sortedInt64Keys(snap.VerdictCount) {
+		fmt.Fprintf(w, "analyzer_verdicts_total{verdict=%q} %d\n", name, snap.VerdictCount[name])
+	}
+
+	fmt.Fprintf(w, "# HELP analyzer_early_exits_total Early exits, labeled by the stage that triggered them.\n")
+	fmt.Fprintf(w, "# TYPE analyzer_early_exits_total counter\n")
+	for _, reason := range sortedInt64Keys(snap.EarlyExitReasons) {
+		fmt.Fprintf(w, "analyzer_early_exits_total{reason=%q} %d\n", reason, snap.EarlyExitReasons[reason])
+	}
+
+	fmt.Fprintf(w, "# HELP analyzer_cache_hits_total Analysis cache hits.\n")
+	fmt.Fprintf(w, "# TYPE analyzer_cache_hits_total counter\n")
+	fmt.Fprintf(w, "analyzer_cache_hits_total %d\n", snap.CacheHits)
+
+	fmt.Fprintf(w, "# HELP analyzer_cache_misses_total Analysis cache misses.\n")
+	fmt.Fprintf(w, "# TYPE analyzer_cache_misses_total counter\n")
+	fmt.Fprintf(w, "analyzer_cache_misses_total %d\n", snap.CacheMisses)
+
+	fmt.Fprintf(w, "# HELP analyzer_active_connections Currently open dashboard connections.\n")
+	fmt.Fprintf(w, "# TYPE analyzer_active_connections gauge\n")
+	fmt.Fprintf(w, "analyzer_active_connections %d\n", snap.ActiveConnections)
+
+	fmt.Fprintf(w, "# HELP analyzer_active_in_flight Analyses currently being processed.\n")
+	fmt.Fprintf(w, "# TYPE analyzer_active_in_flight gauge\n")
+	fmt.Fprintf(w, "analyzer_active_in_flight %d\n", snap.ActiveInFlight)
+
+	fmt.Fprintf(w, "# HELP analyzer_memory_usage_rate EWMA-geglättete Gauge-Rate der Memory-Usage-Messwerte.\n")
+	fmt.Fprintf(w, "# TYPE analyzer_memory_usage_rate gauge\n")
+	writeRateSnapshot(w, "analyzer_memory_usage_rate", "", snap.MemoryUsageRate)
+
+	fmt.Fprintf(w, "# HELP analyzer_cpu_usage_rate EWMA-geglättete Gauge-Rate der CPU-Usage-Messwerte.\n")
+	fmt.Fprintf(w, "# TYPE analyzer_cpu_usage_rate gauge\n")
+	writeRateSnapshot(w, "analyzer_cpu_usage_rate", "", snap.CPUUsageRate)
+
+	fmt.Fprintf(w, "# HELP analyzer_disk_used_bytes Belegter Plattenplatz im Analysis-Cache-Verzeichnis, vom SystemSampler gesampelt.\n")
+	fmt.Fprintf(w, "# TYPE analyzer_disk_used_bytes gauge\n")
+	fmt.Fprintf(w, "analyzer_disk_used_bytes %d\n", snap.DiskUsage.UsedBytes)
+
+	fmt.Fprintf(w, "# HELP analyzer_disk_total_bytes Gesamtgröße des Dateisystems, das das Analysis-Cache-Verzeichnis trägt.\n")
+	fmt.Fprintf(w, "# TYPE analyzer_disk_total_bytes gauge\n")
+	fmt.Fprintf(w, "analyzer_disk_total_bytes %d\n", snap.DiskUsage.TotalBytes)
+
+	fmt.Fprintf(w, "# HELP analyzer_net_bytes_received_total Kumulativ vom Host empfangene Netzwerk-Bytes (erster Interface-Zähler von gopsutil).\n")
+	fmt.Fprintf(w, "# TYPE analyzer_net_bytes_received_total counter\n")
+	fmt.Fprintf(w, "analyzer_net_bytes_received_total %d\n", snap.NetIO.BytesRecv)
+
+	fmt.Fprintf(w, "# HELP analyzer_net_bytes_sent_total Kumulativ vom Host gesendete Netzwerk-Bytes (erster Interface-Zähler von gopsutil).\n")
+	fmt.Fprintf(w, "# TYPE analyzer_net_bytes_sent_total counter\n")
+	fmt.Fprintf(w, "analyzer_net_bytes_sent_total %d\n", snap.NetIO.BytesSent)
+
+	fmt.Fprintf(w, "# HELP analyzer_analysis_duration_seconds_rate EWMA-geglättete Rate an Verarbeitungssekunden pro Sekunde, je Pipeline-Stage und Fenster (1m/5m/15m).\n")
+	fmt.Fprintf(w, "# TYPE analyzer_analysis_duration_seconds_rate gauge\n")
+	for _, name := range sortedRateKeys(snap.DurationRates) {
+		writeRateSnapshot(w, "analyzer_analysis_duration_seconds_rate", name, snap.DurationRates[name])
+	}
+
+	fmt.Fprintf(w, "# HELP analyzer_analysis_duration_seconds Analysis duration per pipeline stage, aus einer Reservoir-Stichprobe der letzten Aufrufe.\n")
+	fmt.Fprintf(w, "# TYPE analyzer_analysis_duration_seconds histogram\n")
+	for _, name := range sortedSampleKeys(snap.LatencySamples) {
+		writeHistogram(w, name, snap.LatencySamples[name])
+	}
+
+	fmt.Fprintf(w, "# HELP analyzer_stage_score_mean Mittlerer von ScoreExtractor gelieferter Rohscore je Pipeline-Stage, aus einer Reservoir-Stichprobe der letzten Aufrufe.\n")
+	fmt.Fprintf(w, "# TYPE analyzer_stage_score_mean gauge\n")
+	for _, name := range sortedSampleKeys(snap.StageScoreSamples) {
+		fmt.Fprintf(w, "analyzer_stage_score_mean{stage=%q} %v\n", name, meanOf(snap.StageScoreSamples[name]))
+	}
+
+	return nil
+}
+
+// meanOf liefert den arithmetischen Mittelwert von samples, oder 0 für eine
+// Stage, die noch keinen Score geliefert hat.
+func meanOf(samples []float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range samples {
+		sum += v
+	}
+	return sum / float64(len(samples))
+}
+
+// writeHistogram schreibt ein kumulatives Prometheus-Histogramm für die
+// Reservoir-Stichprobe eines einzelnen analysisType.
+func writeHistogram(w io.Writer, analysisType string, samplesSeconds []float64) {
+	counts := make([]int64, len(durationBucketsSeconds))
+	var sum float64
+
+	for _, seconds := range samplesSeconds {
+		sum += seconds
+		for i, bound := range durationBucketsSeconds {
+			if seconds <= bound {
+				counts[i]++
+			}
+		}
+	}
+
+	for i, bound := range durationBucketsSeconds {
+		le := strconv.FormatFloat(bound, 'f', -1, 64)
+		fmt.Fprintf(w, "analyzer_analysis_duration_seconds_bucket{analysis_type=%q,le=%q} %d\n", analysisType, le, counts[i])
+	}
+	fmt.Fprintf(w, "analyzer_analysis_duration_seconds_bucket{analysis_type=%q,le=\"+Inf\"} %d\n", analysisType, len(samplesSeconds))
+	fmt.Fprintf(w, "analyzer_analysis_duration_seconds_sum{analysis_type=%q} %v\n", analysisType, sum)
+	fmt.Fprintf(w, "analyzer_analysis_duration_seconds_count{analysis_type=%q} %d\n", analysisType, len(samplesSeconds))
+}
+
+func sortedSampleKeys(m map[string][]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeRateSnapshot schreibt die drei EWMA-Fenster (1m/5m/15m) eines
+// RateSnapshot als gelabelte Gauge-Zeilen. Ist analysisType leer, wird das
+// analysis_type-Label weggelassen (z.B. für die globalen Memory/CPU-Gauges).
+func writeRateSnapshot(w io.Writer, metric, analysisType string, rate monitoring.RateSnapshot) {
+	for _, window := range rateWindows {
+		if analysisType == "" {
+			fmt.Fprintf(w, "%s{window=%q} %v\n", metric, window.label, window.value(rate))
+		} else {
+			fmt.Fprintf(w, "%s{analysis_type=%q,window=%q} %v\n", metric, analysisType, window.label, window.value(rate))
+		}
+	}
+}
+
+func sortedInt64Keys(m map[string]int64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedRateKeys(m map[string]monitoring.RateSnapshot) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
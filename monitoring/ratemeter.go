@@ -0,0 +1,101 @@
+package monitoring
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// rateMeterWindow ist das Intervall, nach dem ein angesammeltes Fenster in
+// die gleitenden Durchschnitte eingerechnet wird.
+const rateMeterWindow = time.Second
+
+// betaFor leitet das EWMA-Gewicht pro Tick für ein Mittel mit der gegebenen
+// Fenstergröße her, sodass avgNm ungefähr die letzten N Minuten an
+// 1s-Fenster-Updates gewichtet.
+func betaFor(window time.Duration) float64 {
+	return 1 - math.Exp(-rateMeterWindow.Seconds()/window.Seconds())
+}
+
+var (
+	rateMeterBeta1m  = betaFor(time.Minute)
+	rateMeterBeta5m  = betaFor(5 * time.Minute)
+	rateMeterBeta15m = betaFor(15 * time.Minute)
+)
+
+// rateMeter ist ein EWMA-basierter Raten-Messer nach dem bei MinIO für
+// Byte-Durchsatz verwendeten Muster: rohe Werte sammeln sich in einem kurzen
+// Fenster (rateMeterWindow); sobald das Fenster abläuft, wird
+// instantRate = windowSum/elapsed gebildet und per
+// avgNm = beta*instantRate + (1-beta)*avgNm in drei gleitende Durchschnitte
+// (1m/5m/15m) eingerechnet. Das liefert stabile Raten, ohne wie ein
+// kapptes []T-Ringpuffer bei jedem Aufruf einen Slice-Shift zu verursachen.
+type rateMeter struct {
+	mu sync.Mutex
+
+	windowStart time.Time
+	windowSum   float64
+
+	avg1m, avg5m, avg15m float64
+	seeded               bool
+}
+
+func newRateMeter() *rateMeter {
+	return &rateMeter{windowStart: time.Now()}
+}
+
+// Add akkumuliert value (z.B. eine Dauer in Sekunden, eine Gauge-Messung,
+// ein einzelnes Ereignis mit Gewicht 1) in das aktuelle Fenster.
+func (r *rateMeter) Add(value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.windowSum += value
+	r.rollLocked()
+}
+
+// rollLocked rechnet ein abgelaufenes Fenster in die gleitenden Durchschnitte
+// ein. Muss mit gehaltenem r.mu aufgerufen werden.
+func (r *rateMeter) rollLocked() {
+	elapsed := time.Since(r.windowStart)
+	if elapsed < rateMeterWindow {
+		return
+	}
+
+	instantRate := r.windowSum / elapsed.Seconds()
+	if !r.seeded {
+		r.avg1m, r.avg5m, r.avg15m = instantRate, instantRate, instantRate
+		r.seeded = true
+	} else {
+		r.avg1m = rateMeterBeta1m*instantRate + (1-rateMeterBeta1m)*r.avg1m
+		r.avg5m = rateMeterBeta5m*instantRate + (1-rateMeterBeta5m)*r.avg5m
+		r.avg15m = rateMeterBeta15m*instantRate + (1-rateMeterBeta15m)*r.avg15m
+	}
+
+	r.windowSum = 0
+	r.windowStart = time.Now()
+}
+
+// Rate1m/Rate5m/Rate15m liefern den aktuellen gleitenden Durchschnitt über
+// das jeweilige Fenster (Werte pro Sekunde). Ein noch offenes Fenster wird
+// zuerst eingerechnet, damit seit dem letzten Tick hinzugefügte Werte
+// sichtbar sind.
+func (r *rateMeter) Rate1m() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rollLocked()
+	return r.avg1m
+}
+
+func (r *rateMeter) Rate5m() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rollLocked()
+	return r.avg5m
+}
+
+func (r *rateMeter) Rate15m() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rollLocked()
+	return r.avg15m
+}
@@ -0,0 +1,43 @@
+package monitoring
+
+import (
+	"context"
+	"time"
+)
+
+// MetricsReporter schiebt einen Snapshot periodisch in ein externes System -
+// eine Alternative zum Scrapen von monitoring/prom, für Operator, die bereits
+// eine TSDB (InfluxDB, StatsD/DogStatsD, ...) betreiben. Konkrete
+// Implementierungen liegen unter monitoring/reporter, um monitoring selbst
+// frei von TSDB-Client-Abhängigkeiten zu halten.
+type MetricsReporter interface {
+	// Report sendet snapshot an das Zielsystem. Fehler werden von
+	// RegisterReporter nur geloggt, nicht weitergereicht - ein einzelner
+	// fehlgeschlagener Push soll den nächsten Tick nicht verhindern.
+	Report(snapshot Snapshot) error
+	// Close gibt die vom Reporter gehaltenen Ressourcen frei (Verbindungen,
+	// Dateihandles, ...). Wird einmal aufgerufen, wenn RegisterReporter
+	// stoppt.
+	Close() error
+}
+
+// RegisterReporter startet eine Hintergrund-Goroutine, die alle interval
+// einen unter m.mu.RLock gezogenen Snapshot (siehe Metrics.Snapshot) an
+// reporter schickt, bis ctx storniert wird - analog zu StartSystemSampler und
+// StartVisitorWindowRotation. reporter.Close() läuft genau einmal beim
+// Stoppen.
+func (m *Metrics) RegisterReporter(ctx context.Context, reporter MetricsReporter, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		defer reporter.Close()
+		for {
+			select {
+			case <-ticker.C:
+				reporter.Report(m.Snapshot())
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
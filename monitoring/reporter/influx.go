@@ -0,0 +1,132 @@
+// Package reporter enthält monitoring.MetricsReporter-Implementierungen für
+// externe TSDBs (InfluxDB, StatsD/DogStatsD), getrennt von monitoring selbst,
+// damit das Kernpaket keine TSDB-Client-Abhängigkeiten zieht.
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BramseDev/imageAnalyzer/monitoring"
+)
+
+// InfluxReporter schreibt einen monitoring.Snapshot als InfluxDB-Line-Protocol
+// (https://docs.influxdata.com/influxdb/v2/reference/syntax/line-protocol/)
+// per HTTP-Write-API.
+type InfluxReporter struct {
+	writeURL string
+	token    string
+	client   *http.Client
+}
+
+// NewInfluxReporter erstellt einen Reporter, der Line-Protocol-Batches per
+// POST an writeURL schickt - z.B.
+// "http://influx:8086/api/v2/write?org=myorg&bucket=analyzer&precision=s".
+// token wird, falls gesetzt, als "Authorization: Token <token>" Header
+// mitgeschickt (InfluxDB v2 Auth).
+func NewInfluxReporter(writeURL, token string) *InfluxReporter {
+	return &InfluxReporter{
+		writeURL: writeURL,
+		token:    token,
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Report rendert snapshot als Line-Protocol und POSTet es an r.writeURL.
+func (r *InfluxReporter) Report(snapshot monitoring.Snapshot) error {
+	var buf bytes.Buffer
+
+	for _, name := range sortedInt64Keys(snapshot.AnalysisCount) {
+		fmt.Fprintf(&buf, "analyzer_analyses_total,analysis_type=%s count=%di\n", escapeTag(name), snapshot.AnalysisCount[name])
+	}
+	for _, name := range sortedInt64Keys(snapshot.ErrorCount) {
+		fmt.Fprintf(&buf, "analyzer_analysis_errors_total,analysis_type=%s count=%di\n", escapeTag(name), snapshot.ErrorCount[name])
+	}
+	for _, name := range sortedInt64Keys(snapshot.VerdictCount) {
+		fmt.Fprintf(&buf, "analyzer_verdicts_total,verdict=%s count=%di\n", escapeTag(name), snapshot.VerdictCount[name])
+	}
+
+	fmt.Fprintf(&buf, "analyzer_cache cache_hits=%di,cache_misses=%di\n", snapshot.CacheHits, snapshot.CacheMisses)
+	fmt.Fprintf(&buf, "analyzer_connections active_connections=%di,active_in_flight=%di\n", snapshot.ActiveConnections, snapshot.ActiveInFlight)
+	fmt.Fprintf(&buf, "analyzer_memory_usage_rate rate1m=%s,rate5m=%s,rate15m=%s\n",
+		formatFloat(snapshot.MemoryUsageRate.Rate1m), formatFloat(snapshot.MemoryUsageRate.Rate5m), formatFloat(snapshot.MemoryUsageRate.Rate15m))
+	fmt.Fprintf(&buf, "analyzer_cpu_usage_rate rate1m=%s,rate5m=%s,rate15m=%s\n",
+		formatFloat(snapshot.CPUUsageRate.Rate1m), formatFloat(snapshot.CPUUsageRate.Rate5m), formatFloat(snapshot.CPUUsageRate.Rate15m))
+	fmt.Fprintf(&buf, "analyzer_disk disk_used_bytes=%di,disk_total_bytes=%di\n", snapshot.DiskUsage.UsedBytes, snapshot.DiskUsage.TotalBytes)
+	fmt.Fprintf(&buf, "analyzer_net net_bytes_received=%di,net_bytes_sent=%di\n", snapshot.NetIO.BytesRecv, snapshot.NetIO.BytesSent)
+
+	for _, name := range sortedStatsKeys(snapshot.LatencyStats) {
+		stats := snapshot.LatencyStats[name]
+		fields := make([]string, 0, len(influxLatencyPercentiles))
+		for _, p := range influxLatencyPercentiles {
+			if d, ok := stats.Percentile[p]; ok {
+				fields = append(fields, fmt.Sprintf("%s=%s", p, formatFloat(d.Seconds())))
+			}
+		}
+		fmt.Fprintf(&buf, "analyzer_analysis_latency_seconds,analysis_type=%s %s\n", escapeTag(name), strings.Join(fields, ","))
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.writeURL, &buf)
+	if err != nil {
+		return fmt.Errorf("build influx write request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Token "+r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("influx write: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("influx write failed: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close gibt die vom http.Client gehaltenen idle Connections frei.
+func (r *InfluxReporter) Close() error {
+	r.client.CloseIdleConnections()
+	return nil
+}
+
+// influxLatencyPercentiles sind die in der Request geforderten Perzentile -
+// eine Teilmenge der von monitoring.LatencyStats gelieferten p50..p9999.
+var influxLatencyPercentiles = []string{"p50", "p75", "p95", "p99", "p999"}
+
+func sortedInt64Keys(m map[string]int64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedStatsKeys(m map[string]monitoring.LatencyStats) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escapeTag escaped die von InfluxDB Line-Protocol für Tag-Keys/-Values
+// reservierten Zeichen (Komma, Gleichheitszeichen, Leerzeichen).
+func escapeTag(s string) string {
+	replacer := strings.NewReplacer(",", "\\,", "=", "\\=", " ", "\\ ")
+	return replacer.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,87 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/BramseDev/imageAnalyzer/monitoring"
+)
+
+// StatsDReporter schreibt einen monitoring.Snapshot als StatsD/DogStatsD-
+// Paket (https://github.com/statsd/statsd/blob/master/docs/metric_types.md)
+// per UDP - Counter als "|c", Gauges als "|g", Latenz-Perzentile als "|ms".
+type StatsDReporter struct {
+	prefix string
+	conn   net.Conn
+}
+
+// NewStatsDReporter verbindet sich (verbindungslos, UDP hat keinen Handshake)
+// mit addr ("host:port") und präfixt jede Metrik mit prefix (z.B.
+// "imageanalyzer").
+func NewStatsDReporter(addr, prefix string) (*StatsDReporter, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial statsd at %s: %w", addr, err)
+	}
+	return &StatsDReporter{prefix: prefix, conn: conn}, nil
+}
+
+// Report rendert snapshot als StatsD-Paket und schreibt es in einem UDP-Write
+// an r.conn.
+func (r *StatsDReporter) Report(snapshot monitoring.Snapshot) error {
+	var buf bytes.Buffer
+
+	metric := func(name, value, kind string) {
+		fmt.Fprintf(&buf, "%s.%s:%s|%s\n", r.prefix, name, value, kind)
+	}
+
+	for _, name := range sortedInt64Keys(snapshot.AnalysisCount) {
+		metric(fmt.Sprintf("analyses_total.%s", sanitizeTag(name)), fmt.Sprintf("%d", snapshot.AnalysisCount[name]), "c")
+	}
+	for _, name := range sortedInt64Keys(snapshot.ErrorCount) {
+		metric(fmt.Sprintf("analysis_errors_total.%s", sanitizeTag(name)), fmt.Sprintf("%d", snapshot.ErrorCount[name]), "c")
+	}
+	for _, name := range sortedInt64Keys(snapshot.VerdictCount) {
+		metric(fmt.Sprintf("verdicts_total.%s", sanitizeTag(name)), fmt.Sprintf("%d", snapshot.VerdictCount[name]), "c")
+	}
+
+	metric("cache_hits_total", fmt.Sprintf("%d", snapshot.CacheHits), "c")
+	metric("cache_misses_total", fmt.Sprintf("%d", snapshot.CacheMisses), "c")
+	metric("active_connections", fmt.Sprintf("%d", snapshot.ActiveConnections), "g")
+	metric("active_in_flight", fmt.Sprintf("%d", snapshot.ActiveInFlight), "g")
+	metric("memory_usage_rate", formatFloat(snapshot.MemoryUsageRate.Rate1m), "g")
+	metric("cpu_usage_rate", formatFloat(snapshot.CPUUsageRate.Rate1m), "g")
+	metric("disk_used_bytes", fmt.Sprintf("%d", snapshot.DiskUsage.UsedBytes), "g")
+	metric("net_bytes_received", fmt.Sprintf("%d", snapshot.NetIO.BytesRecv), "g")
+	metric("net_bytes_sent", fmt.Sprintf("%d", snapshot.NetIO.BytesSent), "g")
+
+	for _, name := range sortedStatsKeys(snapshot.LatencyStats) {
+		stats := snapshot.LatencyStats[name]
+		for _, p := range influxLatencyPercentiles {
+			if d, ok := stats.Percentile[p]; ok {
+				metric(fmt.Sprintf("analysis_latency_ms.%s.%s", sanitizeTag(name), p), formatFloat(float64(d.Milliseconds())), "ms")
+			}
+		}
+	}
+
+	_, err := r.conn.Write(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("statsd write: %w", err)
+	}
+	return nil
+}
+
+// Close schließt die UDP-Verbindung.
+func (r *StatsDReporter) Close() error {
+	return r.conn.Close()
+}
+
+// sanitizeTag ersetzt Zeichen, die StatsD-Implementierungen üblicherweise als
+// Namens-Separatoren interpretieren ('.', ':', '|'), damit Analysis-Type-/
+// Verdict-Label nicht versehentlich zusätzliche Metrik-Segmente erzeugen.
+func sanitizeTag(s string) string {
+	replacer := strings.NewReplacer(".", "_", ":", "_", "|", "_", " ", "_")
+	return replacer.Replace(s)
+}
@@ -0,0 +1,185 @@
+package monitoring
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+	gopsnet "github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// defaultSystemSampleDir spiegelt den Default-Spill-Pfad aus
+// cache.NewAnalysisCache - es gibt keine gemeinsame Konstante, da monitoring
+// den Pfad nur zum Platzmessen braucht, nicht zum Schreiben.
+var defaultSystemSampleDir = filepath.Join(os.TempDir(), "analyzer-cache")
+
+// SystemSample ist eine einzelne Momentaufnahme der von systemCollector
+// gelieferten Host-/Prozess-Messwerte.
+type SystemSample struct {
+	ProcessRSSBytes   uint64
+	VirtualMemPercent float64
+	CPUPercentTotal   float64
+	CPUPercentPerCPU  []float64
+	DiskUsedBytes     uint64
+	DiskTotalBytes    uint64
+	DiskUsedPercent   float64
+	NetBytesRecv      uint64
+	NetBytesSent      uint64
+}
+
+// DiskUsageStats ist der zuletzt gesampelte Plattenbelegungsstand des
+// Upload-/Cache-Verzeichnisses.
+type DiskUsageStats struct {
+	UsedBytes   uint64
+	TotalBytes  uint64
+	UsedPercent float64
+}
+
+// NetIOStats sind die zuletzt gesampelten kumulativen Netzwerk-Zähler.
+type NetIOStats struct {
+	BytesRecv uint64
+	BytesSent uint64
+}
+
+// systemCollector abstrahiert das eigentliche Host-/Prozess-Sampling, damit
+// Tests einen Fake statt gopsutil gegen den echten Host einsetzen können.
+type systemCollector interface {
+	Sample() (SystemSample, error)
+}
+
+// gopsutilCollector ist der Standard-systemCollector, implementiert über
+// github.com/shirou/gopsutil/v3.
+type gopsutilCollector struct {
+	diskDir string
+
+	procOnce sync.Once
+	proc     *process.Process
+	procErr  error
+}
+
+// newGopsutilCollector erstellt einen Collector, der die Plattenbelegung von
+// diskDir (üblicherweise das Analysis-Cache-Verzeichnis) sampelt.
+func newGopsutilCollector(diskDir string) *gopsutilCollector {
+	return &gopsutilCollector{diskDir: diskDir}
+}
+
+func (c *gopsutilCollector) process() (*process.Process, error) {
+	c.procOnce.Do(func() {
+		c.proc, c.procErr = process.NewProcess(int32(os.Getpid()))
+	})
+	return c.proc, c.procErr
+}
+
+// Sample nimmt eine Momentaufnahme von Prozess-RSS, Host-Virtual-Memory,
+// Gesamt-/Per-CPU-CPU-Last, Plattenbelegung von diskDir und kumulativen
+// Netzwerk-Zählern. Ein fehlgeschlagener Teil-Sample (z.B. Platte nicht
+// gemountet) lässt die übrigen Felder unverändert und gibt den ersten
+// aufgetretenen Fehler zurück.
+func (c *gopsutilCollector) Sample() (SystemSample, error) {
+	var sample SystemSample
+	var firstErr error
+	note := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if proc, err := c.process(); err == nil {
+		if memInfo, err := proc.MemoryInfo(); err == nil {
+			sample.ProcessRSSBytes = memInfo.RSS
+		} else {
+			note(err)
+		}
+	} else {
+		note(err)
+	}
+
+	if vmem, err := mem.VirtualMemory(); err == nil {
+		sample.VirtualMemPercent = vmem.UsedPercent
+	} else {
+		note(err)
+	}
+
+	if percpu, err := cpu.Percent(0, true); err == nil {
+		sample.CPUPercentPerCPU = percpu
+	} else {
+		note(err)
+	}
+	if total, err := cpu.Percent(0, false); err == nil && len(total) == 1 {
+		sample.CPUPercentTotal = total[0]
+	} else {
+		note(err)
+	}
+
+	if usage, err := disk.Usage(c.diskDir); err == nil {
+		sample.DiskUsedBytes = usage.Used
+		sample.DiskTotalBytes = usage.Total
+		sample.DiskUsedPercent = usage.UsedPercent
+	} else {
+		note(err)
+	}
+
+	if counters, err := gopsnet.IOCounters(false); err == nil && len(counters) == 1 {
+		sample.NetBytesRecv = counters[0].BytesRecv
+		sample.NetBytesSent = counters[0].BytesSent
+	} else {
+		note(err)
+	}
+
+	return sample, firstErr
+}
+
+// RecordSystemSample speist eine SystemSample in die bestehenden
+// memoryMeter/cpuMeter EWMA-Puffer (über RecordSystemMetrics) ein und hält
+// DiskUsage/NetIO als aktuellen Gauge-Snapshot fest.
+func (m *Metrics) RecordSystemSample(sample SystemSample) {
+	m.RecordSystemMetrics(sample.VirtualMemPercent, sample.CPUPercentTotal)
+
+	m.mu.Lock()
+	m.DiskUsage = DiskUsageStats{
+		UsedBytes:   sample.DiskUsedBytes,
+		TotalBytes:  sample.DiskTotalBytes,
+		UsedPercent: sample.DiskUsedPercent,
+	}
+	m.NetIO = NetIOStats{
+		BytesRecv: sample.NetBytesRecv,
+		BytesSent: sample.NetBytesSent,
+	}
+	m.LastUpdate = time.Now()
+	m.mu.Unlock()
+}
+
+// StartSystemSampler startet einen Hintergrund-Goroutine, der alle interval
+// Prozess-/Host-Ressourcen per gopsutil sampelt (RSS, Virtual-Memory-,
+// CPU-Auslastung, Plattenbelegung von defaultSystemSampleDir, Netzwerk-
+// Zähler) und in m einspeist. Einmal beim Serverstart aufrufen, analog zu
+// StartVisitorWindowRotation; ctx stornieren beendet die Goroutine sauber.
+func (m *Metrics) StartSystemSampler(ctx context.Context, interval time.Duration) {
+	m.startSystemSampler(ctx, interval, newGopsutilCollector(defaultSystemSampleDir))
+}
+
+// startSystemSampler ist der interne Einstiegspunkt, über den Tests einen
+// Fake-systemCollector statt gopsutil einspeisen, ohne den echten Host zu
+// proben.
+func (m *Metrics) startSystemSampler(ctx context.Context, interval time.Duration, collector systemCollector) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if sample, err := collector.Sample(); err == nil {
+					m.RecordSystemSample(sample)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
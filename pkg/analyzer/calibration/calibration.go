@@ -0,0 +1,202 @@
+// Package calibration implementiert gelernte Wahrscheinlichkeits-Kalibrierung
+// pro Detektor - Platt Scaling, Isotonic Regression und Temperature Scaling -
+// als Ersatz für die handgepflegten Faktoren aus
+// internal/handlers/verdict/calibration_config.go. Jeder Detektor kann seinen
+// eigenen, aus Ground-Truth-Daten gefitteten Calibrator in einer JSON-Datei
+// unter Dir() ablegen (siehe cmd/fit-calibrators); fehlt die Datei, bleibt
+// der Rohscore unverändert.
+package calibration
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Calibrator bildet einen rohen Detektor-Score auf eine kalibrierte
+// Wahrscheinlichkeit in [0,1] ab.
+type Calibrator interface {
+	Calibrate(raw float64) float64
+	Method() string
+}
+
+// identityCalibrator ist der Fallback für Detektoren ohne gefittete Datei.
+type identityCalibrator struct{}
+
+func (identityCalibrator) Calibrate(raw float64) float64 { return raw }
+func (identityCalibrator) Method() string                { return "identity" }
+
+// Identity liefert den No-Op-Calibrator - denselben Fallback, den ein
+// fehlendes Calibrator-File über Load auslöst.
+func Identity() Calibrator { return identityCalibrator{} }
+
+// fileFormat ist das on-disk JSON-Format eines gefitteten Calibrators - genau
+// eine der methodenspezifischen Sektionen ist belegt, abhängig von Method.
+type fileFormat struct {
+	Method string  `json:"method"`
+	A      float64 `json:"a,omitempty"`     // platt
+	B      float64 `json:"b,omitempty"`     // platt
+	T      float64 `json:"t,omitempty"`     // temperature
+	Knots  []Knot  `json:"knots,omitempty"` // isotonic
+}
+
+// Knot ist ein Stützpunkt der isotonen Regression: X ist der rohe Score, ab
+// dem Y als kalibrierte Wahrscheinlichkeit gilt.
+type Knot struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+var (
+	dirMu sync.RWMutex
+	dir   = "config/calibrators"
+
+	cacheMu sync.Mutex
+	cache   = map[string]Calibrator{}
+)
+
+// SetDir überschreibt das Verzeichnis, aus dem Calibrator-Dateien geladen
+// (und von cmd/fit-calibrators geschrieben) werden, und verwirft den
+// In-Memory-Cache - nützlich für Tests oder alternative Deployments.
+func SetDir(path string) {
+	dirMu.Lock()
+	dir = path
+	dirMu.Unlock()
+
+	cacheMu.Lock()
+	cache = map[string]Calibrator{}
+	cacheMu.Unlock()
+}
+
+func currentDir() string {
+	dirMu.RLock()
+	defer dirMu.RUnlock()
+	return dir
+}
+
+// HasFit meldet, ob für detector eine gefittete Calibrator-Datei existiert -
+// genutzt von verdict.applyBalancedCalibration, um zwischen gelernter
+// Kalibrierung und dem alten statischen Faktor-Profil zu wählen.
+func HasFit(detector string) bool {
+	_, err := os.Stat(filepath.Join(currentDir(), detector+".json"))
+	return err == nil
+}
+
+// Load liest den gefitteten Calibrator für detector aus
+// <Dir>/<detector>.json. Fehlt die Datei oder lässt sie sich nicht parsen,
+// liefert Load den Identity-Calibrator - ein fehlendes Fit ist kein
+// Fehlerzustand, nur ein Hinweis, dass dieser Detektor noch nicht trainiert
+// wurde.
+func Load(detector string) Calibrator {
+	cacheMu.Lock()
+	if c, ok := cache[detector]; ok {
+		cacheMu.Unlock()
+		return c
+	}
+	cacheMu.Unlock()
+
+	c := loadFromDisk(detector)
+
+	cacheMu.Lock()
+	cache[detector] = c
+	cacheMu.Unlock()
+
+	return c
+}
+
+func loadFromDisk(detector string) Calibrator {
+	path := filepath.Join(currentDir(), detector+".json")
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return identityCalibrator{}
+	}
+
+	var f fileFormat
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return identityCalibrator{}
+	}
+
+	c, err := fromFile(f)
+	if err != nil {
+		return identityCalibrator{}
+	}
+	return c
+}
+
+func fromFile(f fileFormat) (Calibrator, error) {
+	switch f.Method {
+	case "platt":
+		return &PlattCalibrator{A: f.A, B: f.B}, nil
+	case "isotonic":
+		return NewIsotonicCalibrator(f.Knots)
+	case "temperature":
+		return &TemperatureCalibrator{T: f.T}, nil
+	default:
+		return nil, fmt.Errorf("unknown calibration method %q", f.Method)
+	}
+}
+
+// Save persistiert result.Calibrator für detector unter
+// <Dir>/<detector>.json im selben Format, das Load erwartet, und legt Dir
+// bei Bedarf an.
+func Save(detector string, result FitResult) error {
+	f, err := toFile(result.Calibrator)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dirPath := currentDir()
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dirPath, detector+".json"), raw, 0644)
+}
+
+func toFile(c Calibrator) (fileFormat, error) {
+	switch v := c.(type) {
+	case *PlattCalibrator:
+		return fileFormat{Method: "platt", A: v.A, B: v.B}, nil
+	case *IsotonicCalibrator:
+		return fileFormat{Method: "isotonic", Knots: v.knots}, nil
+	case *TemperatureCalibrator:
+		return fileFormat{Method: "temperature", T: v.T}, nil
+	default:
+		return fileFormat{}, fmt.Errorf("unsupported calibrator type %T", c)
+	}
+}
+
+// CalibrateAll wendet den gefitteten Calibrator jedes Detektors aus scores
+// an und liefert eine neue Map - Detektoren ohne Fit-Datei bleiben
+// unverändert (Identity), NaN-Scores werden unverändert durchgereicht, und
+// jedes Ergebnis wird auf [0,1] geklemmt.
+func CalibrateAll(scores map[string]float64) map[string]float64 {
+	calibrated := make(map[string]float64, len(scores))
+	for detector, raw := range scores {
+		if math.IsNaN(raw) {
+			calibrated[detector] = raw
+			continue
+		}
+		calibrated[detector] = clamp01(Load(detector).Calibrate(raw))
+	}
+	return calibrated
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,112 @@
+package calibration
+
+import "math"
+
+// BrierScore ist der mittlere quadratische Fehler zwischen kalibrierten
+// Wahrscheinlichkeiten und binären Labels - das Maß, mit dem FitBest
+// zwischen Platt/Isotonic/Temperature für einen Detektor wählt.
+func BrierScore(predicted, label []float64) float64 {
+	if len(predicted) == 0 {
+		return 0
+	}
+	var sum float64
+	for i := range predicted {
+		diff := predicted[i] - label[i]
+		sum += diff * diff
+	}
+	return sum / float64(len(predicted))
+}
+
+// Fold ist ein Train/Test-Split für die K-Fold Cross-Validation in FitBest.
+type Fold struct {
+	TrainRaw, TrainLabel []float64
+	TestRaw, TestLabel   []float64
+}
+
+// KFolds teilt raw/label in k aufeinanderfolgende, ungefähr gleich große
+// Blöcke auf. Gibt es weniger Punkte als k, wird jeder Punkt sein eigener
+// Fold (Leave-One-Out).
+func KFolds(raw, label []float64, k int) []Fold {
+	n := len(raw)
+	if n == 0 {
+		return nil
+	}
+	if k <= 1 || k > n {
+		k = n
+	}
+
+	folds := make([]Fold, 0, k)
+	foldSize := n / k
+
+	for i := 0; i < k; i++ {
+		start := i * foldSize
+		end := start + foldSize
+		if i == k-1 {
+			end = n
+		}
+
+		testRaw := append([]float64{}, raw[start:end]...)
+		testLabel := append([]float64{}, label[start:end]...)
+
+		trainRaw := make([]float64, 0, n-len(testRaw))
+		trainLabel := make([]float64, 0, n-len(testLabel))
+		trainRaw = append(trainRaw, raw[:start]...)
+		trainRaw = append(trainRaw, raw[end:]...)
+		trainLabel = append(trainLabel, label[:start]...)
+		trainLabel = append(trainLabel, label[end:]...)
+
+		folds = append(folds, Fold{TrainRaw: trainRaw, TrainLabel: trainLabel, TestRaw: testRaw, TestLabel: testLabel})
+	}
+
+	return folds
+}
+
+// FitResult ist das Ergebnis von FitBest für einen Detektor: der gewählte
+// Calibrator sowie sein gemittelter CV-Brier-Score, zum Loggen in
+// cmd/fit-calibrators.
+type FitResult struct {
+	Calibrator Calibrator
+	Method     string
+	BrierScore float64
+}
+
+// FitBest fittet Platt, Isotonic und Temperature Scaling gegen raw/label,
+// bewertet jede Methode per k-Fold Cross-Validation anhand des
+// Brier-Scores und liefert die Methode mit dem niedrigsten mittleren
+// CV-Brier-Score, neu gefittet auf den vollständigen Daten.
+func FitBest(raw, label []float64, folds int) FitResult {
+	candidates := []struct {
+		method string
+		fit    func(raw, label []float64) Calibrator
+	}{
+		{"platt", func(r, l []float64) Calibrator { return FitPlatt(r, l) }},
+		{"isotonic", func(r, l []float64) Calibrator { return FitIsotonic(r, l) }},
+		{"temperature", func(r, l []float64) Calibrator { return FitTemperature(r, l) }},
+	}
+
+	cvFolds := KFolds(raw, label, folds)
+
+	best := FitResult{BrierScore: math.Inf(1)}
+	for _, cand := range candidates {
+		var totalBrier float64
+		for _, fold := range cvFolds {
+			c := cand.fit(fold.TrainRaw, fold.TrainLabel)
+			predicted := make([]float64, len(fold.TestRaw))
+			for i, rawScore := range fold.TestRaw {
+				predicted[i] = clamp01(c.Calibrate(rawScore))
+			}
+			totalBrier += BrierScore(predicted, fold.TestLabel)
+		}
+		meanBrier := totalBrier / float64(len(cvFolds))
+
+		if meanBrier < best.BrierScore {
+			best = FitResult{
+				Calibrator: cand.fit(raw, label),
+				Method:     cand.method,
+				BrierScore: meanBrier,
+			}
+		}
+	}
+
+	return best
+}
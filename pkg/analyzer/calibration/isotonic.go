@@ -0,0 +1,96 @@
+package calibration
+
+import (
+	"fmt"
+	"sort"
+)
+
+// IsotonicCalibrator bildet einen rohen Score über eine stückweise-konstante,
+// monoton steigende Treppenfunktion ab: für einen Rohscore gilt der Y-Wert
+// des größten Knots, dessen X nicht größer ist.
+type IsotonicCalibrator struct {
+	knots []Knot // aufsteigend nach X sortiert, Y monoton nicht-fallend
+}
+
+// NewIsotonicCalibrator validiert, dass knots streng monoton in X ist (die
+// von FitIsotonic erzeugte Treppenfunktion garantiert das bereits, ein von
+// Hand editiertes File könnte das aber verletzen) und liefert den
+// resultierenden Calibrator.
+func NewIsotonicCalibrator(knots []Knot) (*IsotonicCalibrator, error) {
+	if len(knots) == 0 {
+		return nil, fmt.Errorf("isotonic calibrator needs at least one knot")
+	}
+	for i := 1; i < len(knots); i++ {
+		if knots[i].X <= knots[i-1].X {
+			return nil, fmt.Errorf("isotonic knots must be strictly increasing in x, got %v then %v", knots[i-1].X, knots[i].X)
+		}
+		if knots[i].Y < knots[i-1].Y {
+			return nil, fmt.Errorf("isotonic knots must be non-decreasing in y, got %v then %v", knots[i-1].Y, knots[i].Y)
+		}
+	}
+	return &IsotonicCalibrator{knots: knots}, nil
+}
+
+func (c *IsotonicCalibrator) Calibrate(raw float64) float64 {
+	knots := c.knots
+
+	// idx = Anzahl Knots mit X <= raw.
+	idx := sort.Search(len(knots), func(i int) bool { return knots[i].X > raw })
+	if idx == 0 {
+		return knots[0].Y
+	}
+	return knots[idx-1].Y
+}
+
+func (c *IsotonicCalibrator) Method() string { return "isotonic" }
+
+// FitIsotonic fittet eine monotone Treppenfunktion gegen (raw, label) per
+// Pool-Adjacent-Violators-Algorithmus (PAVA): Punkte werden nach raw
+// sortiert, dann werden benachbarte Blöcke, die die Monotonie verletzen, zu
+// ihrem gewichteten Mittelwert verschmolzen, bis die Folge monoton ist.
+func FitIsotonic(raw, label []float64) *IsotonicCalibrator {
+	type point struct{ x, y float64 }
+	type block struct {
+		minX   float64
+		sumY   float64
+		weight float64
+	}
+
+	points := make([]point, len(raw))
+	for i := range raw {
+		points[i] = point{x: raw[i], y: label[i]}
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].x < points[j].x })
+
+	blocks := make([]block, 0, len(points))
+	for _, p := range points {
+		blocks = append(blocks, block{minX: p.x, sumY: p.y, weight: 1})
+
+		for len(blocks) > 1 {
+			last := blocks[len(blocks)-1]
+			prev := blocks[len(blocks)-2]
+			if prev.sumY/prev.weight <= last.sumY/last.weight {
+				break
+			}
+			merged := block{
+				minX:   prev.minX,
+				sumY:   prev.sumY + last.sumY,
+				weight: prev.weight + last.weight,
+			}
+			blocks = append(blocks[:len(blocks)-2], merged)
+		}
+	}
+
+	knots := make([]Knot, 0, len(blocks))
+	var lastX float64
+	for i, b := range blocks {
+		x := b.minX
+		if i > 0 && x <= lastX {
+			x = lastX + 1e-9
+		}
+		knots = append(knots, Knot{X: x, Y: b.sumY / b.weight})
+		lastX = x
+	}
+
+	return &IsotonicCalibrator{knots: knots}
+}
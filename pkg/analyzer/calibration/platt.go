@@ -0,0 +1,53 @@
+package calibration
+
+import "math"
+
+// PlattCalibrator ist logistische Regression auf dem rohen Score: die
+// kalibrierte Wahrscheinlichkeit ist sigmoid(A*raw + B). A und B werden von
+// FitPlatt per Gradientenabstieg auf die Negative-Log-Likelihood der
+// gelabelten Trainingsdaten bestimmt (siehe cmd/fit-calibrators).
+type PlattCalibrator struct {
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+}
+
+func (p *PlattCalibrator) Calibrate(raw float64) float64 {
+	return sigmoid(p.A*raw + p.B)
+}
+
+func (p *PlattCalibrator) Method() string { return "platt" }
+
+func sigmoid(x float64) float64 {
+	return 1.0 / (1.0 + math.Exp(-x))
+}
+
+// FitPlatt fittet A und B per Gradientenabstieg gegen die
+// Negative-Log-Likelihood von label gegeben sigmoid(A*raw+B) - das
+// klassische Platt-Scaling-Verfahren, hier ohne externe
+// Optimierungs-Bibliothek, da dieser Baum weder go.mod noch Vendoring hat.
+func FitPlatt(raw, label []float64) *PlattCalibrator {
+	a, b := 1.0, 0.0
+	const (
+		learningRate = 0.01
+		iterations   = 2000
+	)
+
+	n := float64(len(raw))
+	if n == 0 {
+		return &PlattCalibrator{A: a, B: b}
+	}
+
+	for iter := 0; iter < iterations; iter++ {
+		var gradA, gradB float64
+		for i := range raw {
+			pred := sigmoid(a*raw[i] + b)
+			residual := pred - label[i]
+			gradA += residual * raw[i]
+			gradB += residual
+		}
+		a -= learningRate * gradA / n
+		b -= learningRate * gradB / n
+	}
+
+	return &PlattCalibrator{A: a, B: b}
+}
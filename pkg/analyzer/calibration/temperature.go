@@ -0,0 +1,78 @@
+package calibration
+
+import "math"
+
+// TemperatureCalibrator skaliert den Pre-Sigmoid-Logit eines rohen Scores
+// mit einem einzelnen Skalar T: calibrate(raw) = sigmoid(logit(raw) / T).
+// T > 1 macht die Vorhersage vorsichtiger (Richtung 0.5), T < 1 schärft sie.
+type TemperatureCalibrator struct {
+	T float64 `json:"t"`
+}
+
+func (t *TemperatureCalibrator) Calibrate(raw float64) float64 {
+	temp := t.T
+	if temp <= 0 {
+		temp = 1
+	}
+	return sigmoid(logit(raw) / temp)
+}
+
+func (t *TemperatureCalibrator) Method() string { return "temperature" }
+
+// logit ist die Umkehrfunktion von sigmoid, gegen die [0,1]-Ränder
+// abgesichert, damit raw=0 oder raw=1 keine Unendlichkeiten erzeugen.
+func logit(p float64) float64 {
+	const eps = 1e-6
+	if p < eps {
+		p = eps
+	} else if p > 1-eps {
+		p = 1 - eps
+	}
+	return math.Log(p / (1 - p))
+}
+
+// FitTemperature bestimmt T per 1-D Line Search über ein festes Raster, das
+// die Negative-Log-Likelihood von label gegeben sigmoid(logit(raw)/T)
+// minimiert - für einen einzelnen Freiheitsgrad reicht das Raster, ein
+// Gradientenverfahren wäre hier unnötiger Aufwand.
+func FitTemperature(raw, label []float64) *TemperatureCalibrator {
+	best := 1.0
+	bestNLL := math.Inf(1)
+
+	for _, t := range candidateTemperatures() {
+		nll := negativeLogLikelihood(raw, label, t)
+		if nll < bestNLL {
+			bestNLL = nll
+			best = t
+		}
+	}
+
+	return &TemperatureCalibrator{T: best}
+}
+
+func candidateTemperatures() []float64 {
+	temps := make([]float64, 0, 100)
+	for t := 0.05; t <= 5.0; t += 0.05 {
+		temps = append(temps, t)
+	}
+	return temps
+}
+
+func negativeLogLikelihood(raw, label []float64, t float64) float64 {
+	const eps = 1e-12
+	var nll float64
+	for i := range raw {
+		p := sigmoid(logit(raw[i]) / t)
+		if p < eps {
+			p = eps
+		} else if p > 1-eps {
+			p = 1 - eps
+		}
+		if label[i] >= 0.5 {
+			nll -= math.Log(p)
+		} else {
+			nll -= math.Log(1 - p)
+		}
+	}
+	return nll
+}
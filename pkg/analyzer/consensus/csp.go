@@ -0,0 +1,216 @@
+// Package consensus modelliert die Übereinstimmung zwischen Detektor-
+// Kategorien als Constraint-Satisfaction-Problem (CSP) statt als Ad-hoc-
+// Zählung von High/Low-Scores (siehe früher internal/handlers/verdict.
+// analyzeMethodAgreement). Jede Kategorie ist eine Variable mit einer
+// Domain aus möglichen Urteilen; binäre Constraints kodieren forensisches
+// Vorwissen (siehe rules.go) und werden per AC-3-Bogenkonsistenz
+// durchgesetzt, um widersprüchliche Domains zu beschneiden.
+package consensus
+
+// Variable ist eine CSP-Variable mit ihrem aktuellen Wertebereich (Domain).
+type Variable struct {
+	Name   string
+	Domain []string
+}
+
+// Constraint ist ein binärer Constraint zwischen Between[0] und Between[1]:
+// Allowed(x, y) entscheidet, ob ein Wert x aus der Domain von Between[0]
+// neben einem Wert y aus der Domain von Between[1] bestehen darf. Name
+// dient der Erklärbarkeit - er erscheint in Result.Removals, sobald der
+// Constraint eine Domain beschneidet.
+type Constraint struct {
+	Name    string
+	Between [2]string
+	Allowed func(x, y string) bool
+}
+
+// Removal protokolliert, dass Value aus der Domain von Variable entfernt
+// wurde, weil Constraint keinen unterstützenden Wert in der Nachbar-Domain
+// mehr fand.
+type Removal struct {
+	Variable   string
+	Value      string
+	Constraint string
+}
+
+// Result ist die Ausgabe von Solver.Solve: die verbliebene Domain je
+// Variable, ob das Netz konsistent ist (keine Variable auf eine leere
+// Domain kollabiert ist), die Liste der Entfernungen für Explainability
+// sowie die aus dem Anteil auf einen Singleton kollabierter Variablen
+// abgeleitete ConsensusStrength.
+type Result struct {
+	Domains           map[string][]string
+	Consistent        bool
+	Removals          []Removal
+	ConsensusStrength float64
+}
+
+// Solver führt AC-3-Bogenkonsistenz über einer Menge von Variablen und
+// binären Constraints aus. Constraints sind bewusst pluggable (siehe
+// rules.go) - weitere forensische Regeln lassen sich anfügen, ohne den
+// Solver oder die Fusionslogik zu ändern.
+type Solver struct {
+	variables   map[string]*Variable
+	order       []string
+	constraints []Constraint
+}
+
+// NewSolver baut einen Solver aus variables (als Kopien, damit der Aufrufer
+// die übergebenen Domains nicht versehentlich über den Solver hinweg
+// mutiert) und constraints.
+func NewSolver(variables []Variable, constraints []Constraint) *Solver {
+	s := &Solver{
+		variables:   make(map[string]*Variable, len(variables)),
+		constraints: constraints,
+	}
+	for _, v := range variables {
+		domain := make([]string, len(v.Domain))
+		copy(domain, v.Domain)
+		s.variables[v.Name] = &Variable{Name: v.Name, Domain: domain}
+		s.order = append(s.order, v.Name)
+	}
+	return s
+}
+
+// arcsFor liefert alle Constraints zwischen xi und xj, normalisiert auf die
+// Richtung xi -> xj: Allowed(x, y) prüft dann stets einen Wert aus
+// Domain(xi) gegen einen Wert aus Domain(xj), unabhängig davon, in welcher
+// Reihenfolge der Constraint ursprünglich definiert wurde.
+func (s *Solver) arcsFor(xi, xj string) []Constraint {
+	var out []Constraint
+	for _, c := range s.constraints {
+		switch {
+		case c.Between[0] == xi && c.Between[1] == xj:
+			out = append(out, c)
+		case c.Between[0] == xj && c.Between[1] == xi:
+			reversed := c
+			reversed.Between = [2]string{xi, xj}
+			reversed.Allowed = func(x, y string) bool { return c.Allowed(y, x) }
+			out = append(out, reversed)
+		}
+	}
+	return out
+}
+
+// neighbours liefert die Namen aller Variablen, die mit xi durch mindestens
+// einen Constraint verbunden sind.
+func (s *Solver) neighbours(xi string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, c := range s.constraints {
+		var other string
+		switch xi {
+		case c.Between[0]:
+			other = c.Between[1]
+		case c.Between[1]:
+			other = c.Between[0]
+		default:
+			continue
+		}
+		if !seen[other] {
+			seen[other] = true
+			out = append(out, other)
+		}
+	}
+	return out
+}
+
+// revise beschneidet Domain(xi) um jeden Wert, der unter keinem Constraint
+// zwischen xi und xj noch einen unterstützenden Wert in Domain(xj) findet.
+// Es liefert zurück, ob die Domain tatsächlich verkleinert wurde, sowie die
+// dabei entfernten Werte.
+func (s *Solver) revise(xi, xj string) (bool, []Removal) {
+	arcs := s.arcsFor(xi, xj)
+	if len(arcs) == 0 {
+		return false, nil
+	}
+
+	vi := s.variables[xi]
+	vj := s.variables[xj]
+
+	var kept []string
+	var removals []Removal
+	for _, x := range vi.Domain {
+		supported := false
+		for _, y := range vj.Domain {
+			allowedByAll := true
+			for _, arc := range arcs {
+				if !arc.Allowed(x, y) {
+					allowedByAll = false
+					break
+				}
+			}
+			if allowedByAll {
+				supported = true
+				break
+			}
+		}
+		if supported {
+			kept = append(kept, x)
+		} else {
+			removals = append(removals, Removal{Variable: xi, Value: x, Constraint: arcs[0].Name})
+		}
+	}
+
+	if len(removals) == 0 {
+		return false, nil
+	}
+	vi.Domain = kept
+	return true, removals
+}
+
+// Solve führt AC-3 bis zur Fixpunkt-Konsistenz (oder bis eine Domain
+// kollabiert) aus: die Worklist startet mit allen gerichteten Bögen und
+// reiht bei jeder erfolgreichen Beschneidung die Nachbarn der betroffenen
+// Variable erneut ein.
+func (s *Solver) Solve() Result {
+	type arc struct{ xi, xj string }
+
+	var worklist []arc
+	for _, c := range s.constraints {
+		worklist = append(worklist, arc{c.Between[0], c.Between[1]}, arc{c.Between[1], c.Between[0]})
+	}
+
+	var removals []Removal
+	consistent := true
+
+	for len(worklist) > 0 {
+		a := worklist[0]
+		worklist = worklist[1:]
+
+		revised, rem := s.revise(a.xi, a.xj)
+		if !revised {
+			continue
+		}
+		removals = append(removals, rem...)
+
+		if len(s.variables[a.xi].Domain) == 0 {
+			consistent = false
+			break
+		}
+
+		for _, xk := range s.neighbours(a.xi) {
+			if xk == a.xj {
+				continue
+			}
+			worklist = append(worklist, arc{xk, a.xi})
+		}
+	}
+
+	domains := make(map[string][]string, len(s.order))
+	var singletons int
+	for _, name := range s.order {
+		domain := s.variables[name].Domain
+		domains[name] = domain
+		if len(domain) == 1 {
+			singletons++
+		}
+	}
+
+	return Result{
+		Domains:           domains,
+		Consistent:        consistent,
+		Removals:          removals,
+		ConsensusStrength: float64(singletons) / float64(len(s.order)),
+	}
+}
@@ -0,0 +1,147 @@
+package consensus
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// TestSolveMapColouring ist das Lehrbuchbeispiel für AC-3: Australiens
+// Bundesstaaten, drei Farben, benachbarte Staaten dürfen keine Farbe teilen.
+// WA-NT-SA-Q-NSW-V bilden einen Ring von Nachbarschaften, T hat keine
+// Nachbarn. Mit drei Farben bleibt jede Domain voll erhalten (das Problem
+// ist 3-färbbar, aber AC-3 allein stellt das nicht sicher - es beschneidet
+// nur, wo eine Farbe keinen Support mehr hat, was hier für keinen Staat
+// zutrifft), das Netz bleibt also konsistent.
+func TestSolveMapColouring(t *testing.T) {
+	colours := []string{"red", "green", "blue"}
+	differentColours := func(x, y string) bool { return x != y }
+
+	regions := []string{"WA", "NT", "SA", "Q", "NSW", "V", "T"}
+	var variables []Variable
+	for _, r := range regions {
+		variables = append(variables, Variable{Name: r, Domain: append([]string{}, colours...)})
+	}
+
+	adjacent := [][2]string{
+		{"WA", "NT"}, {"WA", "SA"},
+		{"NT", "SA"}, {"NT", "Q"},
+		{"SA", "Q"}, {"SA", "NSW"}, {"SA", "V"},
+		{"Q", "NSW"},
+		{"NSW", "V"},
+	}
+	var constraints []Constraint
+	for _, pair := range adjacent {
+		constraints = append(constraints, Constraint{
+			Name:    pair[0] + "_" + pair[1] + "_different",
+			Between: pair,
+			Allowed: differentColours,
+		})
+	}
+
+	result := NewSolver(variables, constraints).Solve()
+
+	if !result.Consistent {
+		t.Fatalf("expected consistent map colouring, got inconsistent with removals %+v", result.Removals)
+	}
+	for _, r := range regions {
+		if len(result.Domains[r]) != 3 {
+			t.Errorf("region %s: expected full domain of 3 colours to survive AC-3, got %v", r, result.Domains[r])
+		}
+	}
+}
+
+// TestAnalyzeCleanMetadataExcludesAITraditional prüft den Constraint
+// "clean_metadata_excludes_ai_traditional": eindeutig saubere Metadaten
+// zusammen mit einem unsicheren traditionellen Score müssen das AI-Urteil
+// aus der traditional-Domain entfernen.
+func TestAnalyzeCleanMetadataExcludesAITraditional(t *testing.T) {
+	result := Analyze(0.5, -1, 0.1)
+
+	if result.Domains["metadata"][0] != StateAuthentic {
+		t.Fatalf("expected metadata to collapse to AUTHENTIC, got %v", result.Domains["metadata"])
+	}
+	if contains(result.Domains["traditional"], StateAI) {
+		t.Errorf("expected AI removed from traditional domain, got %v", result.Domains["traditional"])
+	}
+	if !result.Consistent {
+		t.Errorf("expected consistent result, got inconsistent")
+	}
+}
+
+// TestAnalyzeC2PATagForcesAIModel prüft den Constraint
+// "c2pa_ai_tag_forces_ai_model": ein eindeutiges AI-Urteil der Metadaten
+// (z.B. C2PA-AI-Tag) zwingt die ai-model-Domain auf den Singleton AI.
+func TestAnalyzeC2PATagForcesAIModel(t *testing.T) {
+	result := Analyze(-1, -1, 0.95)
+
+	domain := result.Domains["ai-model"]
+	if len(domain) != 1 || domain[0] != StateAI {
+		t.Fatalf("expected ai-model forced to [AI], got %v", domain)
+	}
+	if !result.Consistent {
+		t.Errorf("expected consistent result, got inconsistent")
+	}
+}
+
+// TestAnalyzeCompressionLightingConflictIsInconsistent prüft den Constraint
+// "compression_lighting_conflict": stehen traditional=AUTHENTIC und
+// ai-model=AI beide bereits eindeutig fest, gibt es keinen Wert mehr, der
+// beide Seiten stützt - das Netz muss als inkonsistent markiert werden statt
+// den Widerspruch stillschweigend zu ignorieren.
+func TestAnalyzeCompressionLightingConflictIsInconsistent(t *testing.T) {
+	result := Analyze(0.1, 0.9, -1)
+
+	if result.Consistent {
+		t.Fatalf("expected inconsistent result for conflicting traditional/ai-model evidence, domains=%v", result.Domains)
+	}
+
+	var sawConflict bool
+	for _, rem := range result.Removals {
+		if rem.Constraint == "compression_lighting_conflict" {
+			sawConflict = true
+		}
+	}
+	if !sawConflict {
+		t.Errorf("expected a removal attributed to compression_lighting_conflict, got %+v", result.Removals)
+	}
+}
+
+func contains(domain []string, value string) bool {
+	for _, v := range domain {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedCopy(domain []string) []string {
+	out := append([]string{}, domain...)
+	sort.Strings(out)
+	return out
+}
+
+// TestDomainFromScoreThresholds dokumentiert die Score->Domain-Abbildung,
+// die Analyze zur Initialisierung jeder Kategorie-Variable verwendet.
+func TestDomainFromScoreThresholds(t *testing.T) {
+	cases := []struct {
+		score float64
+		want  []string
+	}{
+		{0.95, []string{StateAI}},
+		{aiThreshold, []string{StateAI}},
+		{0.05, []string{StateAuthentic}},
+		{authThreshold, []string{StateAuthentic}},
+		{0.5, allStates},
+		{-1, allStates},
+	}
+
+	for _, c := range cases {
+		got := sortedCopy(domainFromScore(c.score))
+		want := sortedCopy(c.want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("domainFromScore(%v) = %v, want %v", c.score, got, want)
+		}
+	}
+}
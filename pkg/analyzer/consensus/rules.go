@@ -0,0 +1,95 @@
+package consensus
+
+// Die drei möglichen CSP-Urteile je Detektor-Kategorie.
+const (
+	StateAI        = "AI"
+	StateAuthentic = "AUTHENTIC"
+	StateUnknown   = "UNKNOWN"
+)
+
+// Schwellen, ab denen ein kalibrierter Kategorie-Score ein eindeutiges
+// Urteil trägt statt die volle Domain offen zu lassen - dieselben Schwellen
+// wie das übrige Verdict-Paket (vgl. "Strong"/"Authenticity" in
+// computeVerdict).
+const (
+	aiThreshold   = 0.7
+	authThreshold = 0.3
+)
+
+var allStates = []string{StateAI, StateAuthentic, StateUnknown}
+
+// domainFromScore bildet einen kalibrierten Kategorie-Score auf die
+// initiale Domain einer CSP-Variable ab: ein eindeutiges Urteil (score >=
+// aiThreshold bzw. <= authThreshold) kollabiert die Domain sofort auf einen
+// Singleton; alles dazwischen - oder fehlende Daten (score < 0) - bleibt
+// offen für AC-3, um von benachbarten, zuversichtlicheren Variablen
+// eingeschränkt zu werden.
+func domainFromScore(score float64) []string {
+	switch {
+	case score < 0:
+		return append([]string{}, allStates...)
+	case score >= aiThreshold:
+		return []string{StateAI}
+	case score <= authThreshold:
+		return []string{StateAuthentic}
+	default:
+		return append([]string{}, allStates...)
+	}
+}
+
+// ForensicConstraints sind die eingebauten binären Constraints zwischen den
+// drei Detektor-Kategorien traditional, ai-model und metadata. Sie kodieren
+// Vorwissen aus der forensischen Bildanalyse und sind bewusst als
+// eigenständige Liste gehalten, die sich um weitere Regeln erweitern lässt,
+// ohne Solver oder Fusionslogik anzufassen.
+func ForensicConstraints() []Constraint {
+	return []Constraint{
+		{
+			Name:    "clean_metadata_excludes_ai_traditional",
+			Between: [2]string{"metadata", "traditional"},
+			Allowed: func(metadata, traditional string) bool {
+				// Sauberes, reichhaltiges EXIF (metadata=AUTHENTIC) neben
+				// Kamera-Rauschartefakten schließt ein AI-Urteil der
+				// traditionellen Analyse aus.
+				return !(metadata == StateAuthentic && traditional == StateAI)
+			},
+		},
+		{
+			Name:    "c2pa_ai_tag_forces_ai_model",
+			Between: [2]string{"metadata", "ai-model"},
+			Allowed: func(metadata, aiModel string) bool {
+				// Ein AI-Tag im C2PA-Manifest (metadata=AI) erzwingt ein
+				// AI-Urteil des AI-Modells.
+				return !(metadata == StateAI && aiModel != StateAI)
+			},
+		},
+		{
+			Name:    "compression_lighting_conflict",
+			Between: [2]string{"traditional", "ai-model"},
+			Allowed: func(traditional, aiModel string) bool {
+				// Kompressions-Authentizität (traditional=AUTHENTIC) neben
+				// AI-verdächtigem Licht (ai-model=AI) ist ein bekanntes
+				// Konfliktmuster - beide dürfen nicht gleichzeitig
+				// feststehen.
+				return !(traditional == StateAuthentic && aiModel == StateAI)
+			},
+		},
+	}
+}
+
+// Analyze ersetzt das frühere Ad-hoc-Zählen von High/Low-Kategorien aus
+// analyzeMethodAgreement durch ein Constraint-Satisfaction-Modell: jede
+// Detektor-Kategorie wird zu einer CSP-Variable mit Domain
+// {AI, AUTHENTIC, UNKNOWN}, initialisiert über domainFromScore, und gegen
+// ForensicConstraints per AC-3 (siehe Solver) auf Bogenkonsistenz geprüft.
+// traditional/aiModel/metadata sind die kalibrierten Durchschnitts-Scores
+// der jeweiligen Kategorie, <0 bedeutet "keine Daten".
+func Analyze(traditional, aiModel, metadata float64) Result {
+	solver := NewSolver([]Variable{
+		{Name: "traditional", Domain: domainFromScore(traditional)},
+		{Name: "ai-model", Domain: domainFromScore(aiModel)},
+		{Name: "metadata", Domain: domainFromScore(metadata)},
+	}, ForensicConstraints())
+
+	return solver.Solve()
+}
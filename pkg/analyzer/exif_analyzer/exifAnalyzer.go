@@ -81,6 +81,7 @@
 package exifanalyzer
 
 import (
+	"encoding/hex"
 	"os"
 	"time"
 
@@ -88,11 +89,18 @@ import (
 )
 
 type EXIFData struct {
-	DateTime *time.Time  `json:"date_time,omitempty"`
-	Make     string      `json:"make,omitempty"`
-	Model    string      `json:"model,omitempty"`
-	GPS      *[2]float64 `json:"gps,omitempty"`
-	Raw      []byte      `json:"raw,omitempty"`
+	DateTime             *time.Time  `json:"date_time,omitempty"`
+	Make                 string      `json:"make,omitempty"`
+	Model                string      `json:"model,omitempty"`
+	GPS                  *[2]float64 `json:"gps,omitempty"`
+	Raw                  []byte      `json:"raw,omitempty"`
+	Software             string      `json:"software,omitempty"`
+	LensModel            string      `json:"lens_model,omitempty"`
+	CreatorTool          string      `json:"creator_tool,omitempty"`
+	DigitalSourceType    string      `json:"digital_source_type,omitempty"`
+	EditHistory          []string    `json:"edit_history,omitempty"`
+	MakerNoteFingerprint string      `json:"maker_note_fingerprint,omitempty"`
+	UnrecognizedIFDs     map[string]string `json:"unrecognized_ifds,omitempty"`
 }
 
 func AnalyzeEXIF(path string) (*EXIFData, error) {
@@ -104,8 +112,12 @@ func AnalyzeEXIF(path string) (*EXIFData, error) {
 
 	x, err := exif.Decode(f)
 	if err != nil {
-		// kein EXIF im Bild ⇒ gib ein leeres []byte zurück
-		return &EXIFData{Raw: []byte{}}, nil
+		// kein EXIF im Bild ⇒ versuche trotzdem XMP/IPTC aus den Rohbytes zu lesen
+		out := &EXIFData{Raw: []byte{}}
+		if raw, rerr := os.ReadFile(path); rerr == nil {
+			applyXMPIPTC(out, raw)
+		}
+		return out, nil
 	}
 
 	out := &EXIFData{
@@ -124,6 +136,32 @@ func AnalyzeEXIF(path string) (*EXIFData, error) {
 	if tag, err := x.Get(exif.Model); err == nil {
 		out.Model, _ = tag.StringVal()
 	}
+	if tag, err := x.Get(exif.Software); err == nil {
+		out.Software, _ = tag.StringVal()
+	}
+	if tag, err := x.Get(exif.LensModel); err == nil {
+		out.LensModel, _ = tag.StringVal()
+	}
+	if tag, err := x.Get(exif.MakerNote); err == nil {
+		if raw, rerr := tag.StringVal(); rerr == nil && raw != "" {
+			out.MakerNoteFingerprint = fingerprintMakerNote(raw)
+		}
+	}
+
+	// goexif nur dekodiert TIFF-IFDs; XMP/IPTC-Blöcke liegen in separaten
+	// JPEG-APP-Segmenten und müssen zusätzlich aus den Rohbytes gelesen werden.
+	if raw, rerr := os.ReadFile(path); rerr == nil {
+		applyXMPIPTC(out, raw)
+	}
 
 	return out, nil
 }
+
+// fingerprintMakerNote liefert einen kurzen, stabilen Fingerabdruck der
+// herstellerspezifischen MakerNote, ohne die binären Rohdaten zu exportieren.
+func fingerprintMakerNote(raw string) string {
+	if len(raw) > 16 {
+		raw = raw[:16]
+	}
+	return hex.EncodeToString([]byte(raw))
+}
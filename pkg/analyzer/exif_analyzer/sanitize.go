@@ -0,0 +1,147 @@
+package exifanalyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// jpegSOI/EOI and the marker bytes that start/stop metadata we strip.
+const (
+	jpegMarkerPrefix = 0xFF
+	jpegSOI          = 0xD8
+	jpegEOI          = 0xD9
+	jpegSOS          = 0xDA
+	jpegAPP1         = 0xE1 // EXIF / XMP
+	jpegAPP13        = 0xED // IPTC / Photoshop IRB
+)
+
+// ErrUnsupportedFormat wird zurückgegeben, wenn der Input kein JPEG ist -
+// StripMetadata kennt die Segment-/Chunk-Struktur von PNG/WebP/GIF/BMP/TIFF
+// (alle von utils.ValidateFile akzeptiert) noch nicht und würde sie sonst
+// unverändert durchreichen, während der Aufrufer trotzdem einen Erfolg
+// meldet. Der Aufrufer muss dies als Fehler behandeln, nicht als "nichts zu
+// entfernen" (siehe sanitizeHandler).
+var ErrUnsupportedFormat = fmt.Errorf("sanitize: only JPEG is currently supported")
+
+// StripMetadata walks a JPEG's segment structure and writes a copy with all
+// APP1 (EXIF/XMP) and APP13 (IPTC/Photoshop) segments removed, returning the
+// names of the segments it dropped. Image data (the scan following SOS) and
+// every other segment are copied through byte-for-byte, so compression
+// artifacts used by the rest of this detector are preserved exactly.
+//
+// Only JPEG is supported for now; other containers return ErrUnsupportedFormat
+// rather than being silently copied through unmodified.
+func StripMetadata(in io.Reader, out io.Writer) ([]string, error) {
+	r := bufio.NewReader(in)
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+
+	header, err := r.Peek(2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file header: %w", err)
+	}
+	if header[0] != jpegMarkerPrefix || header[1] != jpegSOI {
+		return nil, ErrUnsupportedFormat
+	}
+
+	var removed []string
+
+	for {
+		marker, err := readMarker(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return removed, fmt.Errorf("failed to read segment marker: %w", err)
+		}
+
+		if marker == jpegSOI {
+			if _, err := w.Write([]byte{jpegMarkerPrefix, jpegSOI}); err != nil {
+				return removed, err
+			}
+			continue
+		}
+
+		if marker == jpegSOS {
+			// Ab hier folgen die komprimierten Bilddaten bis EOI - 1:1 kopieren.
+			if _, err := w.Write([]byte{jpegMarkerPrefix, jpegSOS}); err != nil {
+				return removed, err
+			}
+			if _, err := io.Copy(w, r); err != nil {
+				return removed, fmt.Errorf("failed to copy scan data: %w", err)
+			}
+			break
+		}
+
+		segment, err := readSegment(r)
+		if err != nil {
+			return removed, fmt.Errorf("failed to read segment 0x%X: %w", marker, err)
+		}
+
+		if marker == jpegAPP1 {
+			removed = append(removed, "APP1 (EXIF/XMP)")
+			continue
+		}
+		if marker == jpegAPP13 {
+			removed = append(removed, "APP13 (IPTC/Photoshop)")
+			continue
+		}
+
+		if err := writeSegment(w, marker, segment); err != nil {
+			return removed, err
+		}
+	}
+
+	return removed, nil
+}
+
+func readMarker(r *bufio.Reader) (byte, error) {
+	prefix, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if prefix != jpegMarkerPrefix {
+		return 0, fmt.Errorf("expected marker prefix 0xFF, got 0x%X", prefix)
+	}
+	marker, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	// 0xFF-Padding zwischen Markern überspringen.
+	for marker == jpegMarkerPrefix {
+		marker, err = r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+	}
+	return marker, nil
+}
+
+// readSegment liest die 2-Byte-Längenangabe (die sich selbst einschließt)
+// plus den restlichen Segment-Payload.
+func readSegment(r *bufio.Reader) ([]byte, error) {
+	lenBytes := make([]byte, 2)
+	if _, err := io.ReadFull(r, lenBytes); err != nil {
+		return nil, err
+	}
+	length := int(lenBytes[0])<<8 | int(lenBytes[1])
+	if length < 2 {
+		return nil, fmt.Errorf("invalid segment length %d", length)
+	}
+
+	payload := make([]byte, length-2)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func writeSegment(w *bufio.Writer, marker byte, payload []byte) error {
+	length := len(payload) + 2
+	if _, err := w.Write([]byte{jpegMarkerPrefix, marker, byte(length >> 8), byte(length)}); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
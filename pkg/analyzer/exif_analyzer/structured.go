@@ -0,0 +1,262 @@
+package exifanalyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// MetadataReport bündelt alle aus einer Bilddatei extrahierbaren Metadaten-
+// Container (EXIF/TIFF, XMP, IPTC-IIM, ICC) zu einem einzigen Report, den
+// der "metadata-structured"-Detektor (siehe pipeline.getDefaultStages) gegen
+// bekannte AI-Generator-Signaturen und verdächtige Tag-Kombinationen
+// auswertet - EXIFData allein reicht dafür nicht, weil dort nur die wenigen
+// Felder liegen, auf die die ältere calculateEXIFScore reagiert.
+type MetadataReport struct {
+	Exif             *EXIFData         `json:"exif,omitempty"`
+	XMP              map[string]string `json:"xmp,omitempty"`
+	IPTC             map[string]string `json:"iptc,omitempty"`
+	ICC              *ICCProfile       `json:"icc,omitempty"`
+	MakerNotes       string            `json:"maker_notes,omitempty"`
+	SoftwareTag      string            `json:"software_tag,omitempty"`
+	DateTimeOriginal string            `json:"date_time_original,omitempty"`
+	GPS              *[2]float64       `json:"gps,omitempty"`
+
+	// KnownAIGenerator ist der Name des erkannten Generators (z.B. "Stable
+	// Diffusion"), sofern ein bekannter Marker in Software/CreatorTool/XMP/
+	// IPTC gefunden wurde - sonst leer.
+	KnownAIGenerator string `json:"known_ai_generator,omitempty"`
+	// SuspiciousCombinations listet erkannte verdächtige Tag-Kombinationen,
+	// z.B. ein Kameramodell ohne Objektiv- oder GPS-Angabe.
+	SuspiciousCombinations []string `json:"suspicious_combinations,omitempty"`
+	// StrippedMetadata ist true, wenn die Datei praktisch keinen der obigen
+	// Metadaten-Container enthält - für organisch fotografierte Bilder
+	// unüblich und ein Muster, das viele Generatoren/Anonymisierer erzeugen.
+	StrippedMetadata bool `json:"stripped_metadata"`
+}
+
+// ICCProfile hält die wenigen ICC-Felder, die hier interessieren - kein
+// vollständiger ICC-Parser, derselben Pragmatik wie applyXMPIPTC folgend.
+type ICCProfile struct {
+	Present     bool   `json:"present"`
+	Description string `json:"description,omitempty"`
+}
+
+// knownAIGeneratorMarkers ordnet Substrings, die in Software/CreatorTool/XMP/
+// IPTC auftauchen können, ihrem menschenlesbaren Generator-Namen zu.
+var knownAIGeneratorMarkers = []struct {
+	Substring string
+	Generator string
+}{
+	{"stable diffusion", "Stable Diffusion"},
+	{"midjourney", "Midjourney"},
+	{"dall-e", "DALL-E"},
+	{"dall·e", "DALL-E"},
+	{"chatgpt", "ChatGPT"},
+	{"c2pa", "C2PA Manifest"},
+}
+
+// AnalyzeStructuredMetadata liest alle bekannten Metadaten-Container aus
+// path und reichert sie um die AI-Generator-/Suspicious-Combination-/
+// Stripped-Metadata-Heuristiken an.
+func AnalyzeStructuredMetadata(path string) (*MetadataReport, error) {
+	exifData, err := AnalyzeEXIF(path)
+	if err != nil {
+		return nil, fmt.Errorf("analyze exif: %w", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	report := &MetadataReport{
+		Exif:        exifData,
+		SoftwareTag: exifData.Software,
+		MakerNotes:  exifData.MakerNoteFingerprint,
+		GPS:         exifData.GPS,
+	}
+	if exifData.DateTime != nil {
+		report.DateTimeOriginal = exifData.DateTime.Format("2006-01-02 15:04:05")
+	}
+
+	report.XMP = extractXMPFields(raw)
+	report.IPTC = extractIPTCFields(raw)
+	report.ICC = extractICCProfile(raw)
+
+	report.KnownAIGenerator = detectKnownAIGenerator(report)
+	report.SuspiciousCombinations = detectSuspiciousCombinations(exifData)
+	report.StrippedMetadata = isStrippedMetadata(report)
+
+	return report, nil
+}
+
+// ToResultMap serialisiert report in ein map[string]interface{}, wie es die
+// übrigen Analyzer-Stages liefern (siehe pipeline.getDefaultStages) - damit
+// verdict.calculateMetadataStructuredScore es per utils.GetStringValue
+// auswerten kann und die API-Antwort den vollständigen strukturierten Report
+// für Downstream-Tooling enthält.
+func (r *MetadataReport) ToResultMap() (map[string]interface{}, error) {
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("marshal metadata report: %w", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("unmarshal metadata report: %w", err)
+	}
+
+	result["has_camera_info"] = r.Exif != nil && (r.Exif.Make != "" || r.Exif.Model != "")
+	return result, nil
+}
+
+var xmpParametersRe = regexp.MustCompile(`parameters(?:>|=")([^<"]+)`)
+
+// extractXMPFields liest dieselben Felder wie applyXMPIPTC (siehe xmp.go),
+// zusätzlich das "parameters"-Feld, unter dem manche Generatoren ihre
+// Prompt-/Sampler-Konfiguration ablegen.
+func extractXMPFields(raw []byte) map[string]string {
+	xmp := extractXMPPacket(raw)
+	if xmp == nil {
+		return nil
+	}
+
+	fields := make(map[string]string)
+	if v := firstSubmatch(xmp, xmpCreatorToolRe); v != "" {
+		fields["creator_tool"] = v
+	}
+	if v := firstSubmatch(xmp, digitalSourceTypeRe); v != "" {
+		fields["digital_source_type"] = v
+	}
+	if v := firstSubmatch(xmp, xmpParametersRe); v != "" {
+		fields["parameters"] = v
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// iptcTagNames ordnet die wenigen IPTC-IIM-Record-2-Tags, die für die
+// AI-Generator-/Stripped-Metadata-Heuristik interessieren, ihrem Namen zu.
+var iptcTagNames = map[byte]string{
+	25:  "keywords",
+	80:  "by_line",
+	105: "headline",
+	120: "caption",
+}
+
+// extractIPTCFields scannt raw nach IPTC-IIM-Records (Marker 0x1C 0x02) im
+// Photoshop-IRB (APP13) - kein vollständiger IIM-Parser, nur die wenigen
+// Record-2-Tags aus iptcTagNames.
+func extractIPTCFields(raw []byte) map[string]string {
+	fields := make(map[string]string)
+
+	for i := 0; i+4 < len(raw); i++ {
+		if raw[i] != 0x1C || raw[i+1] != 0x02 {
+			continue
+		}
+
+		tag := raw[i+2]
+		length := int(raw[i+3])<<8 | int(raw[i+4])
+		start := i + 5
+		end := start + length
+		if length < 0 || end > len(raw) {
+			continue
+		}
+
+		if name, ok := iptcTagNames[tag]; ok {
+			fields[name] = string(bytes.TrimSpace(raw[start:end]))
+		}
+		i = end - 1
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+var (
+	iccMarker        = []byte("ICC_PROFILE")
+	iccDescriptionRe = regexp.MustCompile(`desc.{4}([\x20-\x7e]{4,64})`)
+)
+
+// extractICCProfile prüft auf das "ICC_PROFILE"-Segment (JPEG APP2) bzw. die
+// entsprechenden Bytes in anderen Containern und versucht, das enthaltene
+// "desc"-Tag als Profilbeschreibung zu lesen - approximativ, kein
+// vollständiger ICC-Parser.
+func extractICCProfile(raw []byte) *ICCProfile {
+	idx := bytes.Index(raw, iccMarker)
+	if idx < 0 {
+		return &ICCProfile{Present: false}
+	}
+
+	window := raw[idx:min(idx+2048, len(raw))]
+	return &ICCProfile{
+		Present:     true,
+		Description: firstSubmatch(window, iccDescriptionRe),
+	}
+}
+
+// detectKnownAIGenerator durchsucht alle Text-Metadatenfelder des Reports
+// nach den Substrings aus knownAIGeneratorMarkers und liefert den Namen des
+// ersten Treffers, oder "" wenn keiner gefunden wurde.
+func detectKnownAIGenerator(report *MetadataReport) string {
+	haystacks := []string{report.SoftwareTag}
+	if report.Exif != nil {
+		haystacks = append(haystacks, report.Exif.CreatorTool, report.Exif.DigitalSourceType)
+	}
+	for _, v := range report.XMP {
+		haystacks = append(haystacks, v)
+	}
+	for _, v := range report.IPTC {
+		haystacks = append(haystacks, v)
+	}
+
+	for _, haystack := range haystacks {
+		lower := strings.ToLower(haystack)
+		for _, marker := range knownAIGeneratorMarkers {
+			if strings.Contains(lower, marker.Substring) {
+				return marker.Generator
+			}
+		}
+	}
+	return ""
+}
+
+// detectSuspiciousCombinations erkennt Tag-Kombinationen, die für eine echte
+// Kamera-Aufnahme untypisch sind - aktuell: ein Kameramodell ohne jede
+// Objektiv- oder GPS-Angabe, wie es bei teilweise kopierten oder
+// nachträglich eingefügten EXIF-Blöcken vorkommt.
+func detectSuspiciousCombinations(exifData *EXIFData) []string {
+	var findings []string
+	if exifData == nil {
+		return findings
+	}
+
+	hasCamera := exifData.Make != "" || exifData.Model != ""
+	if hasCamera && exifData.LensModel == "" && exifData.GPS == nil {
+		findings = append(findings, "camera_model_without_lens_or_gps")
+	}
+	return findings
+}
+
+// isStrippedMetadata meldet, ob report praktisch keinen der bekannten
+// Metadaten-Container enthält.
+func isStrippedMetadata(report *MetadataReport) bool {
+	if report.Exif != nil && len(report.Exif.Raw) > 0 {
+		return false
+	}
+	if len(report.XMP) > 0 || len(report.IPTC) > 0 {
+		return false
+	}
+	if report.ICC != nil && report.ICC.Present {
+		return false
+	}
+	return true
+}
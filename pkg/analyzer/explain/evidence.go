@@ -0,0 +1,56 @@
+// Package explain entkoppelt die Verdict-Arithmetik von der natürlich-
+// sprachlichen Darstellung. Statt handgeschriebener fmt.Sprintf-Zweige
+// (früher generateTraditionalExplanation/generateAIExplanation/
+// generateMetadataExplanation) emittiert jeder Detektor ein Evidence-Objekt
+// mit Richtung, Gewicht und stichwortartigen Tokens; RenderSummary rendert
+// daraus per text/template eine sprachspezifische Fließtext-Zusammenfassung
+// - die Evidence-Liste selbst bleibt das stabile, maschinenlesbare Ergebnis,
+// das die Oberfläche ohne String-Scraping als Badges darstellen kann.
+package explain
+
+import (
+	"math"
+	"sort"
+)
+
+// Direction ist die grobe Stoßrichtung eines Detektor-Befunds.
+type Direction string
+
+const (
+	DirectionAI        Direction = "AI"
+	DirectionAuthentic Direction = "AUTH"
+)
+
+// Evidence ist der Befund eines einzelnen Detektors: Magnitude ist sein
+// Logit-Beitrag zur Gesamtfusion (siehe pkg/analyzer/fusion.CombineLogOdds)
+// und bestimmt damit, wie stark er das Endergebnis tatsächlich beeinflusst
+// hat - nicht bloß sein Rohscore. Tokens sind stichwortartige Belege (z.B.
+// "camera_make_present", "no_software_tag"), aus denen sich sowohl Badges
+// als auch Fließtext rendern lassen.
+type Evidence struct {
+	Detector  string    `json:"detector"`
+	Direction Direction `json:"direction"`
+	Magnitude float64   `json:"magnitude"`
+	Tokens    []string  `json:"tokens"`
+}
+
+// RankByMagnitude liefert eine nach |Magnitude| absteigend sortierte Kopie
+// von evidence, ohne die übergebene Slice zu mutieren.
+func RankByMagnitude(evidence []Evidence) []Evidence {
+	ranked := make([]Evidence, len(evidence))
+	copy(ranked, evidence)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return math.Abs(ranked[i].Magnitude) > math.Abs(ranked[j].Magnitude)
+	})
+	return ranked
+}
+
+// DirectionFor leitet die Stoßrichtung aus einer kalibrierten
+// Wahrscheinlichkeit ab - dieselbe 0.5-Schwelle, die auch
+// determineBalancedVerdict für die binäre Einordnung verwendet.
+func DirectionFor(calibratedScore float64) Direction {
+	if calibratedScore >= 0.5 {
+		return DirectionAI
+	}
+	return DirectionAuthentic
+}
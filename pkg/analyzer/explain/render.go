@@ -0,0 +1,98 @@
+package explain
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"text/template"
+)
+
+const defaultTemplateDir = "config/explain"
+
+var (
+	dirMu sync.RWMutex
+	dir   = defaultTemplateDir
+
+	cacheMu sync.Mutex
+	cached  = map[string]*template.Template{}
+)
+
+// SetTemplateDir überschreibt das Verzeichnis, aus dem Sprachvorlagen
+// geladen werden (siehe RenderSummary), und verwirft den Parse-Cache -
+// nützlich für Tests oder alternative Deployments.
+func SetTemplateDir(d string) {
+	dirMu.Lock()
+	dir = d
+	dirMu.Unlock()
+
+	cacheMu.Lock()
+	cached = map[string]*template.Template{}
+	cacheMu.Unlock()
+}
+
+func currentDir() string {
+	dirMu.RLock()
+	defer dirMu.RUnlock()
+	return dir
+}
+
+// summaryData ist der an die Sprachvorlagen übergebene Kontext: Top enthält
+// die nach |Magnitude| sortierten Top-3-Belege, All alle gesammelten.
+type summaryData struct {
+	Top []Evidence
+	All []Evidence
+}
+
+// defaultTemplateText ist der eingebaute Fallback, falls für lang keine
+// Datei unter currentDir gepflegt ist - ein fehlendes Sprach-Template ist
+// kein Fehlerzustand, nur ein Hinweis, dass es noch nicht erstellt wurde.
+const defaultTemplateText = `{{range $i, $e := .Top}}{{if $i}}; {{end}}{{$e.Detector}}: {{if eq $e.Direction "AI"}}Strong AI indicators{{else}}Authenticity indicators{{end}}{{end}}`
+
+// loadTemplate lädt und parsed <lang>.tmpl aus currentDir, gecached im
+// Prozessspeicher. Fehlt die Datei oder lässt sie sich nicht parsen, fällt
+// sie auf defaultTemplateText zurück.
+func loadTemplate(lang string) *template.Template {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if t, exists := cached[lang]; exists {
+		return t
+	}
+
+	path := filepath.Join(currentDir(), lang+".tmpl")
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		raw = []byte(defaultTemplateText)
+	}
+
+	t, err := template.New(lang).Parse(string(raw))
+	if err != nil {
+		t = template.Must(template.New(lang).Parse(defaultTemplateText))
+	}
+
+	cached[lang] = t
+	return t
+}
+
+// RenderSummary rankt evidence nach |Magnitude| (siehe RankByMagnitude) und
+// rendert daraus eine Fließtext-Zusammenfassung der Top-3-Belege über die
+// Sprachvorlage lang (siehe config/explain/<lang>.tmpl). Die
+// Evidence-Objekte selbst bleiben das stabile, maschinenlesbare Ergebnis -
+// der Fließtext ist nur eine von mehreren möglichen Darstellungen derselben
+// Daten.
+func RenderSummary(evidence []Evidence, lang string) (string, error) {
+	ranked := RankByMagnitude(evidence)
+	top := ranked
+	if len(top) > 3 {
+		top = top[:3]
+	}
+
+	t := loadTemplate(lang)
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, summaryData{Top: top, All: ranked}); err != nil {
+		return "", fmt.Errorf("render evidence summary: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,77 @@
+package explain
+
+// TokensFor leitet stichwortartige Evidence-Tokens für detector aus seinen
+// rohen Analyse-Ergebnisdaten ab (dasselbe map[string]interface{}, das auch
+// die calculateXScore-Funktionen in internal/handlers/verdict auswerten).
+// Detektoren ohne spezialisierte Tokens fallen auf eine grobe
+// Score-Einordnung zurück.
+func TokensFor(detector string, calibratedScore float64, data map[string]interface{}) []string {
+	switch detector {
+	case "exif":
+		return exifTokens(data)
+	case "metadata":
+		return presenceTokens(data, "has_metadata", "metadata_present", "no_metadata")
+	case "c2pa":
+		return c2paTokens(data)
+	default:
+		return genericTokens(calibratedScore)
+	}
+}
+
+func exifTokens(data map[string]interface{}) []string {
+	tokens := make([]string, 0, 3)
+
+	if cameraMake, ok := getString(data, "camera_make"); ok && cameraMake != "" {
+		tokens = append(tokens, "camera_make_present")
+	} else {
+		tokens = append(tokens, "no_camera_make")
+	}
+
+	if gps, exists := data["gps"]; exists && gps != nil {
+		tokens = append(tokens, "gps_present")
+	} else {
+		tokens = append(tokens, "no_gps")
+	}
+
+	if software, ok := getString(data, "software"); ok && software != "" {
+		tokens = append(tokens, "software_tag_present")
+	} else {
+		tokens = append(tokens, "no_software_tag")
+	}
+
+	return tokens
+}
+
+func c2paTokens(data map[string]interface{}) []string {
+	if sourceType, ok := getString(data, "digital_source_type"); ok && sourceType != "" {
+		return []string{"digital_source_type_present"}
+	}
+	return []string{"no_digital_source_type"}
+}
+
+func presenceTokens(data map[string]interface{}, field, presentToken, absentToken string) []string {
+	if present, ok := data[field].(bool); ok && present {
+		return []string{presentToken}
+	}
+	return []string{absentToken}
+}
+
+func genericTokens(calibratedScore float64) []string {
+	switch {
+	case calibratedScore >= 0.7:
+		return []string{"high_score"}
+	case calibratedScore <= 0.3:
+		return []string{"low_score"}
+	default:
+		return []string{"moderate_score"}
+	}
+}
+
+func getString(data map[string]interface{}, key string) (string, bool) {
+	v, exists := data[key]
+	if !exists {
+		return "", false
+	}
+	s, ok := v.(string)
+	return s, ok
+}
@@ -0,0 +1,108 @@
+package fusion
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// LabeledScore ist eine einzelne (kalibrierte Wahrscheinlichkeit,
+// Ground-Truth-Label)-Beobachtung eines Detektors aus einem historischen,
+// annotierten Analyse-Durchlauf.
+type LabeledScore struct {
+	Probability float64
+	Label       float64 // 1 = AI-generated, 0 = authentic
+}
+
+// Fit schätzt den Klassen-Prior sowie das Zuverlässigkeitsgewicht jedes
+// Detektors (w_i = 2*AUC_i - 1, geflort bei 0) aus labeled - einem
+// annotierten Validierungsdatensatz, ein Eintrag pro Detektor-Name - und
+// liefert den resultierenden Gewichte-Satz, ohne ihn zu persistieren (siehe
+// Save).
+func Fit(labeled map[string][]LabeledScore) Weights {
+	w := Weights{Factors: map[string]float64{}}
+
+	var positives, total float64
+	for _, scores := range labeled {
+		for _, s := range scores {
+			positives += s.Label
+			total++
+		}
+	}
+	if total > 0 {
+		w.Prior = positives / total
+	}
+	if w.Prior <= 0 || w.Prior >= 1 {
+		w.Prior = defaultPrior
+	}
+
+	for detector, scores := range labeled {
+		auc := computeAUC(scores)
+		reliability := 2*auc - 1
+		if reliability < 0 {
+			reliability = 0
+		}
+		w.Factors[detector] = reliability
+	}
+
+	return w
+}
+
+// Save persistiert w unter dem aktuell konfigurierten Pfad (siehe SetPath)
+// und verwirft den In-Memory-Cache, damit der nächste CombineLogOdds-Aufruf
+// die neuen Gewichte sieht.
+func Save(w Weights) error {
+	raw, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	p := currentPath()
+	if dir := filepath.Dir(p); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(p, raw, 0644); err != nil {
+		return err
+	}
+
+	cacheMu.Lock()
+	cached = nil
+	cacheMu.Unlock()
+
+	return nil
+}
+
+// computeAUC schätzt die ROC-AUC eines Detektors über die
+// Mann-Whitney-U-Statistik: den Anteil aller (positiv, negativ)-Paare, bei
+// denen der Detektor der positiven Beobachtung die höhere Wahrscheinlichkeit
+// zuweist (Unentschieden zählen halb).
+func computeAUC(scores []LabeledScore) float64 {
+	var positives, negatives []float64
+	for _, s := range scores {
+		if s.Label >= 0.5 {
+			positives = append(positives, s.Probability)
+		} else {
+			negatives = append(negatives, s.Probability)
+		}
+	}
+	if len(positives) == 0 || len(negatives) == 0 {
+		return 0.5
+	}
+
+	var concordant, tied float64
+	for _, p := range positives {
+		for _, n := range negatives {
+			switch {
+			case p > n:
+				concordant++
+			case p == n:
+				tied++
+			}
+		}
+	}
+
+	total := float64(len(positives) * len(negatives))
+	return (concordant + 0.5*tied) / total
+}
@@ -0,0 +1,146 @@
+// Package fusion kombiniert kalibrierte Pro-Detektor-Wahrscheinlichkeiten zu
+// einer einzigen Gesamtwahrscheinlichkeit per naive-Bayes-artiger
+// Log-Odds-Fusion - statt des arithmetischen Mittels mit Ad-hoc-patternBoost,
+// das zuvor in internal/handlers/verdict/boost.go lag. Jeder Detektor
+// bekommt ein gelerntes Zuverlässigkeitsgewicht w_i in [0,1], abgeleitet aus
+// seiner Validierungs-AUC (siehe Fit) - ein Detektor ohne gefittetes Gewicht
+// wird mit vollem Vertrauen (w_i=1) einbezogen.
+package fusion
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"sync"
+)
+
+const (
+	logitClamp    = 8.0
+	defaultPrior  = 0.5
+	defaultWeight = 1.0
+)
+
+// Weights ist die persistierte Ausgabe von Fit: ein Klassen-Prior sowie ein
+// Zuverlässigkeitsgewicht pro Detektor.
+type Weights struct {
+	Prior   float64            `json:"prior"`
+	Factors map[string]float64 `json:"weights"`
+}
+
+var (
+	pathMu sync.RWMutex
+	path   = "config/fusion_weights.json"
+
+	cacheMu sync.Mutex
+	cached  *Weights
+)
+
+// SetPath überschreibt den Pfad, aus dem Fit-Gewichte geladen (und von Save
+// geschrieben) werden, und verwirft den In-Memory-Cache - nützlich für Tests
+// oder alternative Deployments.
+func SetPath(p string) {
+	pathMu.Lock()
+	path = p
+	pathMu.Unlock()
+
+	cacheMu.Lock()
+	cached = nil
+	cacheMu.Unlock()
+}
+
+func currentPath() string {
+	pathMu.RLock()
+	defer pathMu.RUnlock()
+	return path
+}
+
+func currentWeights() Weights {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if cached != nil {
+		return *cached
+	}
+
+	w := loadWeights()
+	cached = &w
+	return w
+}
+
+// loadWeights liest den gefitteten Gewichte-Satz vom konfigurierten Pfad.
+// Fehlt die Datei oder lässt sie sich nicht parsen, liefert sie den neutralen
+// Default (Prior 0.5, keine Detektor-spezifischen Gewichte) - ein fehlendes
+// Fit ist kein Fehlerzustand, nur ein Hinweis, dass noch nicht gegen
+// annotierte Daten trainiert wurde.
+func loadWeights() Weights {
+	raw, err := os.ReadFile(currentPath())
+	if err != nil {
+		return Weights{Prior: defaultPrior, Factors: map[string]float64{}}
+	}
+
+	var w Weights
+	if err := json.Unmarshal(raw, &w); err != nil {
+		return Weights{Prior: defaultPrior, Factors: map[string]float64{}}
+	}
+	if w.Factors == nil {
+		w.Factors = map[string]float64{}
+	}
+	if w.Prior <= 0 || w.Prior >= 1 {
+		w.Prior = defaultPrior
+	}
+	return w
+}
+
+func weightFor(w Weights, detector string) float64 {
+	if factor, exists := w.Factors[detector]; exists {
+		return factor
+	}
+	return defaultWeight
+}
+
+// CombineLogOdds kombiniert die kalibrierten Wahrscheinlichkeiten aus probs
+// per Log-Odds-Fusion gegen den aktiv geladenen Prior/Gewichte-Satz (siehe
+// Fit/Save) und liefert die fusionierte Gesamtwahrscheinlichkeit sowie den
+// Logit-Beitrag jedes Detektors (w_i * (logit_i - logit_prior)) - letzteres
+// ist das auditierbare Feld, das die alten CONTRIB-Debug-Prints ersetzt.
+func CombineLogOdds(probs map[string]float64) (float64, map[string]float64) {
+	w := currentWeights()
+	logitPrior := clampLogit(logit(w.Prior))
+
+	l := logitPrior
+	contributions := make(map[string]float64, len(probs))
+
+	for detector, p := range probs {
+		li := clampLogit(logit(p))
+		wi := weightFor(w, detector)
+		contribution := wi * (li - logitPrior)
+		contributions[detector] = contribution
+		l += contribution
+	}
+
+	return sigmoid(l), contributions
+}
+
+func logit(p float64) float64 {
+	const eps = 1e-6
+	if p < eps {
+		p = eps
+	} else if p > 1-eps {
+		p = 1 - eps
+	}
+	return math.Log(p / (1 - p))
+}
+
+func clampLogit(l float64) float64 {
+	if l > logitClamp {
+		return logitClamp
+	}
+	if l < -logitClamp {
+		return -logitClamp
+	}
+	return l
+}
+
+func sigmoid(x float64) float64 {
+	return 1.0 / (1.0 + math.Exp(-x))
+}
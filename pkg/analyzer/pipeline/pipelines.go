@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"os"
+	"runtime"
+	"runtime/pprof"
 	"strings"
 	"sync"
 	"time"
@@ -17,6 +20,23 @@ import (
 	"github.com/BramseDev/imageAnalyzer/pkg/rustrunner"
 )
 
+// StageAnalyzer ist die Analyzer-Funktion einer AnalysisStage. deps enthält
+// die zum Zeitpunkt des Aufrufs bereits abgeschlossenen Ergebnisse der in
+// AnalysisStage.Dependencies gelisteten Stages (siehe runDependencyGraph) -
+// leer für Stages ohne Dependencies, und auch dann leer, wenn eine
+// gelistete Dependency für das aktuelle MIME-Profil gar nicht läuft (siehe
+// sortedStagesForMIME). withoutDeps wrapt die älteren, dependency-losen
+// Analyzer (EXIF, C2PA, Metadata) in diese Signatur.
+type StageAnalyzer func(ctx context.Context, imagePath string, deps map[string]interface{}) (interface{}, error)
+
+// withoutDeps wrapt einen Analyzer, der keine Dependencies konsumiert, in
+// die StageAnalyzer-Signatur - deps wird einfach ignoriert.
+func withoutDeps(fn func(context.Context, string) (interface{}, error)) StageAnalyzer {
+	return func(ctx context.Context, imagePath string, _ map[string]interface{}) (interface{}, error) {
+		return fn(ctx, imagePath)
+	}
+}
+
 // AnalysisStage definiert eine einzelne Analysestufe
 type AnalysisStage struct {
 	Name         string
@@ -24,93 +44,444 @@ type AnalysisStage struct {
 	FastTrack    bool
 	Timeout      time.Duration
 	Dependencies []string
-	Analyzer     func(context.Context, string) (interface{}, error)
+	Analyzer     StageAnalyzer
+
+	// StreamingAnalyzer ist, sofern gesetzt, eine Variante von Analyzer, die
+	// vor ihrem Endergebnis Zwischenfortschritt an onProgress meldet (siehe
+	// pythonrunner.StreamingAnalyzerFunc) - nur bei den python-gestützten
+	// Stages gesetzt (siehe pythonStage). RunAnalysisStream nutzt sie, wenn
+	// vorhanden, und fällt sonst auf Analyzer zurück, dessen Endergebnis
+	// dann als einzelnes "completed"-Event läuft.
+	StreamingAnalyzer func(ctx context.Context, imagePath string, onProgress func(interface{})) (interface{}, error)
+
+	// ScoreExtractor liest aus dem Ergebnis dieser Stage die kalibrierte
+	// AI-Wahrscheinlichkeit aus (-1, wenn die Stage kein Score-Feld
+	// beisteuert) - siehe stageScoreExtractors. Grundlage für
+	// calculateFinalConfidence/calculateEarlyConfidence, die die so
+	// gesammelten Scores per Log-Odds-Fusion statt einer reinen
+	// Stage-Count-Ratio kombinieren. Bleibt es nil (z.B. für exif), trägt
+	// die Stage nichts zur Fusion bei, läuft aber weiterhin normal.
+	ScoreExtractor func(data interface{}) float64
 }
 
 // PipelineResult enthält das Gesamtergebnis der Pipeline
 type PipelineResult struct {
-	Results     map[string]interface{}
-	StagesRun   []string
-	ProcessTime time.Duration
-	EarlyExit   bool
-	Confidence  float64
-	CacheHit    bool
+	Results      map[string]interface{}
+	StagesRun    []string
+	ProcessTime  time.Duration
+	EarlyExit    bool
+	Confidence   float64
+	CacheHit     bool
+	StageMetrics map[string]StageMetric
+
+	// MimeType ist der von DetectMIME am pipeline-sichtbaren Bild erkannte
+	// MIME-Typ (nach einer eventuellen HEIC/AVIF/DNG-Transcodierung, siehe
+	// internal/handlers/utils/convert.go) - bestimmt über Registry, welche
+	// Stages überhaupt ausgeführt wurden, und dient
+	// verdict.calculateAnalysisQuality als Grundlage für den erwarteten
+	// Detektor-Nenner. Leer, wenn DetectMIME fehlschlug.
+	MimeType string
+
+	// Verdict wird nicht von der Pipeline selbst gesetzt, sondern von
+	// verdict.CalculateOverallVerdict nachträglich auf das bereits gelaufene
+	// PipelineResult geschrieben (siehe dort) - hier als Feld geführt, damit
+	// Aufrufer wie Metrics.RecordVerdict das typisierte Enum statt eines
+	// rohen Label-Strings bekommen.
+	Verdict Verdict
+
+	// Contributions ist der Logit-Beitrag (w_i * (logit_i - logit_prior))
+	// jeder Stage, deren ScoreExtractor ein Ergebnis geliefert hat, zur
+	// über calculateFinalConfidence/calculateEarlyConfidence fusionierten
+	// Confidence - siehe combineStageConfidence. Getrennt von
+	// verdict.CalculateOverallVerdict's eigenem "weights_used" (das aus
+	// pkg/analyzer/fusion stammt und gegen die kalibrierten Detektor-Scores
+	// rechnet): dies hier ist die schnelle, Pipeline-interne Schätzung, die
+	// schon vor einer eventuellen vollen Verdict-Berechnung vorliegt.
+	Contributions map[string]float64
+}
+
+// StageMetric hält die pro-Stage Profiling-Daten, die während einer
+// live genommenen pprof-CPU-Probe den einzelnen Analyzern zugeordnet werden
+// können (über das "stage"-pprof.Label, siehe runStage) sowie die dabei
+// beobachteten Speicher-Allokationen.
+type StageMetric struct {
+	Duration   time.Duration
+	AllocBytes int64
+	AllocCount uint64
+}
+
+// StageEvent ist ein einzelnes Fortschritts- oder Abschluss-Ereignis, das
+// RunAnalysisStream über den zurückgegebenen Channel sendet - ein Aufrufer
+// wie ein SSE-Handler kann jedes Event direkt (z.B. per json.Marshal) an
+// einen Client weiterreichen.
+type StageEvent struct {
+	// Stage ist der Name der Stage (siehe AnalysisStage.Name), die dieses
+	// Event ausgelöst hat. Leer beim abschließenden "done"-Event, das kein
+	// einzelner Stage mehr zuzuordnen ist.
+	Stage string `json:"stage"`
+	// Status ist einer von "progress" (Zwischenmeldung eines
+	// StreamingAnalyzer), "completed", "failed" oder - als letztes Event
+	// des Channels - "done".
+	Status string `json:"status"`
+	// PartialResult ist beim "progress"-Event der vom Analyzer gemeldete
+	// Zwischenstand, bei "completed"/"failed" dessen Endergebnis bzw.
+	// Fehlertext, und beim abschließenden "done"-Event das komplette
+	// *PipelineResult dieses Laufs.
+	PartialResult interface{} `json:"partial_result,omitempty"`
+	// Elapsed ist die seit Start dieser Stage (bzw. seit Start der
+	// gesamten Analyse beim "done"-Event) vergangene Zeit.
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// runStage führt einen Analyzer innerhalb von pprof.Do mit einem
+// "stage"-Label aus, damit eine während der Analyse gezogene CPU-Probe
+// (z.B. über /dashboard/debug/pprof/profile) die Zeit dem jeweiligen
+// Analyzer zuordnet statt sie pauschal der Pipeline zuzuschreiben.
+func runStage(ctx context.Context, stage AnalysisStage, imagePath string, deps map[string]interface{}) (interface{}, error, StageMetric) {
+	var memBefore, memAfter runtime.MemStats
+	runtime.ReadMemStats(&memBefore)
+	start := time.Now()
+
+	var result interface{}
+	var err error
+
+	pprof.Do(ctx, pprof.Labels("stage", stage.Name), func(stageCtx context.Context) {
+		result, err = stage.Analyzer(stageCtx, imagePath, deps)
+	})
+
+	runtime.ReadMemStats(&memAfter)
+
+	metric := StageMetric{
+		Duration:   time.Since(start),
+		AllocBytes: int64(memAfter.TotalAlloc) - int64(memBefore.TotalAlloc),
+		AllocCount: memAfter.Mallocs - memBefore.Mallocs,
+	}
+
+	return result, err, metric
+}
+
+// DetectorVersion kennzeichnet die aktuelle Generation der Analyzer-Stages
+// (siehe getDefaultStages) - geht in den Cache-Key ein, damit ein Deploy mit
+// geänderten/neuen Analyzer-Skripten alte, inkompatible Cache-Einträge nicht
+// weiterverwendet. Bei jeder Änderung an einer Stage hochzählen.
+const DetectorVersion = "v1"
+
+// ConfigFingerprint liefert, wenn gesetzt, einen kurzen Hash der aktuell
+// aktiven Scoring-Konfiguration (Detector-Gewichte, Kalibrierungsprofil) -
+// geht zusammen mit DetectorVersion in den Cache-Key ein, damit ein
+// Reload von detector.yaml/calibration.yaml alte Cache-Einträge nicht mit
+// einer veralteten Konfiguration vermischt. Dieses Paket kennt das
+// verdict-Paket nicht (Importzyklus), daher wird der Hook analog zu
+// verdict.OnCalibrationReload von main.go verdrahtet; bleibt er nil, wird
+// "default" verwendet.
+var ConfigFingerprint func() string
+
+func configFingerprint() string {
+	if ConfigFingerprint == nil {
+		return "default"
+	}
+	return ConfigFingerprint()
 }
 
 var (
-	globalCache *cache.AnalysisCache
+	globalCache cache.Backend
 	cacheOnce   sync.Once
 )
 
-func getGlobalCache() *cache.AnalysisCache {
+func getGlobalCache() cache.Backend {
 	cacheOnce.Do(func() {
 		globalCache = cache.NewAnalysisCache()
 	})
 	return globalCache
 }
 
+// SetGlobalCacheBackend ersetzt den geteilten Analysis-Cache durch backend -
+// etwa ein cache.RedisBackend, wenn Operator den Cache über mehrere
+// Instanzen teilen wollen. Muss vor der ersten Pipeline-Ausführung
+// aufgerufen werden, sonst laufen bereits gestartete Pipelines mit dem
+// zuvor aktiven Backend weiter.
+func SetGlobalCacheBackend(backend cache.Backend) {
+	cacheOnce.Do(func() {})
+	globalCache = backend
+}
+
+// GetGlobalCacheStats liefert Stats vom geteilten Analysis-Cache, unabhängig
+// davon, wie viele Pipeline-Instanzen gerade existieren (sie teilen sich
+// alle denselben Cache über getGlobalCache).
+func GetGlobalCacheStats() cache.Stats {
+	return getGlobalCache().Stats()
+}
+
+// PurgeCacheEntry entfernt einen einzelnen Eintrag aus dem geteilten
+// Analysis-Cache - Grundlage für das /cache Admin-Endpoint. hash ist der
+// rohe Inhalts-Hash, nicht der interne Cache-Key.
+func PurgeCacheEntry(hash string) bool {
+	return getGlobalCache().Delete(cacheKeyForHash(hash))
+}
+
+// PurgeCacheKey entfernt einen Eintrag über den bereits vollständigen
+// internen Cache-Key (wie von CacheKeys zurückgegeben) - anders als
+// PurgeCacheEntry wird hash hier nicht nochmal über cacheKeyForHash
+// verpackt.
+func PurgeCacheKey(key string) bool {
+	return getGlobalCache().Delete(key)
+}
+
+// CacheKeys listet die Keys im Memory-Tier des geteilten Analysis-Caches auf -
+// für das /cache Admin-Endpoint.
+func CacheKeys() []string {
+	return getGlobalCache().Keys()
+}
+
+// LookupByHash prüft den geteilten Analysis-Cache direkt über den
+// Inhalts-Hash, ohne eine Pipeline-Instanz zu benötigen - Grundlage für den
+// clientseitigen Hash-Pre-Check (siehe uploadCheckHandler), der Clients
+// erlaubt, ein bereits analysiertes Bild zu erkennen, ohne dessen Bytes
+// erneut hochzuladen. hash ist derselbe SHA-256-Inhalts-Hash, den
+// generateCacheKey aus der Bilddatei ableitet.
+func LookupByHash(hash string) (*PipelineResult, bool) {
+	cached, found := getGlobalCache().Get(cacheKeyForHash(hash))
+	if !found {
+		return nil, false
+	}
+
+	result, ok := cached.(*PipelineResult)
+	return result, ok
+}
+
+// cacheKeyForHash hängt DetectorVersion und die aktive Config-Fingerprint an
+// den Inhalts-Hash an, damit ein Deploy mit neuen Analyzer-Skripten oder
+// einem geänderten Scoring-Profil alte Einträge nicht wiederverwendet.
+func cacheKeyForHash(hash string) string {
+	return fmt.Sprintf("analysis_%s_%s_%s", hash, DetectorVersion, configFingerprint())
+}
+
 // MetricsRecorder Interface für Cache-Tracking
 type MetricsRecorder interface {
 	RecordCacheHit()
 	RecordCacheMiss()
+
+	// RecordDetectorResult verbucht, ob die Stage name in diesem Durchlauf
+	// erfolgreich war (err == nil) oder fehlgeschlagen ist - Grundlage für
+	// alerts.DetectorFailureRateRule, das einen ausgefallenen Python-Skript-
+	// Detektor erkennen soll, bevor er genug Stages mitreißt, um den
+	// Overall-Error-Rate-Alert auszulösen.
+	RecordDetectorResult(name string, err error)
+
+	// RecordStageDuration verbucht, wie lange die Stage name für diesen
+	// Durchlauf gebraucht hat - runStage/runFastTrackStages messen das ohnehin
+	// schon für StageMetric, dieser Aufruf macht dieselbe Zahl zusätzlich im
+	// Prometheus-Export (monitoring/prom, analyzer_analysis_duration_seconds)
+	// je Stage-Name sichtbar.
+	RecordStageDuration(name string, duration time.Duration, err error)
+
+	// RecordEarlyExit verbucht einen Early Exit, gelabelt mit der Stage, die
+	// ihn ausgelöst hat (siehe shouldEarlyExit) - erlaubt zu unterscheiden, ob
+	// Early Exits überwiegend über metadata-quick oder über c2pa laufen.
+	RecordEarlyExit(reason string)
+
+	// RecordPipelineDuration verbucht die Gesamtlaufzeit eines RunAnalysis-
+	// Aufrufs, getrennt nach Cache-Hit/-Miss - ein Cache-Hit besteht fast nur
+	// aus dem Cache-Lookup und würde die Laufzeit-Statistik sonst nach unten
+	// verzerren.
+	RecordPipelineDuration(duration time.Duration, cacheHit bool)
+
+	// RecordStageScore verbucht den von ScoreExtractor gelieferten Rohscore
+	// einer Stage (siehe combineStageConfidence) - Grundlage für eine
+	// Drift-Beobachtung je Stage, unabhängig von der fusionierten Gesamt-
+	// Confidence.
+	RecordStageScore(name string, score float64)
+}
+
+// noCacheKey ist der Context-Key, über den ein Aufrufer RunAnalysis anweist,
+// den Analysis-Cache für diesen einen Aufruf zu umgehen (siehe WithNoCache) -
+// etwa für den ?nocache=1 Query-Parameter von /upload.
+type noCacheKey struct{}
+
+// WithNoCache markiert ctx so, dass RunAnalysis den Analysis-Cache weder
+// liest noch nach der Analyse beschreibt - für Aufrufer, die einen
+// erzwungenen Re-Run brauchen (z.B. nach einem Analyzer-Fix, ohne
+// DetectorVersion hochzuzählen).
+func WithNoCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+func noCacheFromContext(ctx context.Context) bool {
+	v, _ := ctx.Value(noCacheKey{}).(bool)
+	return v
 }
 
 // AnalysisPipeline Hauptstruktur
 type AnalysisPipeline struct {
 	stages           []AnalysisStage
-	cache            *cache.AnalysisCache
+	cache            cache.Backend
 	metrics          MetricsRecorder
 	earlyExitEnabled bool
+	maxConcurrency   int
 	mu               sync.RWMutex
 }
 
+// defaultMaxConcurrency begrenzt, wie viele Stages runDependencyGraph
+// gleichzeitig startet, solange niemand SetMaxConcurrency aufgerufen hat -
+// die meisten Stages rufen ohnehin einen eigenen Python-Worker-Prozess auf
+// (siehe pythonrunner.BackendManager), daher begrenzt dieser Wert primär die
+// parallele CPU-/Speicherlast auf dem Host, nicht Go-Routinen an sich.
+const defaultMaxConcurrency = 4
+
 // NewAnalysisPipeline erstellt eine neue Pipeline-Instanz ohne Cache
 func NewAnalysisPipeline() *AnalysisPipeline {
+	stages := getDefaultStages()
+	if err := validateDAG(stages); err != nil {
+		panic(fmt.Sprintf("pipeline: invalid stage dependency graph: %v", err))
+	}
 	return &AnalysisPipeline{
-		stages:           getDefaultStages(),
+		stages:           stages,
 		cache:            getGlobalCache(),
 		earlyExitEnabled: true,
+		maxConcurrency:   defaultMaxConcurrency,
 	}
 }
 
 // NewAnalysisPipelineWithCache erstellt eine Pipeline mit Metrics-Integration
 func NewAnalysisPipelineWithCache(metrics MetricsRecorder) *AnalysisPipeline {
+	stages := getDefaultStages()
+	if err := validateDAG(stages); err != nil {
+		panic(fmt.Sprintf("pipeline: invalid stage dependency graph: %v", err))
+	}
 	return &AnalysisPipeline{
-		stages:           getDefaultStages(),
+		stages:           stages,
 		cache:            getGlobalCache(),
 		metrics:          metrics,
 		earlyExitEnabled: true,
+		maxConcurrency:   defaultMaxConcurrency,
 	}
 }
 
+// NewAnalysisPipelineWithBackend erstellt eine Pipeline, die backend statt
+// des geteilten, über getGlobalCache() bezogenen Caches verwendet - für
+// Aufrufer, die einen eigenen cache.Backend (etwa ein cache.DiskBackend oder
+// cache.RedisBackend) pro Pipeline-Instanz statt global über
+// SetGlobalCacheBackend verdrahten wollen.
+func NewAnalysisPipelineWithBackend(backend cache.Backend, metrics MetricsRecorder) *AnalysisPipeline {
+	stages := getDefaultStages()
+	if err := validateDAG(stages); err != nil {
+		panic(fmt.Sprintf("pipeline: invalid stage dependency graph: %v", err))
+	}
+	return &AnalysisPipeline{
+		stages:           stages,
+		cache:            backend,
+		metrics:          metrics,
+		earlyExitEnabled: true,
+		maxConcurrency:   defaultMaxConcurrency,
+	}
+}
+
+// validateDAG prüft, dass stages als Dependency-Graph zyklenfrei ist (Kahns
+// Algorithmus) - Grundlage für runDependencyGraph, das sonst auf einen
+// Dependency-Zyklus mit einem für immer blockierten Worker-Pool reagieren
+// würde. Eine Dependency, die auf keine der übergebenen stages verweist (z.B.
+// weil sortedStagesForMIME sie für dieses Format herausgefiltert hat), gilt
+// als bereits erfüllt statt als Fehler - siehe runDependencyGraph.
+func validateDAG(stages []AnalysisStage) error {
+	known := make(map[string]bool, len(stages))
+	for _, s := range stages {
+		if known[s.Name] {
+			return fmt.Errorf("duplicate stage name %q", s.Name)
+		}
+		known[s.Name] = true
+	}
+
+	remaining := make(map[string]int, len(stages))
+	dependents := make(map[string][]string)
+	for _, s := range stages {
+		count := 0
+		for _, dep := range s.Dependencies {
+			if !known[dep] {
+				continue
+			}
+			count++
+			dependents[dep] = append(dependents[dep], s.Name)
+		}
+		remaining[s.Name] = count
+	}
+
+	queue := make([]string, 0, len(stages))
+	for name, count := range remaining {
+		if count == 0 {
+			queue = append(queue, name)
+		}
+	}
+
+	visited := 0
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, dependent := range dependents[name] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	if visited != len(stages) {
+		return fmt.Errorf("dependency cycle detected among stages")
+	}
+	return nil
+}
+
+// SetMaxConcurrency begrenzt, wie viele Stages runDependencyGraph gleichzeitig
+// startet (siehe defaultMaxConcurrency). n < 1 wird auf 1 angehoben statt
+// einen deadlocken Worker-Pool zu erzeugen.
+func (ap *AnalysisPipeline) SetMaxConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+	ap.maxConcurrency = n
+}
+
+func (ap *AnalysisPipeline) getMaxConcurrency() int {
+	ap.mu.RLock()
+	defer ap.mu.RUnlock()
+	if ap.maxConcurrency < 1 {
+		return defaultMaxConcurrency
+	}
+	return ap.maxConcurrency
+}
+
 // getDefaultStages definiert alle verfügbaren Analysestufen
 func getDefaultStages() []AnalysisStage {
 	return []AnalysisStage{
 		// Priorität 1: Schnelle, definitive Checks
 		{
-			Name:         "metadata-quick",
-			Priority:     1,
-			FastTrack:    true,
-			Timeout:      5 * time.Second,
-			Dependencies: []string{},
-			Analyzer:     pythonrunner.RunMetadata,
+			Name:           "metadata-quick",
+			Priority:       1,
+			FastTrack:      true,
+			Timeout:        5 * time.Second,
+			Dependencies:   []string{},
+			Analyzer:       withoutDeps(pythonrunner.RunMetadata),
+			ScoreExtractor: extractMetadataScore,
 		},
 		{
-			Name:         "c2pa",
-			Priority:     1,
-			FastTrack:    true,
-			Timeout:      8 * time.Second,
-			Dependencies: []string{},
-			Analyzer:     rustrunner.RunC2PA,
+			Name:           "c2pa",
+			Priority:       1,
+			FastTrack:      true,
+			Timeout:        8 * time.Second,
+			Dependencies:   []string{},
+			Analyzer:       withoutDeps(rustrunner.RunC2PA),
+			ScoreExtractor: extractC2PAScore,
 		},
 		{
 			Name:      "exif",
 			Priority:  1,
 			FastTrack: true,
 			Timeout:   2 * time.Second,
-			Analyzer: func(ctx context.Context, p string) (interface{}, error) {
+			Analyzer: withoutDeps(func(ctx context.Context, p string) (interface{}, error) {
 				return exifanalyzer.AnalyzeEXIF(p)
-			},
+			}),
 		},
 
 		// Priorität 2: Wichtige technische Analysen
@@ -120,76 +491,61 @@ func getDefaultStages() []AnalysisStage {
 			FastTrack:    false,
 			Timeout:      8 * time.Second,
 			Dependencies: []string{},
-			Analyzer:     pythonrunner.RunMetadata,
-		},
-
-		// Priorität 3: Spezialisierte Bildanalysen
-		{
-			Name:         "artifacts",
-			Priority:     3,
-			FastTrack:    false,
-			Timeout:      15 * time.Second,
-			Dependencies: []string{},
-			Analyzer:     pythonrunner.RunArtifacts,
-		},
-		{
-			Name:         "compression",
-			Priority:     3,
-			FastTrack:    false,
-			Timeout:      10 * time.Second,
-			Dependencies: []string{},
-			Analyzer:     pythonrunner.RunCompression,
-		},
-		{
-			Name:         "pixel-analysis",
-			Priority:     3,
-			FastTrack:    false,
-			Timeout:      18 * time.Second,
-			Dependencies: []string{},
-			Analyzer:     pythonrunner.RunPixelAnalysis,
-		},
-		{
-			Name:         "color-balance",
-			Priority:     3,
-			FastTrack:    false,
-			Timeout:      12 * time.Second,
-			Dependencies: []string{},
-			Analyzer:     pythonrunner.RunColorBalance,
+			Analyzer:     withoutDeps(pythonrunner.RunMetadata),
+			ScoreExtractor: extractMetadataScore,
 		},
 		{
-			Name:         "advanced-artifacts",
-			Priority:     3,
+			Name:         "metadata-structured",
+			Priority:     2,
 			FastTrack:    false,
-			Timeout:      20 * time.Second,
+			Timeout:      5 * time.Second,
 			Dependencies: []string{},
-			Analyzer:     pythonrunner.RunAdvancedArtifacts,
+			Analyzer: withoutDeps(func(ctx context.Context, p string) (interface{}, error) {
+				report, err := exifanalyzer.AnalyzeStructuredMetadata(p)
+				if err != nil {
+					return nil, err
+				}
+				return report.ToResultMap()
+			}),
+			ScoreExtractor: extractMetadataScore,
 		},
 
+		// Priorität 3: Spezialisierte Bildanalysen
+		pythonStage("artifacts", 3),
+		pythonStage("compression", 3),
+		pythonStage("pixel-analysis", 3),
+		pythonStage("color-balance", 3),
+		pythonStage("advanced-artifacts", 3, "artifacts"),
+
 		// Priorität 4: Neue visuelle Inhaltanalysen
-		{
-			Name:         "object-coherence",
-			Priority:     4,
-			FastTrack:    false,
-			Timeout:      25 * time.Second,
-			Dependencies: []string{},
-			Analyzer:     pythonrunner.RunObjectCoherence,
-		},
-		{
-			Name:         "lighting-analysis",
-			Priority:     4,
-			FastTrack:    false,
-			Timeout:      20 * time.Second,
-			Dependencies: []string{},
-			Analyzer:     pythonrunner.RunLightingAnalysis,
-		},
-		{
-			Name:         "ai-model",
-			Priority:     2,
-			FastTrack:    false,
-			Timeout:      30 * time.Second,
-			Dependencies: []string{},
-			Analyzer:     pythonrunner.RunAIModelPrediction,
-		},
+		pythonStage("object-coherence", 4),
+		pythonStage("lighting-analysis", 4),
+		pythonStage("ai-model", 2),
+	}
+}
+
+// pythonStage baut die AnalysisStage für einen in der pythonrunner-Registry
+// (siehe pkg/pythonrunner/registry.go) hinterlegten Analyzer - Analyzer und
+// Timeout kommen aus der Registry, sodass ein neuer Python-Analyzer dort per
+// YAML-Eintrag registriert wird, statt zusätzlich hier eine neue
+// AnalysisStage-Literal zu brauchen. Das hier gesetzte Timeout ist der von
+// runStage bereits angewendete per-Stage-Timeout (siehe dort) - die Registry
+// selbst erzwingt via pythonrunner.Run denselben Wert zusätzlich als
+// worker-internes Timeout, unabhängig von ctx.
+func pythonStage(name string, priority int, dependencies ...string) AnalysisStage {
+	timeout, ok := pythonrunner.TimeoutFor(name)
+	if !ok {
+		timeout = 30 * time.Second
+	}
+	return AnalysisStage{
+		Name:              name,
+		Priority:          priority,
+		FastTrack:         false,
+		Timeout:           timeout,
+		Dependencies:      dependencies,
+		Analyzer:          pythonrunner.AnalyzerFunc(name),
+		StreamingAnalyzer: pythonrunner.StreamingAnalyzerFunc(name),
+		ScoreExtractor:    scoreExtractorForStage(name),
 	}
 }
 
@@ -212,6 +568,96 @@ func (ap *AnalysisPipeline) ExtractConfidenceScore(data interface{}) float64 {
 	return ap.extractConfidenceScore(data)
 }
 
+// RunAnalysisStream führt dieselben Stages wie RunAnalysis aus, meldet aber
+// den Abschluss (bzw. bei python-gestützten Stages auch den
+// Zwischenfortschritt, siehe AnalysisStage.StreamingAnalyzer) jeder Stage
+// sofort über den zurückgegebenen Channel, statt erst am Ende ein einziges
+// *PipelineResult zu liefern - für Clients, die während der bis zu zwei
+// Minuten dauernden Analyse eine Live-Fortschrittsanzeige zeigen wollen
+// (siehe internal/handlers für den SSE-Endpunkt GET /analyze/stream).
+//
+// Anders als RunAnalysis nutzt RunAnalysisStream weder den Analysis-Cache
+// noch die FastTrack/Early-Exit-Phase: beides optimiert auf möglichst
+// geringe Latenz bis zur Antwort, während RunAnalysisStream gerade auf
+// Sichtbarkeit jeder einzelnen Stage ausgelegt ist. Der zurückgegebene
+// Channel wird geschlossen, nachdem das abschließende "done"-Event gesendet
+// wurde; ein Fehler wird nur zurückgegeben, wenn die Analyse nicht einmal
+// gestartet werden konnte (z.B. MIME-Erkennung fehlgeschlagen).
+func (ap *AnalysisPipeline) RunAnalysisStream(ctx context.Context, imagePath string) (<-chan StageEvent, error) {
+	mimeType, err := DetectMIME(imagePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect MIME type: %w", err)
+	}
+
+	stages := ap.sortedStagesForMIME(mimeType)
+	events := make(chan StageEvent, 16)
+
+	go func() {
+		defer close(events)
+
+		pipelineStart := time.Now()
+		results := make(map[string]interface{})
+		stagesRun := make([]string, 0, len(stages))
+
+		for _, stage := range stages {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			stageStart := time.Now()
+			stageCtx, cancel := context.WithTimeout(ctx, stage.Timeout)
+
+			var stageResult interface{}
+			var stageErr error
+			if stage.StreamingAnalyzer != nil {
+				stageResult, stageErr = stage.StreamingAnalyzer(stageCtx, imagePath, func(partial interface{}) {
+					events <- StageEvent{Stage: stage.Name, Status: "progress", PartialResult: partial, Elapsed: time.Since(stageStart)}
+				})
+			} else {
+				stageResult, stageErr = stage.Analyzer(stageCtx, imagePath, results)
+			}
+			cancel()
+
+			if stageErr != nil {
+				if ap.metrics != nil {
+					ap.metrics.RecordDetectorResult(stage.Name, stageErr)
+					ap.metrics.RecordStageDuration(stage.Name, time.Since(stageStart), stageErr)
+				}
+				events <- StageEvent{Stage: stage.Name, Status: "failed", PartialResult: stageErr.Error(), Elapsed: time.Since(stageStart)}
+				continue
+			}
+
+			if ap.metrics != nil {
+				ap.metrics.RecordDetectorResult(stage.Name, nil)
+				ap.metrics.RecordStageDuration(stage.Name, time.Since(stageStart), nil)
+			}
+			results[stage.Name] = stageResult
+			stagesRun = append(stagesRun, stage.Name)
+			events <- StageEvent{Stage: stage.Name, Status: "completed", PartialResult: stageResult, Elapsed: time.Since(stageStart)}
+		}
+
+		confidence, contributions := ap.calculateFinalConfidence(results, stages)
+		if ap.metrics != nil {
+			for name, score := range contributions {
+				ap.metrics.RecordStageScore(name, score)
+			}
+		}
+		final := &PipelineResult{
+			Results:       results,
+			StagesRun:     stagesRun,
+			MimeType:      mimeType,
+			ProcessTime:   time.Since(pipelineStart),
+			Confidence:    confidence,
+			Contributions: contributions,
+		}
+		events <- StageEvent{Status: "done", PartialResult: final, Elapsed: time.Since(pipelineStart)}
+	}()
+
+	return events, nil
+}
+
 func (ap *AnalysisPipeline) RunAnalysis(ctx context.Context, imagePath string) (*PipelineResult, error) {
 	startTime := time.Now()
 	logger := slog.With("image_path", imagePath)
@@ -224,43 +670,61 @@ func (ap *AnalysisPipeline) RunAnalysis(ctx context.Context, imagePath string) (
 
 	logger = logger.With("cache_key", cacheKey[:16])
 
+	// noCache (siehe WithNoCache) überspringt sowohl das Lesen als auch das
+	// spätere Schreiben - für einen erzwungenen Re-Run (?nocache=1), ohne
+	// DetectorVersion hochzuzählen.
+	noCache := noCacheFromContext(ctx)
+
 	// DEBUG: Cache-Status prüfen
 	logger.Info("DEBUG: Checking cache",
 		"cache_enabled", ap.cache != nil,
+		"no_cache", noCache,
 		"cache_key", cacheKey[:16])
 
-	// Cache prüfen
-	if cachedResult, found := ap.cache.Get(cacheKey); found {
-		if ap.metrics != nil {
-			ap.metrics.RecordCacheHit()
-		}
+	if !noCache {
+		// Cache prüfen
+		if cachedResult, found := ap.cache.Get(cacheKey); found {
+			if ap.metrics != nil {
+				ap.metrics.RecordCacheHit()
+			}
 
-		if result, ok := cachedResult.(*PipelineResult); ok {
-			cachedCopy := *result
-			cachedCopy.CacheHit = true
-			cachedCopy.ProcessTime = time.Since(startTime)
+			if result, ok := cachedResult.(*PipelineResult); ok {
+				cachedCopy := *result
+				cachedCopy.CacheHit = true
+				cachedCopy.ProcessTime = time.Since(startTime)
 
-			logger.Info("Cache HIT - returning cached result",
-				"original_duration", result.ProcessTime,
-				"cache_lookup_time", time.Since(startTime))
+				if ap.metrics != nil {
+					ap.metrics.RecordPipelineDuration(cachedCopy.ProcessTime, true)
+				}
+
+				logger.Info("Cache HIT - returning cached result",
+					"original_duration", result.ProcessTime,
+					"cache_lookup_time", time.Since(startTime))
 
-			return &cachedCopy, nil
+				return &cachedCopy, nil
+			} else {
+				logger.Warn("Cache entry found but wrong type", "type", fmt.Sprintf("%T", cachedResult))
+			}
 		} else {
-			logger.Warn("Cache entry found but wrong type", "type", fmt.Sprintf("%T", cachedResult))
+			logger.Info("Cache entry not found or expired")
 		}
-	} else {
-		logger.Info("Cache entry not found or expired")
-	}
 
-	// Cache Miss - Record it
-	if ap.metrics != nil {
-		ap.metrics.RecordCacheMiss()
+		// Cache Miss - Record it
+		if ap.metrics != nil {
+			ap.metrics.RecordCacheMiss()
+		}
 	}
 
 	logger.Info("Cache MISS - running full analysis")
 
+	mimeType, mimeErr := DetectMIME(imagePath)
+	if mimeErr != nil {
+		logger.Warn("failed to detect MIME type, running full stage set", "error", mimeErr)
+		mimeType = ""
+	}
+
 	// Rest bleibt gleich...
-	result, err := ap.runFullAnalysis(ctx, imagePath, logger)
+	result, err := ap.runFullAnalysis(ctx, imagePath, mimeType, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -268,6 +732,17 @@ func (ap *AnalysisPipeline) RunAnalysis(ctx context.Context, imagePath string) (
 	result.CacheHit = false
 	result.ProcessTime = time.Since(startTime)
 
+	if ap.metrics != nil {
+		ap.metrics.RecordPipelineDuration(result.ProcessTime, false)
+	}
+
+	if noCache {
+		logger.Info("Analysis completed without touching cache (no_cache)",
+			"duration", result.ProcessTime,
+			"stages_completed", len(result.StagesRun))
+		return result, nil
+	}
+
 	// DEBUG: Cache speichern
 	logger.Info("DEBUG: Storing in cache",
 		"cache_key", cacheKey[:16],
@@ -297,92 +772,94 @@ func (ap *AnalysisPipeline) generateCacheKey(imagePath string) (string, error) {
 
 	// Cache-Key nur basierend auf Inhalt, nicht auf Pfad
 	hashStr := fmt.Sprintf("%x", hash.Sum(nil))
-	return fmt.Sprintf("analysis_%s", hashStr), nil
+	return cacheKeyForHash(hashStr), nil
 }
 
 // runFullAnalysis führt die tatsächliche Analyse ohne Cache aus
-func (ap *AnalysisPipeline) runFullAnalysis(ctx context.Context, imagePath string, logger *slog.Logger) (*PipelineResult, error) {
+func (ap *AnalysisPipeline) runFullAnalysis(ctx context.Context, imagePath string, mimeType string, logger *slog.Logger) (*PipelineResult, error) {
 	result := &PipelineResult{
-		Results:   make(map[string]interface{}),
-		StagesRun: []string{},
-		EarlyExit: false,
+		Results:      make(map[string]interface{}),
+		StagesRun:    []string{},
+		EarlyExit:    false,
+		StageMetrics: make(map[string]StageMetric),
+		MimeType:     mimeType,
 	}
 
-	// Sortiere Stages nach Priorität
-	stages := ap.getSortedStages()
+	// Sortiere Stages nach Priorität und beschränke sie auf das für
+	// mimeType in Registry konfigurierte FormatProfile - z.B. läuft
+	// "compression" nicht auf einem PNG.
+	stages := ap.sortedStagesForMIME(mimeType)
 
 	// Phase 1: FastTrack Stages (für Early Exit)
 	if ap.IsEarlyExitEnabled() {
-		fastTrackResults := ap.runFastTrackStages(ctx, imagePath, stages, logger)
+		fastTrackResults, fastTrackMetrics := ap.runFastTrackStages(ctx, imagePath, stages, logger)
 
 		// Merge FastTrack Ergebnisse
 		for name, data := range fastTrackResults {
 			result.Results[name] = data
 			result.StagesRun = append(result.StagesRun, name)
 		}
+		for name, metric := range fastTrackMetrics {
+			result.StageMetrics[name] = metric
+		}
 
 		// Prüfe Early Exit Bedingungen
-		if ap.shouldEarlyExit(result.Results) {
+		if exit, reason := ap.shouldEarlyExit(result.Results); exit {
 			result.EarlyExit = true
-			result.Confidence = ap.calculateEarlyConfidence(result.Results)
+			result.Confidence, result.Contributions = ap.calculateEarlyConfidence(result.Results, stages)
+
+			if ap.metrics != nil {
+				ap.metrics.RecordEarlyExit(reason)
+				for name, score := range result.Contributions {
+					ap.metrics.RecordStageScore(name, score)
+				}
+			}
 
 			logger.Info("Early exit triggered",
 				"stages_run", len(result.StagesRun),
+				"reason", reason,
 				"confidence", result.Confidence)
 			return result, nil
 		}
 	}
 
-	// Phase 2: Vollständige Analyse
+	// Phase 2: Vollständige Analyse - verbleibende Stages laufen als
+	// Dependency-Graph (siehe runDependencyGraph), nicht mehr streng
+	// seriell nach Priorität.
 	logger.Info("Running full analysis pipeline")
 
+	remaining := make([]AnalysisStage, 0, len(stages))
 	for _, stage := range stages {
 		// Skip bereits ausgeführte FastTrack Stages
 		if stage.FastTrack && result.Results[stage.Name] != nil {
 			continue
 		}
+		remaining = append(remaining, stage)
+	}
 
-		// Context mit Stage-spezifischem Timeout
-		stageCtx, cancel := context.WithTimeout(ctx, stage.Timeout)
-
-		logger.Info("Running stage", "stage", stage.Name, "timeout", stage.Timeout)
-		stageStart := time.Now()
-
-		stageResult, err := stage.Analyzer(stageCtx, imagePath)
-		stageDuration := time.Since(stageStart)
-
-		cancel() // Cleanup
-
-		if err != nil {
-			logger.Warn("Stage failed",
-				"stage", stage.Name,
-				"error", err,
-				"duration", stageDuration)
-
-			// Nicht-kritische Fehler: Weiter
-			continue
-		}
+	graphResults, graphMetrics, graphStagesRun, err := ap.runDependencyGraph(ctx, imagePath, remaining, result.Results, logger)
+	for name, data := range graphResults {
+		result.Results[name] = data
+	}
+	for name, metric := range graphMetrics {
+		result.StageMetrics[name] = metric
+	}
+	result.StagesRun = append(result.StagesRun, graphStagesRun...)
 
-		result.Results[stage.Name] = stageResult
-		result.StagesRun = append(result.StagesRun, stage.Name)
+	if err != nil {
+		logger.Warn("Pipeline cancelled", "completed_stages", len(result.StagesRun))
+		return nil, err
+	}
 
-		logger.Info("Stage completed",
-			"stage", stage.Name,
-			"duration", stageDuration)
+	// Finale Berechnung
+	result.Confidence, result.Contributions = ap.calculateFinalConfidence(result.Results, stages)
 
-		// Prüfe Context Cancellation
-		select {
-		case <-ctx.Done():
-			logger.Warn("Pipeline cancelled", "completed_stages", len(result.StagesRun))
-			return nil, ctx.Err()
-		default:
-			// Continue
+	if ap.metrics != nil {
+		for name, score := range result.Contributions {
+			ap.metrics.RecordStageScore(name, score)
 		}
 	}
 
-	// Finale Berechnung
-	result.Confidence = ap.calculateFinalConfidence(result.Results)
-
 	logger.Info("Pipeline completed",
 		"total_stages", len(result.StagesRun),
 		"confidence", result.Confidence)
@@ -406,8 +883,37 @@ func (ap *AnalysisPipeline) getSortedStages() []AnalysisStage {
 	return stages
 }
 
-func (ap *AnalysisPipeline) runFastTrackStages(ctx context.Context, imagePath string, stages []AnalysisStage, logger *slog.Logger) map[string]interface{} {
+// sortedStagesForMIME liefert die nach Priorität sortierten Stages dieser
+// Pipeline-Instanz, eingeschränkt auf die in Registry[mimeType] gelisteten
+// Detektor-Namen. Ist mimeType leer oder in Registry unbekannt, werden alle
+// Stages der Instanz zurückgegeben (ap.stages ist per Konstruktor bereits
+// getDefaultStages, kann aber von Aufrufern angepasst worden sein, daher
+// wird hier gefiltert statt erneut StagesForMIME aufzurufen).
+func (ap *AnalysisPipeline) sortedStagesForMIME(mimeType string) []AnalysisStage {
+	stages := ap.getSortedStages()
+
+	profile, ok := Registry[mimeType]
+	if !ok {
+		return stages
+	}
+
+	allowed := make(map[string]bool, len(profile.Detectors))
+	for _, name := range profile.Detectors {
+		allowed[name] = true
+	}
+
+	filtered := make([]AnalysisStage, 0, len(stages))
+	for _, stage := range stages {
+		if allowed[stage.Name] {
+			filtered = append(filtered, stage)
+		}
+	}
+	return filtered
+}
+
+func (ap *AnalysisPipeline) runFastTrackStages(ctx context.Context, imagePath string, stages []AnalysisStage, logger *slog.Logger) (map[string]interface{}, map[string]StageMetric) {
 	results := make(map[string]interface{})
+	metrics := make(map[string]StageMetric)
 
 	for _, stage := range stages {
 		if !stage.FastTrack {
@@ -415,38 +921,201 @@ func (ap *AnalysisPipeline) runFastTrackStages(ctx context.Context, imagePath st
 		}
 
 		stageCtx, cancel := context.WithTimeout(ctx, stage.Timeout)
-		stageResult, err := stage.Analyzer(stageCtx, imagePath)
+		stageResult, err, metric := runStage(stageCtx, stage, imagePath, results)
 		cancel()
 
+		if ap.metrics != nil {
+			ap.metrics.RecordDetectorResult(stage.Name, err)
+			ap.metrics.RecordStageDuration(stage.Name, metric.Duration, err)
+		}
+
 		if err != nil {
 			logger.Warn("FastTrack stage failed", "stage", stage.Name, "error", err)
 			continue
 		}
 
 		results[stage.Name] = stageResult
-		logger.Info("FastTrack stage completed", "stage", stage.Name)
+		metrics[stage.Name] = metric
+		logger.Info("FastTrack stage completed", "stage", stage.Name, "duration", metric.Duration)
 	}
-	return results
+	return results, metrics
 }
 
-func (ap *AnalysisPipeline) shouldEarlyExit(results map[string]interface{}) bool {
+// runDependencyGraph führt stages als Dependency-Graph aus: eine Stage
+// startet, sobald alle in AnalysisStage.Dependencies gelisteten Stages (die
+// überhaupt Teil von stages sind - eine Dependency außerhalb davon, z.B. eine
+// von sortedStagesForMIME herausgefilterte Stage, gilt als bereits erfüllt)
+// abgeschlossen sind, statt wie zuvor strikt seriell nach Priorität zu
+// laufen. Parallel laufen dabei höchstens ap.getMaxConcurrency() Stages
+// gleichzeitig. seedResults sind bereits vorliegende Ergebnisse (aus Phase 1,
+// FastTrack) - sie zählen für die Dependency-Auflösung als erfüllt und werden
+// als deps an jede Stage weitergereicht, die sie braucht, laufen selbst aber
+// nicht erneut.
+//
+// Ein ctx.Err() nach Abbruch wird wie zuvor an den Aufrufer zurückgegeben;
+// bereits gestartete Stages dürfen dabei noch fertig laufen (ihr per-Stage
+// Timeout-Context ist über ctx bereits transitiv mit-abgebrochen), es werden
+// nur keine neuen Stages mehr gestartet.
+func (ap *AnalysisPipeline) runDependencyGraph(ctx context.Context, imagePath string, stages []AnalysisStage, seedResults map[string]interface{}, logger *slog.Logger) (map[string]interface{}, map[string]StageMetric, []string, error) {
+	results := make(map[string]interface{})
+	metrics := make(map[string]StageMetric)
+	stagesRun := make([]string, 0, len(stages))
+
+	if len(stages) == 0 {
+		return results, metrics, stagesRun, nil
+	}
+
+	byName := make(map[string]AnalysisStage, len(stages))
+	for _, stage := range stages {
+		byName[stage.Name] = stage
+	}
+
+	// remaining/dependents: eine Dependency, die nicht unter stages läuft
+	// (weil sie schon in seedResults vorliegt, z.B. eine FastTrack-Stage,
+	// oder weil sortedStagesForMIME sie für dieses Format gar nicht erst
+	// vorsieht), blockiert den Start der abhängigen Stage nicht.
+	remaining := make(map[string]int, len(stages))
+	dependents := make(map[string][]string)
+	readyQueue := make([]string, 0, len(stages))
+	for _, stage := range stages {
+		count := 0
+		for _, dep := range stage.Dependencies {
+			if _, isPending := byName[dep]; !isPending {
+				continue
+			}
+			count++
+			dependents[dep] = append(dependents[dep], stage.Name)
+		}
+		remaining[stage.Name] = count
+		if count == 0 {
+			readyQueue = append(readyQueue, stage.Name)
+		}
+	}
+
+	type completion struct {
+		name   string
+		result interface{}
+		err    error
+		metric StageMetric
+	}
+
+	maxConcurrency := ap.getMaxConcurrency()
+	completions := make(chan completion, len(stages))
+	inFlight := 0
+	pending := len(stages)
+	cancelled := false
+	var cancelErr error
+
+	snapshot := func() map[string]interface{} {
+		merged := make(map[string]interface{}, len(seedResults)+len(results))
+		for k, v := range seedResults {
+			merged[k] = v
+		}
+		for k, v := range results {
+			merged[k] = v
+		}
+		return merged
+	}
+
+	launch := func(name string) {
+		stage := byName[name]
+		deps := snapshot()
+		inFlight++
+		go func() {
+			stageCtx, cancel := context.WithTimeout(ctx, stage.Timeout)
+			logger.Info("Running stage", "stage", stage.Name, "timeout", stage.Timeout)
+			result, err, metric := runStage(stageCtx, stage, imagePath, deps)
+			cancel()
+			completions <- completion{name: stage.Name, result: result, err: err, metric: metric}
+		}()
+	}
+
+	for pending > 0 {
+		for !cancelled && len(readyQueue) > 0 && inFlight < maxConcurrency {
+			name := readyQueue[0]
+			readyQueue = readyQueue[1:]
+			launch(name)
+		}
+
+		if inFlight == 0 {
+			// Kein Fortschritt mehr möglich: entweder abgebrochen und alle
+			// gestarteten Stages sind bereits fertig, oder (sollte wegen
+			// validateDAG nicht vorkommen) ein Rest-Zyklus.
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			if !cancelled {
+				cancelled = true
+				cancelErr = ctx.Err()
+			}
+			c := <-completions
+			inFlight--
+			pending--
+			ap.recordStageCompletion(c.name, c.result, c.err, c.metric, logger, results, metrics, &stagesRun, remaining, dependents, &readyQueue)
+		case c := <-completions:
+			inFlight--
+			pending--
+			ap.recordStageCompletion(c.name, c.result, c.err, c.metric, logger, results, metrics, &stagesRun, remaining, dependents, &readyQueue)
+		}
+	}
+
+	if cancelled {
+		return results, metrics, stagesRun, cancelErr
+	}
+	return results, metrics, stagesRun, nil
+}
+
+// recordStageCompletion verbucht das Ergebnis einer einzelnen, von
+// runDependencyGraph gestarteten Stage: Metrics-Recording, Merge in
+// results/metrics/stagesRun bei Erfolg, und das Herabzählen von remaining
+// für alle von ihr abhängigen Stages, die dadurch neu bereit werden.
+func (ap *AnalysisPipeline) recordStageCompletion(name string, result interface{}, err error, metric StageMetric, logger *slog.Logger, results map[string]interface{}, metrics map[string]StageMetric, stagesRun *[]string, remaining map[string]int, dependents map[string][]string, readyQueue *[]string) {
+	if ap.metrics != nil {
+		ap.metrics.RecordDetectorResult(name, err)
+		ap.metrics.RecordStageDuration(name, metric.Duration, err)
+	}
+
+	if err != nil {
+		logger.Warn("Stage failed", "stage", name, "error", err, "duration", metric.Duration)
+	} else {
+		results[name] = result
+		metrics[name] = metric
+		*stagesRun = append(*stagesRun, name)
+		logger.Info("Stage completed", "stage", name, "duration", metric.Duration, "alloc_bytes", metric.AllocBytes)
+	}
+
+	for _, dependent := range dependents[name] {
+		remaining[dependent]--
+		if remaining[dependent] == 0 {
+			*readyQueue = append(*readyQueue, dependent)
+		}
+	}
+}
+
+// shouldEarlyExit liefert neben dem Entscheid auch die Stage, die ihn
+// ausgelöst hat ("metadata-quick" oder "c2pa") - als reason an
+// MetricsRecorder.RecordEarlyExit gereicht, damit sich im Prometheus-Export
+// unterscheiden lässt, worüber Early Exits überwiegend laufen.
+func (ap *AnalysisPipeline) shouldEarlyExit(results map[string]interface{}) (bool, string) {
 	if !ap.IsEarlyExitEnabled() {
-		return false
+		return false, ""
 	}
 
 	if metaResult, exists := results["metadata-quick"]; exists {
 		if ap.hasDefinitiveMetadataEvidence(metaResult) {
-			return true
+			return true, "metadata-quick"
 		}
 	}
 
 	if c2paResult, exists := results["c2pa"]; exists {
 		if ap.hasDefinitiveC2PAEvidence(c2paResult) {
-			return true
+			return true, "c2pa"
 		}
 	}
 
-	return false
+	return false, ""
 }
 
 func (ap *AnalysisPipeline) hasDefinitiveMetadataEvidence(data interface{}) bool {
@@ -467,136 +1136,328 @@ func (ap *AnalysisPipeline) hasDefinitiveC2PAEvidence(data interface{}) bool {
 	return false
 }
 
-func (ap *AnalysisPipeline) calculateEarlyConfidence(results map[string]interface{}) float64 {
-	return 0.98 // Hohe Konfidenz bei Early Exit
+// calculateEarlyConfidence fusioniert die Scores der bei Early Exit bereits
+// gelaufenen FastTrack-Stages per combineStageConfidence statt der zuvor
+// konstanten 0.98 - das Ergebnis hängt jetzt tatsächlich davon ab, wie
+// eindeutig metadata-quick/c2pa ausgefallen sind (shouldEarlyExit hat bereits
+// geprüft, dass mindestens einer von beiden definitiv war).
+func (ap *AnalysisPipeline) calculateEarlyConfidence(results map[string]interface{}, stages []AnalysisStage) (float64, map[string]float64) {
+	return combineStageConfidence(results, stages)
 }
 
-func (ap *AnalysisPipeline) calculateFinalConfidence(results map[string]interface{}) float64 {
-	totalStages := len(ap.stages)
-	completedStages := len(results)
+// calculateFinalConfidence fusioniert die kalibrierten Scores aller Stages,
+// für die ein ScoreExtractor ein Ergebnis liefert, per Log-Odds-Addition
+// (siehe combineStageConfidence) statt der zuvor rein strukturellen
+// Stage-Count-Ratio - eine Stage ohne verwertbaren Score (ScoreExtractor
+// liefert -1 oder ist nil, z.B. exif) zählt für die Confidence nicht mit,
+// lief aber dennoch und bleibt in StagesRun.
+func (ap *AnalysisPipeline) calculateFinalConfidence(results map[string]interface{}, stages []AnalysisStage) (float64, map[string]float64) {
+	return combineStageConfidence(results, stages)
+}
 
-	completionRatio := float64(completedStages) / float64(totalStages)
-	return 0.5 + (completionRatio * 0.4) // 0.5 - 0.9
+// stageWeight ist das Zuverlässigkeitsgewicht, mit dem eine Stage in
+// combineStageConfidence einfließt - getrennt von pkg/analyzer/fusion, dessen
+// Gewichte über config/fusion_weights.json gegen die vollständig kalibrierten
+// Detektor-Scores in internal/handlers/verdict gefittet werden. Diese Tabelle
+// ist die Default-Gewichtung für die schnelle, Pipeline-interne
+// Vorab-Confidence, die schon vor einer eventuellen vollen Verdict-Berechnung
+// vorliegt - c2pa/ai-model sind die informativsten Einzelsignale, color-balance/
+// lighting-analysis die am leichtesten durch normale Foto-Bearbeitung
+// verfälschten. Eine Stage ohne Eintrag bekommt das neutrale Gewicht 1.0.
+var stageWeight = map[string]float64{
+	"c2pa":                1.6,
+	"ai-model":            1.6,
+	"metadata-quick":      1.3,
+	"metadata":            1.1,
+	"metadata-structured": 1.1,
+	"advanced-artifacts":  1.1,
+	"artifacts":           1.0,
+	"compression":         0.9,
+	"pixel-analysis":      0.9,
+	"object-coherence":    0.8,
+	"color-balance":       0.5,
+	"lighting-analysis":   0.5,
 }
 
-func (ap *AnalysisPipeline) extractConfidenceScore(data interface{}) float64 {
+func weightForStage(name string) float64 {
+	if w, exists := stageWeight[name]; exists {
+		return w
+	}
+	return 1.0
+}
+
+const (
+	confidenceLogitClamp = 8.0
+	confidencePrior      = 0.5
+)
+
+// combineStageConfidence liest für jede Stage in stages mit einem Ergebnis in
+// results per ScoreExtractor deren kalibrierte AI-Wahrscheinlichkeit aus und
+// kombiniert sie per naive-Bayes-artiger Log-Odds-Addition, gewichtet über
+// weightForStage - analog zu fusion.CombineLogOdds, aber eigenständig, da
+// diese Pipeline-interne Confidence auf den rohen ScoreExtractor-Werten
+// arbeitet statt auf den in internal/handlers/verdict kalibrierten Scores.
+// Liefert zusätzlich die Logit-Beiträge pro Stage (siehe
+// PipelineResult.Contributions), auf 0.5 geklammert ohne eine einzige
+// Stage mit Score, damit eine Analyse ganz ohne verwertbares Signal nicht als
+// falsch-zuversichtlich erscheint.
+func combineStageConfidence(results map[string]interface{}, stages []AnalysisStage) (float64, map[string]float64) {
+	logitPrior := clampConfidenceLogit(confidenceLogit(confidencePrior))
+	l := logitPrior
+	contributions := make(map[string]float64)
+
+	for _, stage := range stages {
+		if stage.ScoreExtractor == nil {
+			continue
+		}
+		data, exists := results[stage.Name]
+		if !exists {
+			continue
+		}
+
+		score := stage.ScoreExtractor(data)
+		if score < 0 {
+			continue
+		}
+
+		li := clampConfidenceLogit(confidenceLogit(score))
+		contribution := weightForStage(stage.Name) * (li - logitPrior)
+		contributions[stage.Name] = contribution
+		l += contribution
+	}
+
+	if len(contributions) == 0 {
+		return confidencePrior, contributions
+	}
+	return confidenceSigmoid(l), contributions
+}
+
+func confidenceLogit(p float64) float64 {
+	const eps = 1e-6
+	if p < eps {
+		p = eps
+	} else if p > 1-eps {
+		p = 1 - eps
+	}
+	return math.Log(p / (1 - p))
+}
+
+func clampConfidenceLogit(l float64) float64 {
+	if l > confidenceLogitClamp {
+		return confidenceLogitClamp
+	}
+	if l < -confidenceLogitClamp {
+		return -confidenceLogitClamp
+	}
+	return l
+}
+
+func confidenceSigmoid(x float64) float64 {
+	return 1.0 / (1.0 + math.Exp(-x))
+}
+
+// stageScoreExtractors ordnet Stage-Namen ihre ScoreExtractor-Funktion zu -
+// vormals ein einziger if/else-Block in extractConfidenceScore, jetzt pro
+// Stage registriert, damit jede Extractor-Funktion nur für die Ergebnisform
+// ihrer eigenen Stage zuständig ist. getDefaultStages/pythonStage verdrahten
+// das passende AnalysisStage.ScoreExtractor über diese Tabelle.
+var stageScoreExtractors = map[string]func(interface{}) float64{
+	"color-balance":       extractColorBalanceScore,
+	"advanced-artifacts":  extractAdvancedArtifactsScore,
+	"compression":         extractCompressionScore,
+	"artifacts":           extractOverallAssessmentScore,
+	"pixel-analysis":      extractOverallAssessmentScore,
+	"c2pa":                extractC2PAScore,
+	"lighting-analysis":   extractLightingScore,
+	"object-coherence":    extractObjectCoherenceScore,
+	"ai-model":            extractPredictionScore,
+	"metadata":            extractMetadataScore,
+	"metadata-quick":      extractMetadataScore,
+	"metadata-structured": extractMetadataScore,
+}
+
+func scoreExtractorForStage(name string) func(interface{}) float64 {
+	return stageScoreExtractors[name] // nil, wenn die Stage kein Score-Feld beisteuert (z.B. exif)
+}
+
+func asDataMap(data interface{}) (map[string]interface{}, bool) {
 	dataMap, ok := data.(map[string]interface{})
+	return dataMap, ok
+}
+
+func extractColorBalanceScore(data interface{}) float64 {
+	dataMap, ok := asDataMap(data)
 	if !ok {
 		return -1
 	}
+	if score, ok := dataMap["ai_color_score"].(float64); ok {
+		return score
+	}
+	return -1
+}
 
-	// Color-Balance Score
-	if aiColorScore, exists := dataMap["ai_color_score"]; exists {
-		if score, ok := aiColorScore.(float64); ok {
-			return score
+func extractAdvancedArtifactsScore(data interface{}) float64 {
+	dataMap, ok := asDataMap(data)
+	if !ok {
+		return -1
+	}
+	if assessment, ok := dataMap["advanced_assessment"].(map[string]interface{}); ok {
+		if probability, ok := assessment["advanced_ai_probability"].(float64); ok {
+			return probability
 		}
 	}
+	return -1
+}
 
-	// Advanced Artifacts Score
-	if advanced, exists := dataMap["advanced_assessment"]; exists {
-		if assessment, ok := advanced.(map[string]interface{}); ok {
-			if prob, exists := assessment["advanced_ai_probability"]; exists {
-				if probability, ok := prob.(float64); ok {
-					return probability
-				}
-			}
-		}
+// extractCompressionScore liest compression_ai_analysis.ai_probability - bei
+// einer Multi-File-Antwort (siehe Batch-Upload) liegt dasselbe Feld statt auf
+// oberster Ebene unter einem Dateinamen-Key verschachtelt, daher der
+// zusätzliche Fallback über alle Top-Level-Werte.
+func extractCompressionScore(data interface{}) float64 {
+	dataMap, ok := asDataMap(data)
+	if !ok {
+		return -1
 	}
 
-	// Compression Analysis Score
-	if compressionAnalysis, exists := dataMap["compression_ai_analysis"]; exists {
-		if analysis, ok := compressionAnalysis.(map[string]interface{}); ok {
-			if prob, exists := analysis["ai_probability"]; exists {
-				if probFloat, ok := prob.(float64); ok {
-					return probFloat
-				}
-			}
+	if analysis, ok := dataMap["compression_ai_analysis"].(map[string]interface{}); ok {
+		if prob, ok := analysis["ai_probability"].(float64); ok {
+			return prob
 		}
 	}
 
-	// Für nested compression data
 	for _, value := range dataMap {
-		if fileData, ok := value.(map[string]interface{}); ok {
-			if analysis, exists := fileData["compression_ai_analysis"]; exists {
-				if analysisMap, ok := analysis.(map[string]interface{}); ok {
-					if prob, exists := analysisMap["ai_probability"]; exists {
-						if probFloat, ok := prob.(float64); ok {
-							return probFloat
-						}
-					}
-				}
+		fileData, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if analysis, ok := fileData["compression_ai_analysis"].(map[string]interface{}); ok {
+			if prob, ok := analysis["ai_probability"].(float64); ok {
+				return prob
 			}
 		}
 	}
 
-	// Artifacts Score
-	if overall, exists := dataMap["overall_assessment"]; exists {
-		if assessment, ok := overall.(map[string]interface{}); ok {
-			if score, exists := assessment["ai_probability_score"]; exists {
-				if scoreFloat, ok := score.(float64); ok {
-					return scoreFloat
-				}
-			}
+	return -1
+}
+
+// extractOverallAssessmentScore liest overall_assessment.ai_probability_score -
+// das Format, das sowohl "artifacts" als auch "pixel-analysis" liefern.
+func extractOverallAssessmentScore(data interface{}) float64 {
+	dataMap, ok := asDataMap(data)
+	if !ok {
+		return -1
+	}
+	if assessment, ok := dataMap["overall_assessment"].(map[string]interface{}); ok {
+		if score, ok := assessment["ai_probability_score"].(float64); ok {
+			return score
 		}
 	}
+	return -1
+}
 
-	// C2PA Score
-	if c2paScore, exists := dataMap["score"]; exists {
-		if scoreFloat, ok := c2paScore.(float64); ok {
-			return scoreFloat / 100.0 // Normalisiere auf 0-1
-		}
+func extractC2PAScore(data interface{}) float64 {
+	dataMap, ok := asDataMap(data)
+	if !ok {
+		return -1
+	}
+	if score, ok := dataMap["score"].(float64); ok {
+		return score / 100.0 // Normalisiere auf 0-1
 	}
+	return -1
+}
 
-	// Pixel Analysis Score
-	if pixelOverall, exists := dataMap["overall_assessment"]; exists {
-		if assessment, ok := pixelOverall.(map[string]interface{}); ok {
-			if score, exists := assessment["ai_probability_score"]; exists {
-				if scoreFloat, ok := score.(float64); ok {
-					return scoreFloat
-				}
-			}
+func extractLightingScore(data interface{}) float64 {
+	dataMap, ok := asDataMap(data)
+	if !ok {
+		return -1
+	}
+	if analysis, ok := dataMap["lighting_analysis"].(map[string]interface{}); ok {
+		if score, ok := analysis["ai_lighting_score"].(float64); ok {
+			return score
 		}
 	}
+	return -1
+}
 
-	// Lighting Analysis Score
-	if lightingAnalysis, exists := dataMap["lighting_analysis"]; exists {
-		if analysis, ok := lightingAnalysis.(map[string]interface{}); ok {
-			if score, exists := analysis["ai_lighting_score"]; exists {
-				if scoreFloat, ok := score.(float64); ok {
-					return scoreFloat
-				}
-			}
+func extractObjectCoherenceScore(data interface{}) float64 {
+	dataMap, ok := asDataMap(data)
+	if !ok {
+		return -1
+	}
+	if analysis, ok := dataMap["object_analysis"].(map[string]interface{}); ok {
+		if score, ok := analysis["ai_coherence_score"].(float64); ok {
+			return score
 		}
 	}
+	return -1
+}
 
-	// Object Coherence Score
-	if objectAnalysis, exists := dataMap["object_analysis"]; exists {
-		if analysis, ok := objectAnalysis.(map[string]interface{}); ok {
-			if score, exists := analysis["ai_coherence_score"]; exists {
-				if scoreFloat, ok := score.(float64); ok {
-					return scoreFloat
-				}
-			}
-		}
+// extractPredictionScore liest das generische prediction/probability-Format
+// des ai-model-Detektors - "fake" ist bereits die AI-Wahrscheinlichkeit,
+// "real" muss gegen 1 gespiegelt werden.
+func extractPredictionScore(data interface{}) float64 {
+	dataMap, ok := asDataMap(data)
+	if !ok {
+		return -1
 	}
-	if prediction, exists := dataMap["prediction"]; exists {
-		if predStr, ok := prediction.(string); ok {
-			if probability, exists := dataMap["probability"]; exists {
-				if probFloat, ok := probability.(float64); ok {
-					if predStr == "fake" {
-						return probFloat
-					} else {
-						return 1.0 - probFloat
-					}
-				}
-			}
-		}
+	predStr, ok := dataMap["prediction"].(string)
+	if !ok {
+		return -1
+	}
+	probability, ok := dataMap["probability"].(float64)
+	if !ok {
+		return -1
+	}
+	if predStr == "fake" {
+		return probability
 	}
+	return 1.0 - probability
+}
+
+// extractMetadataScore wrapt detectAIFromMetadata in die ScoreExtractor-
+// Signatur - für metadata/metadata-quick/metadata-structured, deren Ergebnis
+// ein flaches map[string]interface{} aus EXIF/XMP-Feldern ist.
+func extractMetadataScore(data interface{}) float64 {
+	dataMap, ok := asDataMap(data)
+	if !ok {
+		return -1
+	}
+	return detectAIFromMetadataValues(dataMap)
+}
 
+// extractConfidenceScore ist der generische Fallback für Aufrufer wie
+// verdict.calculator, die für einen unbekannten Detektor-Namen irgendeinen
+// Score aus dessen Rohergebnis ziehen wollen (siehe ExtractConfidenceScore) -
+// probiert dieselben Formate wie stageScoreExtractors der Reihe nach durch,
+// statt sich auf den für eine bestimmte Stage registrierten Extractor zu
+// beschränken.
+func (ap *AnalysisPipeline) extractConfidenceScore(data interface{}) float64 {
+	for _, extractor := range []func(interface{}) float64{
+		extractColorBalanceScore,
+		extractAdvancedArtifactsScore,
+		extractCompressionScore,
+		extractOverallAssessmentScore,
+		extractC2PAScore,
+		extractLightingScore,
+		extractObjectCoherenceScore,
+		extractPredictionScore,
+	} {
+		if score := extractor(data); score >= 0 {
+			return score
+		}
+	}
 	return -1
 }
 
 func (ap *AnalysisPipeline) detectAIFromMetadata(dataMap map[string]interface{}) float64 {
+	return detectAIFromMetadataValues(dataMap)
+}
+
+// detectAIFromMetadataValues durchsucht dataMap nach bekannten
+// AI-Generator-Signaturen in XMP/IPTC-Textfeldern - gemeinsame Grundlage für
+// hasDefinitiveMetadataEvidence (über die Methode oben) und
+// extractMetadataScore (als ScoreExtractor der metadata*-Stages).
+func detectAIFromMetadataValues(dataMap map[string]interface{}) float64 {
 	aiKeywords := []string{
 		"ChatGPT", "DALL-E", "Midjourney", "Stable Diffusion",
 		"trainedAlgorithmicMedia", "AI generated",
@@ -615,10 +1476,18 @@ func (ap *AnalysisPipeline) detectAIFromMetadata(dataMap map[string]interface{})
 }
 
 // Cache Management Methods
+
+// ClearCache leert den aktiven Cache-Backend in-place, statt ihn durch einen
+// frischen AnalysisCache zu ersetzen - ein Ersatz würde bei einem
+// konfigurierten RedisBackend/DiskBackend stillschweigend auf einen
+// In-Memory-Cache zurückfallen und den eigentlichen Bestand (Platte/Redis)
+// unangetastet lassen.
 func (ap *AnalysisPipeline) ClearCache() {
-	if ap.cache != nil {
-		// Implementiere Cache-Clear Methode falls nötig
-		ap.cache = cache.NewAnalysisCache()
+	if ap.cache == nil {
+		return
+	}
+	for _, key := range ap.cache.Keys() {
+		ap.cache.Delete(key)
 	}
 }
 
@@ -630,14 +1499,17 @@ func (ap *AnalysisPipeline) GetCacheStats() map[string]interface{} {
 		}
 	}
 
-	// Versuche Cache-Statistiken zu ermitteln
-	stats := map[string]interface{}{
-		"enabled": true,
+	stats := ap.cache.Stats()
+
+	return map[string]interface{}{
+		"enabled":      true,
+		"hits":         stats.Hits,
+		"misses":       stats.Misses,
+		"disk_hits":    stats.DiskHits,
+		"evictions":    stats.Evictions,
+		"entries":      stats.Entries,
+		"approx_bytes": stats.ApproxBytes,
+		"max_entries":  stats.MaxEntries,
+		"max_bytes":    stats.MaxBytes,
 	}
-
-	// Falls dein Cache-Package Stats unterstützt, füge sie hinzu
-	// stats["entries"] = ap.cache.Len()
-	// stats["hit_rate"] = ap.cache.HitRate()
-
-	return stats
 }
@@ -0,0 +1,232 @@
+package pipeline
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// FormatProfile beschreibt, welche Detektor-Stages für einen MIME-Typ
+// sinnvoll sind und wie ihre Gewichte ggü. der globalen DetectorConfig
+// (siehe internal/handlers/verdict/detector_config.go) angepasst werden
+// sollen - z.B. hat "compression" (JPEG-Quantisierungsartefakte) auf einem
+// verlustfrei kodierten GIF/BMP keine Aussagekraft und wird dort gar nicht
+// erst ausgeführt, statt eine wertlose Fehlermeldung oder einen irreführenden
+// Score zu produzieren.
+type FormatProfile struct {
+	// Detectors ist die Teilmenge der in getDefaultStages definierten
+	// Stage-Namen, die für diesen MIME-Typ ausgeführt werden, in
+	// Ausführungsreihenfolge.
+	Detectors []string
+	// Weights überschreibt, sofern gesetzt, die globalen Detector-Gewichte
+	// für einzelne Detektoren bei diesem MIME-Typ - z.B. um Metadata-Signale
+	// bei Formaten mit schwachen CV-Signalen stärker zu gewichten.
+	Weights map[string]float64
+}
+
+// Registry ordnet jedem unterstützten, normalisierten MIME-Typ sein
+// FormatProfile zu. Container-Formate (HEIC/HEIF/AVIF/DNG) erreichen die
+// Pipeline nie in ihrer Rohform - utils.CreateSecureTempFile transcodiert
+// sie vorher zu JPEG/PNG (siehe convert.go) - daher sind sie hier bewusst
+// nicht aufgeführt.
+var Registry = map[string]FormatProfile{
+	"image/jpeg": {
+		Detectors: allDetectorNames(),
+	},
+	"image/png": {
+		// PNG ist verlustfrei kodiert - "compression" sucht nach
+		// JPEG-Quantisierungsartefakten, die auf einem nie JPEG-komprimierten
+		// PNG nicht vorkommen können (es sei denn, es wurde zuvor aus einem
+		// JPEG re-encodiert, was "artifacts"/"pixel-analysis" bereits
+		// abdecken).
+		Detectors: removeDetector(allDetectorNames(), "compression"),
+	},
+	"image/webp": {
+		// WEBP hat sein eigenes (verlustfreies oder verlustbehaftetes)
+		// Kompressionsschema - die auf JPEG-DQT-Tabellen zugeschnittene
+		// "compression"-Stage würde auf WEBP-Bytes falsch anschlagen.
+		Detectors: removeDetector(allDetectorNames(), "compression"),
+	},
+	"image/tiff": {
+		Detectors: removeDetector(allDetectorNames(), "compression"),
+	},
+	"image/bmp": {
+		// BMP ist unkomprimiert - CV-Detektoren, die auf Kompressionsartefakte
+		// zielen, liefern auf BMP nur Rauschen statt Signal.
+		Detectors: removeDetector(allDetectorNames(), "compression", "advanced-artifacts"),
+		Weights: map[string]float64{
+			"metadata": 4.0,
+		},
+	},
+	"image/gif": {
+		// GIF ist palettenbasiert (max. 256 Farben) - die photografischen
+		// CV-Detektoren (Artefakte, Beleuchtung, Farbbalance, Pixel-Analyse)
+		// sind auf Indexed-Color-Bilder nicht kalibriert und würden
+		// bestenfalls Rauschen, schlimmstenfalls irreführende Scores liefern.
+		Detectors: []string{"metadata-quick", "c2pa", "exif", "metadata", "metadata-structured", "ai-model"},
+		Weights: map[string]float64{
+			"metadata-quick": 1.5,
+			"metadata":       4.0,
+		},
+	},
+}
+
+// allDetectorNames liefert die Namen aller in getDefaultStages definierten
+// Stages, in ihrer dort deklarierten Reihenfolge - Grundlage für
+// FormatProfile.Detectors bei Formaten, die (fast) alle Detektoren sinnvoll
+// unterstützen.
+func allDetectorNames() []string {
+	stages := getDefaultStages()
+	names := make([]string, len(stages))
+	for i, stage := range stages {
+		names[i] = stage.Name
+	}
+	return names
+}
+
+func removeDetector(names []string, remove ...string) []string {
+	skip := make(map[string]bool, len(remove))
+	for _, r := range remove {
+		skip[r] = true
+	}
+	kept := make([]string, 0, len(names))
+	for _, name := range names {
+		if !skip[name] {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+// ExpectedDetectorCount liefert, wie viele Detektoren für mimeType laut
+// Registry vorgesehen sind - calculateAnalysisQuality nutzt das als Nenner,
+// wenn der MIME-Typ bekannt ist, damit ein für dieses Format von vornherein
+// nicht vorgesehener Detektor (z.B. "compression" bei PNG) die Quote nicht
+// wie ein fehlgeschlagener Detektor aussehen lässt. ok=false bedeutet, dass
+// mimeType nicht in Registry bekannt ist.
+func ExpectedDetectorCount(mimeType string) (count int, ok bool) {
+	profile, ok := Registry[mimeType]
+	if !ok {
+		return 0, false
+	}
+	return len(profile.Detectors), true
+}
+
+// ExpectedDetectors liefert die laut Registry für mimeType vorgesehenen
+// Detektor-Namen - genutzt, um Gewichts-Summen (z.B. evidence_coverage in
+// internal/handlers/verdict/determination.go) auf die für dieses Format
+// tatsächlich ausführbaren Detektoren zu beschränken, statt auch nie
+// laufende Detektoren (z.B. "compression" bei GIF) in den Nenner
+// einzurechnen. ok=false bedeutet, dass mimeType nicht in Registry bekannt
+// ist.
+func ExpectedDetectors(mimeType string) (names []string, ok bool) {
+	profile, ok := Registry[mimeType]
+	if !ok {
+		return nil, false
+	}
+	return profile.Detectors, true
+}
+
+// IsSupportedMIME meldet, ob mimeType ein in Registry bekanntes Format ist -
+// uploadHandler nutzt das, um unbekannte Formate mit 415 abzulehnen, statt
+// sie mit dem vollen Detektor-Satz durch die Pipeline laufen zu lassen.
+func IsSupportedMIME(mimeType string) bool {
+	_, ok := Registry[mimeType]
+	return ok
+}
+
+// StagesForMIME liefert die für mimeType konfigurierten Stages in
+// Registry-Reihenfolge. Ist mimeType nicht in Registry bekannt, werden alle
+// Stages zurückgegeben (defensiver Fallback - uploadHandler hat unbekannte
+// Formate bereits vorher mit 415 abgelehnt, dieser Pfad greift nur für
+// Aufrufer, die DetectMIME nicht selbst geprüft haben, z.B. Tests).
+func StagesForMIME(mimeType string) []AnalysisStage {
+	profile, ok := Registry[mimeType]
+	if !ok {
+		return getDefaultStages()
+	}
+
+	byName := make(map[string]AnalysisStage, len(profile.Detectors))
+	for _, stage := range getDefaultStages() {
+		byName[stage.Name] = stage
+	}
+
+	stages := make([]AnalysisStage, 0, len(profile.Detectors))
+	for _, name := range profile.Detectors {
+		if stage, ok := byName[name]; ok {
+			stages = append(stages, stage)
+		}
+	}
+	return stages
+}
+
+// MergeFormatWeights wendet die Weights-Overrides aus Registry[mimeType]
+// (falls vorhanden) auf eine Kopie von baseWeights an - verdict.
+// CalculateOverallVerdict ruft das statt baseWeights direkt zu verwenden, um
+// die globale DetectorConfig pro Format nachzujustieren, ohne sie zu
+// verändern.
+func MergeFormatWeights(mimeType string, baseWeights map[string]float64) map[string]float64 {
+	profile, ok := Registry[mimeType]
+	if !ok || len(profile.Weights) == 0 {
+		return baseWeights
+	}
+
+	merged := make(map[string]float64, len(baseWeights))
+	for name, weight := range baseWeights {
+		merged[name] = weight
+	}
+	for name, weight := range profile.Weights {
+		merged[name] = weight
+	}
+	return merged
+}
+
+// mimeSniffLen ist die Anzahl Bytes, die DetectMIME für http.DetectContentType
+// liest - derselbe Wert, den net/http selbst intern verwendet.
+const mimeSniffLen = 512
+
+// DetectMIME bestimmt den MIME-Typ von path per net/http.DetectContentType
+// und normalisiert dessen Ergebnis (das z.B. "image/jpeg" liefert, aber auch
+// Parameter wie "; charset=..." anhängen kann, was hier nicht vorkommt aber
+// zur Robustheit trotzdem über strings.Cut entfernt wird) auf die in Registry
+// verwendeten Schlüssel. net/http erkennt kein TIFF zuverlässig über alle
+// Varianten hinweg, daher greift magicNumberMIME als Fallback.
+func DetectMIME(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open %s for MIME sniffing: %w", path, err)
+	}
+	defer f.Close()
+
+	buf := make([]byte, mimeSniffLen)
+	n, err := f.Read(buf)
+	if err != nil && n == 0 {
+		return "", fmt.Errorf("read %s for MIME sniffing: %w", path, err)
+	}
+	buf = buf[:n]
+
+	if mime := magicNumberMIME(buf); mime != "" {
+		return mime, nil
+	}
+
+	return http.DetectContentType(buf), nil
+}
+
+// magicNumberMIME erkennt Formate per Magic Bytes, die
+// http.DetectContentType nicht (zuverlässig) unterscheidet - insbesondere
+// TIFF (Little- und Big-Endian) und BMP, falls der darin enthaltene
+// "image/bmp"-Zweig von net/http je nach Go-Version abweicht. Gibt "" zurück,
+// wenn keine der bekannten Signaturen passt, und überlässt die Entscheidung
+// dann http.DetectContentType.
+func magicNumberMIME(buf []byte) string {
+	switch {
+	case len(buf) >= 4 && bytes.Equal(buf[0:4], []byte{0x49, 0x49, 0x2A, 0x00}):
+		return "image/tiff"
+	case len(buf) >= 4 && bytes.Equal(buf[0:4], []byte{0x4D, 0x4D, 0x00, 0x2A}):
+		return "image/tiff"
+	case len(buf) >= 2 && bytes.Equal(buf[0:2], []byte{0x42, 0x4D}):
+		return "image/bmp"
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package pipeline
+
+import "strings"
+
+// Verdict ist eine typisierte Abstufung des finalen Analyse-Ergebnisses -
+// ersetzt den brüchigen Vergleich fest verdrahteter Label-Strings (z.B.
+// Metrics.RecordVerdict, das gegen "AI Generated (Confirmed)" & Co. verglich,
+// obwohl determineBalancedVerdict nie diese Strings zurückgab), der bei
+// jeder Umformulierung der Label stillschweigend falsch zählte.
+type Verdict int
+
+const (
+	VerdictUnknown Verdict = iota
+	VerdictHuman
+	VerdictLikelyHuman
+	VerdictPossiblyAI
+	VerdictLikelyAI
+	VerdictVeryLikelyAI
+	VerdictConfirmedAI
+	// VerdictInconclusive ist das Abstain-Ergebnis von determineBalancedVerdict
+	// bei zu geringer evidence_coverage oder starkem CV/AI-Widerspruch (siehe
+	// internal/handlers/verdict/determination.go) - bewusst getrennt von
+	// VerdictUnknown, das den technischen Fehlerfall (keine verwertbaren
+	// Analyseergebnisse) markiert. RecordVerdict/RecordVerdictLabel würden
+	// sonst beide unter demselben Enum-Wert zählen und damit genau die
+	// Unterscheidung verlieren, die abstain_reason erst eingeführt hat.
+	VerdictInconclusive
+)
+
+// String liefert das für Menschen lesbare Label, wie es bisher direkt von
+// verdict.CalculateOverallVerdict zurückgegeben wurde - API-Antworten bleiben
+// dadurch unverändert, obwohl intern jetzt das Enum geführt wird.
+func (v Verdict) String() string {
+	switch v {
+	case VerdictHuman:
+		return "Authentic"
+	case VerdictLikelyHuman:
+		return "Likely Authentic"
+	case VerdictPossiblyAI:
+		return "Possibly AI Generated"
+	case VerdictLikelyAI:
+		return "Likely AI Generated"
+	case VerdictVeryLikelyAI:
+		return "Very Likely AI Generated"
+	case VerdictConfirmedAI:
+		return "AI Generated (Confirmed)"
+	case VerdictInconclusive:
+		return "Inconclusive"
+	default:
+		return "Unknown"
+	}
+}
+
+// IsAIGenerated meldet, ob v eine der AI-Abstufungen ist - Grundlage für
+// Metrics.AIDetectedCount, vorher ein String-Vergleich gegen vier
+// Label-Varianten, von denen keine zu den tatsächlich von
+// determineBalancedVerdict gelieferten Strings passte.
+func (v Verdict) IsAIGenerated() bool {
+	switch v {
+	case VerdictPossiblyAI, VerdictLikelyAI, VerdictVeryLikelyAI, VerdictConfirmedAI:
+		return true
+	default:
+		return false
+	}
+}
+
+// legacyVerdictLabels bildet sowohl die von determineBalancedVerdict/
+// computeVerdict tatsächlich zurückgegebenen Label-Strings als auch ältere,
+// inzwischen nicht mehr produzierte Varianten auf das neue Enum ab - für die
+// in der Request geforderte Übergangsphase.
+var legacyVerdictLabels = map[string]Verdict{
+	"Authentic":                VerdictHuman,
+	"Likely Human":             VerdictLikelyHuman,
+	"Likely Authentic":         VerdictLikelyHuman,
+	"Possibly AI Generated":    VerdictPossiblyAI,
+	"Likely AI Generated":      VerdictLikelyAI,
+	"Very Likely AI Generated": VerdictVeryLikelyAI,
+	"AI Generated":             VerdictConfirmedAI,
+	"AI Generated (Confirmed)": VerdictConfirmedAI,
+	"Inconclusive":             VerdictInconclusive,
+}
+
+// ParseVerdict übersetzt ein Legacy-Label in das neue Enum. Unbekannte Labels
+// ergeben VerdictUnknown statt eines Fehlers, da Aufrufer (Metrics.RecordVerdict)
+// auf noch nicht migrierte oder zukünftige Label-Varianten nicht hart
+// reagieren sollen.
+//
+// TODO(nächster Release): entfernen, sobald alle Aufrufer das Enum direkt
+// über PipelineResult.Verdict beziehen statt über den "verdict"-String.
+func ParseVerdict(label string) Verdict {
+	if v, ok := legacyVerdictLabels[strings.TrimSpace(label)]; ok {
+		return v
+	}
+	return VerdictUnknown
+}
@@ -0,0 +1,396 @@
+package pythonrunner
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// backendRequest ist die pro Aufruf an einen Worker gesendete Anfrage -
+// newline-getrennt auf dessen stdin geschrieben. Entspricht dem
+// AnalyzeRequest{image_path, params} aus dem LocalAI-gRPC-Vorbild, nur ohne
+// den Protobuf-Unterbau, den dieses Repo (noch) nirgendwo als Abhängigkeit
+// führt - ein in-process serialisiertes JSON-Längenprotokoll über stdin/
+// stdout erreicht denselben Zweck (ein Worker-Prozess pro Analyzer bleibt
+// über mehrere Requests am Leben), ohne protoc/grpc-go als neue
+// Build-Abhängigkeit einzuführen.
+type backendRequest struct {
+	Ping      bool                   `json:"ping,omitempty"`
+	ImagePath string                 `json:"image_path,omitempty"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+}
+
+// backendResponse ist die vom Worker pro Zeile auf stdout zurückgegebene
+// Antwort - Pong für den Health-Check, sonst Payload/Err analog zu
+// AnalyzeResponse{json_payload, err}. Ein Worker darf vor der
+// abschließenden Payload/Err-Zeile beliebig viele Progress-Zeilen
+// schreiben (z.B. {"progress": {"percent": 40, "message": "..."}}) - jede
+// solche Zeile hat ausschließlich Progress gesetzt und wird von roundtrip
+// an onProgress weitergereicht, statt als finale Antwort behandelt zu
+// werden (siehe isFinal).
+type backendResponse struct {
+	Pong     bool            `json:"pong,omitempty"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+	Err      string          `json:"err,omitempty"`
+	Progress json.RawMessage `json:"progress,omitempty"`
+}
+
+// isFinal meldet, ob resp die abschließende Antwort auf einen Request ist
+// (statt einer reinen Zwischenfortschrittsmeldung).
+func (r backendResponse) isFinal() bool {
+	return r.Pong || r.Payload != nil || r.Err != ""
+}
+
+// backend hält den Prozess eines einzelnen, dauerhaft laufenden
+// Python-Workers sowie seine stdin/stdout-Pipes. Ein Worker beantwortet
+// Requests strikt FIFO, deshalb serialisiert mu alle Calls gegen diesen
+// einen Prozess statt ihn nebenläufig mit verschachtelten Antworten zu
+// verwirren.
+type backend struct {
+	mu     sync.Mutex
+	name   string
+	script string
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	reader *bufio.Scanner
+	ready  bool
+	// killed markiert, dass killLocked bereits gelaufen ist - macht
+	// killLocked idempotent, da es sowohl von roundtripStream (Timeout/
+	// Ctx-Abbruch, mit bereits gehaltenem b.mu) als auch von kill()
+	// (BackendManager-Restart/Reload) aufgerufen werden kann, ohne dass
+	// ein zweiter Aufruf cmd.Wait() ein zweites Mal auf denselben, längst
+	// abgeräumten Prozess anwendet.
+	killed  bool
+	sandbox *Sandbox
+}
+
+// BackendManager hält je Analyzer-Name höchstens einen laufenden Worker vor
+// und startet ihn verzögert beim ersten Call, statt wie die früheren
+// RunCompression & Co. für jedes Bild einen neuen python3-Prozess zu
+// starten - numpy/opencv/torch/timm werden dadurch nur einmal pro
+// Prozesslaufzeit importiert statt einmal pro Request, und das
+// ai-model-Ensemble bleibt über Requests hinweg im (GPU-)Speicher geladen.
+type BackendManager struct {
+	mu            sync.Mutex
+	defaultPython string
+	analyzers     map[string]Analyzer
+	backends      map[string]*backend
+	sandbox       *Sandbox
+}
+
+// NewBackendManager baut einen BackendManager, der analyzers (Backend-Name
+// -> Analyzer) mit defaultPython als Interpreter startet, sofern ein
+// Analyzer selbst keinen eigenen Interpreter angibt. Die Worker selbst
+// werden erst beim ersten Call tatsächlich gestartet (siehe ensure), dann
+// über sandbox (siehe sandbox.go) statt per nacktem exec.Command.
+func NewBackendManager(defaultPython string, analyzers map[string]Analyzer, sandbox *Sandbox) *BackendManager {
+	return &BackendManager{
+		defaultPython: defaultPython,
+		analyzers:     analyzers,
+		backends:      make(map[string]*backend),
+		sandbox:       sandbox,
+	}
+}
+
+// ensure liefert den laufenden Worker für name, startet ihn bei Bedarf.
+func (m *BackendManager) ensure(name string) (*backend, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if b, ok := m.backends[name]; ok && b.ready {
+		return b, nil
+	}
+
+	analyzer, ok := m.analyzers[name]
+	if !ok {
+		return nil, fmt.Errorf("pythonrunner: unknown backend %q", name)
+	}
+
+	b, err := m.start(analyzer)
+	if err != nil {
+		return nil, err
+	}
+	m.backends[name] = b
+	return b, nil
+}
+
+func (m *BackendManager) start(analyzer Analyzer) (*backend, error) {
+	pythonCmd := analyzer.Interpreter
+	if pythonCmd == "" {
+		pythonCmd = m.defaultPython
+	}
+
+	args := append([]string{analyzer.ScriptPath, "--worker"}, analyzer.Args...)
+	cmd := m.sandbox.Command(pythonCmd, args)
+	name, script := analyzer.Name, analyzer.ScriptPath
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("could not open stdin for %s worker: %v", name, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("could not open stdout for %s worker: %v", name, err)
+	}
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("could not start %s worker: %v", name, err)
+	}
+	if err := m.sandbox.AfterStart(cmd); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, fmt.Errorf("could not sandbox %s worker: %v", name, err)
+	}
+
+	reader := bufio.NewScanner(stdout)
+	reader.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	b := &backend{
+		name:    name,
+		script:  script,
+		cmd:     cmd,
+		stdin:   stdin,
+		reader:  reader,
+		sandbox: m.sandbox,
+	}
+
+	if err := b.ping(); err != nil {
+		b.kill()
+		return nil, fmt.Errorf("%s worker failed health check: %v", name, err)
+	}
+	b.ready = true
+	return b, nil
+}
+
+// ping prüft direkt nach dem Start, ob der Worker bereits antwortet, bevor
+// ihn der BackendManager als einsatzbereit markiert.
+func (b *backend) ping() error {
+	resp, err := b.roundtrip(context.Background(), backendRequest{Ping: true}, 10*time.Second)
+	if err != nil {
+		return err
+	}
+	if !resp.Pong {
+		return fmt.Errorf("expected pong, got %+v", resp)
+	}
+	return nil
+}
+
+// kill nimmt b.mu, bevor es cmd/stdin anfasst - sonst könnte ein
+// gleichzeitig in call()/roundtripStream laufender Request mitten im
+// Write/Scan auf dieselben Pipes treffen, die hier per Close/Process.Kill
+// unter ihm weggerissen werden (z.B. Reload während eines laufenden
+// Requests, oder CallWithParams' eigener Restart-Pfad gegen denselben
+// Backend-Namen). Der eigentliche Teardown steckt in killLocked, da
+// roundtripStream ihn bei Timeout/ctx-Abbruch synchron ausführen muss,
+// während b.mu von call() bereits gehalten wird - ein erneutes Lock an
+// derselben Stelle würde deadlocken.
+func (b *backend) kill() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.killLocked()
+}
+
+// killLocked setzt b.ready auf false und beendet den Worker-Prozess - der
+// Aufrufer muss b.mu bereits halten (oder, wie ping() beim Start, garantiert
+// alleinigen Zugriff auf b haben). b.killed macht den Teardown idempotent:
+// sowohl kill() (Reload, CallWithParams' Restart-Pfad) als auch
+// roundtripStream (Timeout/ctx-Abbruch) können killLocked für denselben
+// Prozess aufrufen, und ein zweiter cmd.Wait()-Aufruf auf einen bereits
+// abgeräumten Prozess wäre ein Fehler ohne Erkenntniswert.
+func (b *backend) killLocked() {
+	if b.killed {
+		return
+	}
+	b.killed = true
+
+	b.stdin.Close()
+	if b.cmd.Process != nil {
+		b.cmd.Process.Kill()
+	}
+	b.cmd.Wait()
+	b.ready = false
+}
+
+// roundtrip schreibt req als JSON-Zeile auf stdin des Workers und liest die
+// abschließende Antwortzeile zurück - siehe roundtripStream für Worker, die
+// vorher noch Progress-Zeilen schreiben.
+func (b *backend) roundtrip(ctx context.Context, req backendRequest, timeout time.Duration) (backendResponse, error) {
+	return b.roundtripStream(ctx, req, timeout, nil)
+}
+
+// roundtripStream schreibt req als JSON-Zeile auf stdin des Workers und
+// liest Antwortzeilen, bis eine finale Zeile (isFinal) eintrifft. Zeilen
+// davor gelten als Progress-Updates und werden, sofern onProgress gesetzt
+// ist, mit ihrem Progress-Feld an onProgress weitergereicht - onProgress
+// darf nil sein, dann werden Progress-Zeilen stillschweigend übersprungen
+// (das normale roundtrip-Verhalten für Worker, die gar keine
+// Zwischenmeldungen schreiben). timeout begrenzt das Warten auf JEDE
+// einzelne Zeile zusätzlich zu ctx - wird ctx abgebrochen (z.B. weil der
+// Client die Verbindung trennt oder die Pipeline selbst abbricht), kehrt
+// roundtripStream sofort zurück statt bis zum vollen timeout zu blockieren,
+// wie es vorher exec.CommandContext für den pro Request gestarteten Prozess
+// bereits tat. Ein abgelaufenes timeout oder ctx beendet den Worker-Prozess
+// synchron per killLocked, bevor roundtripStream zurückkehrt: die oben
+// gestartete Scan-Goroutine hängt in diesem Fall möglicherweise noch in
+// b.reader.Scan() fest, und der Aufrufer (call) hält b.mu nur bis zu seinem
+// eigenen return - ohne den Prozess hier tot zu machen, könnte ein zweiter,
+// gleichzeitiger Call auf denselben Backend-Namen b.mu erneut bekommen und
+// eine zweite Scan-Goroutine auf demselben bufio.Scanner starten, bevor
+// CallWithParams' Restart-Pfad überhaupt zum Zug kommt - zwei Leser auf
+// einem Scanner sind eine Race, die eine Antwort dem falschen Aufrufer
+// zustellen kann. killLocked ist idempotent, der spätere kill()-Aufruf im
+// Restart-Pfad von CallWithParams ist dann ein No-Op.
+func (b *backend) roundtripStream(ctx context.Context, req backendRequest, timeout time.Duration, onProgress func(json.RawMessage)) (backendResponse, error) {
+	line, err := json.Marshal(req)
+	if err != nil {
+		return backendResponse{}, err
+	}
+	if _, err := b.stdin.Write(append(line, '\n')); err != nil {
+		return backendResponse{}, fmt.Errorf("write to %s worker failed: %v", b.name, err)
+	}
+
+	type scanResult struct {
+		resp backendResponse
+		err  error
+	}
+	lines := make(chan scanResult, 8)
+	go func() {
+		for {
+			if !b.reader.Scan() {
+				lines <- scanResult{err: fmt.Errorf("%s worker closed stdout: %v", b.name, b.reader.Err())}
+				return
+			}
+			var resp backendResponse
+			if err := json.Unmarshal(b.reader.Bytes(), &resp); err != nil {
+				lines <- scanResult{err: fmt.Errorf("invalid %s worker response: %v", b.name, err)}
+				return
+			}
+			lines <- scanResult{resp: resp}
+			if resp.isFinal() {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case r := <-lines:
+			if r.err != nil {
+				return backendResponse{}, r.err
+			}
+			if !r.resp.isFinal() {
+				if onProgress != nil && r.resp.Progress != nil {
+					onProgress(r.resp.Progress)
+				}
+				continue
+			}
+			return r.resp, nil
+		case <-ctx.Done():
+			b.killLocked()
+			return backendResponse{}, ctx.Err()
+		case <-time.After(timeout):
+			b.killLocked()
+			return backendResponse{}, fmt.Errorf("%s worker did not respond within %s", b.name, timeout)
+		}
+	}
+}
+
+// Call schickt imgPath an den Backend-Worker name und liefert dessen
+// geparstes JSON-Payload. Startet den Worker beim ersten Aufruf (ensure) und
+// unternimmt bei einem toten oder nicht antwortenden Worker genau einen
+// Neustart-Versuch, bevor der Call fehlschlägt - ein abgestürzter Worker
+// soll nicht jeden weiteren Call auf diesen Backend-Namen dauerhaft
+// blockieren.
+func (m *BackendManager) Call(ctx context.Context, name, imgPath string) (interface{}, error) {
+	return m.CallStreaming(ctx, name, imgPath, nil)
+}
+
+// CallStreaming verhält sich wie Call, ruft aber onProgress (sofern nicht
+// nil) für jede Zwischenfortschrittszeile auf, die der Worker vor seiner
+// abschließenden Antwort schreibt (siehe backend.roundtripStream) - z.B.
+// "artifacts: 40% blocks processed" während einer langsamen Analyse.
+func (m *BackendManager) CallStreaming(ctx context.Context, name, imgPath string, onProgress func(json.RawMessage)) (interface{}, error) {
+	return m.CallWithParams(ctx, name, imgPath, nil, onProgress)
+}
+
+// CallWithParams verhält sich wie CallStreaming, schickt aber params
+// zusätzlich im "params"-Feld des Requests mit - der Worker bekommt damit
+// z.B. das bereits berechnete Ergebnis einer Dependency-Stage
+// (siehe pipeline.AnalysisStage.Dependencies) und kann es wiederverwenden,
+// statt es erneut zu berechnen.
+func (m *BackendManager) CallWithParams(ctx context.Context, name, imgPath string, params map[string]interface{}, onProgress func(json.RawMessage)) (interface{}, error) {
+	b, err := m.ensure(name)
+	if err != nil {
+		return nil, err
+	}
+
+	result, callErr := b.call(ctx, imgPath, params, onProgress)
+	if callErr == nil {
+		return result, nil
+	}
+
+	log.Printf("pythonrunner: %s worker call failed (%v), restarting", name, callErr)
+	m.mu.Lock()
+	b.kill()
+	delete(m.backends, name)
+	m.mu.Unlock()
+
+	b, err = m.ensure(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s worker unavailable after restart: %v", name, err)
+	}
+	return b.call(ctx, imgPath, params, onProgress)
+}
+
+func (b *backend) call(ctx context.Context, imgPath string, params map[string]interface{}, onProgress func(json.RawMessage)) (interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	timeout := 2 * time.Minute
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining > 0 {
+			timeout = remaining
+		}
+	}
+
+	workerPath := imgPath
+	if b.sandbox != nil {
+		workerPath = b.sandbox.TranslatePath(imgPath)
+	}
+
+	resp, err := b.roundtripStream(ctx, backendRequest{ImagePath: workerPath, Params: params}, timeout, onProgress)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Err != "" {
+		return nil, fmt.Errorf("%s worker error: %s", b.name, resp.Err)
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(resp.Payload, &result); err != nil {
+		return nil, fmt.Errorf("invalid %s worker payload: %v", b.name, err)
+	}
+	return result, nil
+}
+
+// Reload ersetzt die bekannten Analyzer-Definitionen durch analyzers und
+// beendet alle aktuell laufenden Worker - sie würden sonst mit dem vor dem
+// Reload gültigen Skriptpfad/Args weiterlaufen. Der nächste Call auf einen
+// Backend-Namen startet ihn per ensure mit der neuen Definition neu.
+func (m *BackendManager) Reload(analyzers map[string]Analyzer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.analyzers = analyzers
+	for name, b := range m.backends {
+		b.kill()
+		delete(m.backends, name)
+	}
+}
@@ -0,0 +1,393 @@
+package pythonrunner
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Analyzer beschreibt einen einzelnen Python-Analyzer vollständig
+// deklarativ - ersetzt die Kopierschablone, die RunCompression & Co. vor
+// der Registry waren (identischer exec/read/parse-Ablauf, nur Skriptname
+// und Fehlertext unterschieden sich). Ein neuer Analyzer braucht damit
+// einen neuen Eintrag in analyzers.yaml statt einer neuen Go-Funktion plus
+// Verdrahtung in pipelines.go.
+type Analyzer struct {
+	// Name identifiziert den Analyzer in Registry und ist zugleich der
+	// Backend-Name, den BackendManager für dessen Worker-Prozess verwendet.
+	Name string
+	// ScriptPath ist der Pfad zum Python-Skript, relativ zum
+	// Arbeitsverzeichnis des Server-Prozesses.
+	ScriptPath string
+	// Interpreter überschreibt den Standard-Interpreter (siehe
+	// findWorkingPython) für diesen einen Analyzer - z.B. braucht ai-model
+	// die venv mit torch/timm, während die übrigen Skripte mit System-Python
+	// auskommen.
+	Interpreter string
+	// Args sind zusätzliche Kommandozeilenargumente, die vor dem
+	// "--worker"-Flag an den Worker-Prozess übergeben werden (z.B.
+	// "--models <dir>" für ai-model).
+	Args []string
+	// Timeout begrenzt, wie lange Run auf die Antwort eines einzelnen
+	// Calls wartet - unabhängig vom (meist großzügigeren) Timeout, das
+	// pipeline.AnalysisStage für dieselbe Stage konfiguriert.
+	Timeout time.Duration
+	// ResultSchema ist, falls gesetzt, der Pfad zu einer JSON-Datei der
+	// Form {"required": ["feld1", "feld2"]} - Run lehnt ein Ergebnis ab,
+	// dem eines der dort gelisteten Felder fehlt.
+	ResultSchema string
+}
+
+// defaultAnalyzerRegistryPath ist der Default-Pfad für LoadRegistry, analog
+// zu defaultDetectorConfigPath/defaultCalibrationPath in
+// internal/handlers/verdict.
+const defaultAnalyzerRegistryPath = "pythonScripts/analyzers.yaml"
+
+// defaultRegistry sind die eingebauten Analyzer-Definitionen, die verwendet
+// werden, solange analyzers.yaml fehlt - identisch zu den vor der Registry
+// hartkodierten Skriptpfaden/Timeouts in runner.go/pipelines.go.
+func defaultRegistry() map[string]Analyzer {
+	entries := []Analyzer{
+		{Name: "compression", ScriptPath: filepath.Join("pythonScripts", "analyze_compression.py"), Timeout: 10 * time.Second},
+		{Name: "artifacts", ScriptPath: filepath.Join("pythonScripts", "detect-artifacts.py"), Timeout: 15 * time.Second},
+		{Name: "color-balance", ScriptPath: filepath.Join("pythonScripts", "analyze_color_balance.py"), Timeout: 12 * time.Second},
+		{Name: "color-histogram", ScriptPath: filepath.Join("pythonScripts", "analyze_color_histogram.py"), Timeout: 12 * time.Second},
+		{Name: "pixel-analysis", ScriptPath: filepath.Join("pythonScripts", "analyze_pixel.py"), Timeout: 18 * time.Second},
+		{Name: "advanced-artifacts", ScriptPath: filepath.Join("pythonScripts", "advanced-artifacts.py"), Timeout: 20 * time.Second},
+		{Name: "object-coherence", ScriptPath: filepath.Join("pythonScripts", "analyze_coherence.py"), Timeout: 25 * time.Second},
+		{Name: "lighting-analysis", ScriptPath: filepath.Join("pythonScripts", "analyze_lighting.py"), Timeout: 20 * time.Second},
+		{
+			Name:        "ai-model",
+			ScriptPath:  filepath.Join("ai-analyse", "new_analysis", "classify-v6.py"),
+			Interpreter: filepath.Join("venv", "bin", "python3"),
+			Args:        []string{"--models", filepath.Join("ai-analyse", "new_analysis", "ensemble1")},
+			Timeout:     30 * time.Second,
+		},
+	}
+
+	registry := make(map[string]Analyzer, len(entries))
+	for _, a := range entries {
+		registry[a.Name] = a
+	}
+	return registry
+}
+
+var (
+	registryMu           sync.RWMutex
+	analyzerRegistryPath = defaultAnalyzerRegistryPath
+	activeRegistry       = defaultRegistry()
+	registryWatchOnce    sync.Once
+)
+
+// InitRegistry lädt die Analyzer-Registry von path (leer =
+// defaultAnalyzerRegistryPath), registriert einen SIGHUP-Handler und
+// startet ein Polling, das analyzers.yaml bei Änderungen automatisch neu
+// einliest - analog zu verdict.InitDetectorConfig. Wird path nicht
+// gefunden, bleibt defaultRegistry aktiv.
+func InitRegistry(path string) {
+	registryMu.Lock()
+	if path != "" {
+		analyzerRegistryPath = path
+	}
+	registryMu.Unlock()
+
+	reloadRegistry()
+
+	registryWatchOnce.Do(func() {
+		sighup := make(chan os.Signal, 1)
+		signal.Notify(sighup, syscall.SIGHUP)
+		go func() {
+			for range sighup {
+				fmt.Println("SIGHUP empfangen, lade Analyzer-Registry neu:", analyzerRegistryPath)
+				reloadRegistry()
+			}
+		}()
+
+		go watchRegistryFile()
+	})
+}
+
+func watchRegistryFile() {
+	var lastMod time.Time
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		registryMu.RLock()
+		path := analyzerRegistryPath
+		registryMu.RUnlock()
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(lastMod) {
+			lastMod = info.ModTime()
+			if !lastMod.IsZero() {
+				reloadRegistry()
+			}
+		}
+	}
+}
+
+func reloadRegistry() {
+	registryMu.RLock()
+	path := analyzerRegistryPath
+	registryMu.RUnlock()
+
+	registry, err := LoadRegistry(path)
+	if err != nil {
+		fmt.Printf("WARNING: Analyzer-Registry %s konnte nicht geladen werden (%v), behalte aktive Registry\n", path, err)
+		return
+	}
+
+	registryMu.Lock()
+	activeRegistry = registry
+	registryMu.Unlock()
+
+	// Ein aktiver Reload verwirft alle laufenden Worker - sie würden sonst
+	// mit veralteten Skriptpfaden/Args weiterlaufen, bis sie von selbst
+	// abstürzen. resetBackends ruft currentRegistry() auf, das seinerseits
+	// registryMu sperrt - darf daher erst außerhalb der obigen Lock-Sektion
+	// laufen, sonst blockiert sich reloadRegistry selbst.
+	resetBackends()
+}
+
+func currentRegistry() map[string]Analyzer {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return activeRegistry
+}
+
+func lookupAnalyzer(name string) (Analyzer, bool) {
+	registry := currentRegistry()
+	a, ok := registry[name]
+	return a, ok
+}
+
+// RegisteredAnalyzers liefert die Namen aller aktuell registrierten
+// Analyzer - pipelines.go nutzt das, um die python-gestützten Stages aus
+// der Registry statt aus hartkodierten Run*-Referenzen aufzubauen.
+func RegisteredAnalyzers() []string {
+	registry := currentRegistry()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	return names
+}
+
+// TimeoutFor liefert das in der Registry für name konfigurierte Timeout -
+// pipelines.go liest darüber das AnalysisStage.Timeout der python-gestützten
+// Stages, statt denselben Wert zusätzlich als Literal in pipelines.go zu
+// pflegen. ok=false bedeutet, dass name nicht registriert ist.
+func TimeoutFor(name string) (timeout time.Duration, ok bool) {
+	analyzer, ok := lookupAnalyzer(name)
+	if !ok {
+		return 0, false
+	}
+	return analyzer.Timeout, true
+}
+
+// AnalyzerFunc liefert eine AnalysisStage.Analyzer-kompatible Closure, die
+// RunWithDeps(ctx, name, imagePath, deps) aufruft - pipelines.go baut damit
+// die python-gestützten Stages, ohne für jeden Analyzer-Namen weiterhin eine
+// eigene RunX-Funktion referenzieren zu müssen. deps sind die Ergebnisse
+// bereits abgeschlossener Dependency-Stages (siehe AnalysisStage.
+// Dependencies) - z.B. kann "advanced-artifacts" darüber das schon
+// berechnete "artifacts"-Ergebnis als Params statt erneuter Berechnung
+// bekommen.
+func AnalyzerFunc(name string) func(context.Context, string, map[string]interface{}) (interface{}, error) {
+	return func(ctx context.Context, imagePath string, deps map[string]interface{}) (interface{}, error) {
+		return RunWithDeps(ctx, name, imagePath, deps, nil)
+	}
+}
+
+// StreamingAnalyzerFunc liefert eine AnalysisStage.StreamingAnalyzer-
+// kompatible Closure, die RunStreaming(ctx, name, imagePath, onProgress)
+// aufruft - Gegenstück zu AnalyzerFunc für Stages, deren Fortschritt per
+// pipeline.AnalysisPipeline.RunAnalysisStream beobachtet werden soll.
+func StreamingAnalyzerFunc(name string) func(context.Context, string, func(interface{})) (interface{}, error) {
+	return func(ctx context.Context, imagePath string, onProgress func(interface{})) (interface{}, error) {
+		return RunStreaming(ctx, name, imagePath, onProgress)
+	}
+}
+
+// LoadRegistry parst dasselbe minimalistische YAML-Subset wie
+// verdict.loadDetectorConfig, erweitert um eine Liste unter "analyzers:" -
+// jeder Eintrag beginnt mit "- " und listet darunter eingerückt
+// "key: value"-Paare; "args" ist eine kommagetrennte Liste statt einer
+// echten YAML-Sequenz, da dieses Repo keine YAML-Bibliothek einbindet.
+func LoadRegistry(path string) (map[string]Analyzer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	registry := make(map[string]Analyzer)
+	var current *Analyzer
+	inAnalyzers := false
+
+	flush := func() {
+		if current != nil && current.Name != "" {
+			registry[current.Name] = *current
+		}
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		rawLine := scanner.Text()
+		trimmed := strings.TrimSpace(rawLine)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if trimmed == "analyzers:" {
+			inAnalyzers = true
+			continue
+		}
+		if !inAnalyzers {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") {
+			flush()
+			current = &Analyzer{}
+			trimmed = strings.TrimPrefix(trimmed, "- ")
+		}
+		if current == nil {
+			continue
+		}
+
+		key, value, ok := splitRegistryKeyValue(trimmed)
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "name":
+			current.Name = value
+		case "script":
+			current.ScriptPath = value
+		case "interpreter":
+			current.Interpreter = value
+		case "args":
+			current.Args = splitRegistryArgs(value)
+		case "timeout":
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout for analyzer %q: %w", current.Name, err)
+			}
+			current.Timeout = d
+		case "schema":
+			current.ResultSchema = value
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(registry) == 0 {
+		return nil, fmt.Errorf("analyzer registry %s has no analyzers", path)
+	}
+	return registry, nil
+}
+
+func splitRegistryKeyValue(line string) (string, string, bool) {
+	idx := strings.Index(line, ":")
+	if idx < 0 {
+		return "", "", false
+	}
+	key := strings.TrimSpace(line[:idx])
+	value := strings.TrimSpace(line[idx+1:])
+	value = strings.Trim(value, `"`)
+	if key == "" || value == "" {
+		return "", "", false
+	}
+	return key, value, true
+}
+
+func splitRegistryArgs(value string) []string {
+	parts := strings.Split(value, ",")
+	args := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			args = append(args, p)
+		}
+	}
+	return args
+}
+
+// schemaCache hält bereits von der Platte gelesene ResultSchema-Dateien vor
+// - validateResult wird pro Analyzer-Call aufgerufen und soll nicht bei
+// jedem Request erneut dieselbe kleine JSON-Datei einlesen.
+var (
+	schemaMu    sync.Mutex
+	schemaCache = map[string][]string{}
+)
+
+type resultSchema struct {
+	Required []string `json:"required"`
+}
+
+// validateResult prüft, dass result (als JSON-Objekt) alle in schemaPath
+// unter "required" gelisteten Felder enthält - bewusst kein vollständiger
+// JSON-Schema-Validator, da dieses Repo dafür keine Bibliothek vendort,
+// sondern die Teilmenge, die für "hat der Worker die erwarteten Felder
+// geliefert" ausreicht.
+func validateResult(result interface{}, schemaPath string) error {
+	required, err := loadResultSchema(schemaPath)
+	if err != nil {
+		return err
+	}
+
+	asMap, ok := result.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("result is not a JSON object")
+	}
+
+	var missing []string
+	for _, field := range required {
+		if _, exists := asMap[field]; !exists {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required fields: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func loadResultSchema(path string) ([]string, error) {
+	schemaMu.Lock()
+	defer schemaMu.Unlock()
+
+	if required, ok := schemaCache[path]; ok {
+		return required, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read result schema %s: %w", path, err)
+	}
+	var schema resultSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid result schema %s: %w", path, err)
+	}
+
+	schemaCache[path] = schema.Required
+	return schema.Required, nil
+}
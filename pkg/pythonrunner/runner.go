@@ -4,13 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strconv"
-	"strings"
+	"sync"
 )
 
 // ScriptResult unverändert
@@ -20,6 +18,36 @@ type ScriptResult struct {
 	Err  string      `json:"err,omitempty"`
 }
 
+// defaultBackends hält die Worker-Prozesse hinter Run - lazy gebaut
+// (backendManager) statt als package-level var, damit findWorkingPython()
+// (ein exec.Command-Probe) nicht schon beim Laden des Pakets läuft, sondern
+// erst, wenn tatsächlich ein Backend gebraucht wird.
+var (
+	defaultBackends     *BackendManager
+	defaultBackendsOnce sync.Once
+)
+
+// backendManager liefert den package-weiten BackendManager, gebaut beim
+// ersten Zugriff mit dem per findWorkingPython() ermittelten Interpreter und
+// den zu diesem Zeitpunkt aktiven Analyzer-Definitionen (siehe registry.go).
+func backendManager() *BackendManager {
+	defaultBackendsOnce.Do(func() {
+		defaultBackends = NewBackendManager(findWorkingPython(), currentRegistry(), defaultSandbox())
+	})
+	return defaultBackends
+}
+
+// resetBackends verwirft alle laufenden Worker, nachdem die Analyzer-Registry
+// neu geladen wurde (siehe reloadRegistry) - ein Worker, der mit einer
+// inzwischen überholten Skriptpfad-/Args-Definition läuft, soll nicht
+// unbemerkt weiterlaufen. Ist der BackendManager noch gar nicht gebaut (kein
+// Call bisher), ist nichts zu tun.
+func resetBackends() {
+	if defaultBackends != nil {
+		defaultBackends.Reload(currentRegistry())
+	}
+}
+
 // RunMetadata ruft jetzt direkt exiftool -j auf.
 func RunMetadata(ctx context.Context, imgPath string) (interface{}, error) {
 	cmd := exec.CommandContext(ctx, "exiftool", "-j", imgPath)
@@ -38,218 +66,111 @@ func RunMetadata(ctx context.Context, imgPath string) (interface{}, error) {
 	return map[string]interface{}{}, nil
 }
 
-// RunCompression ruft analyze_compression.py auf und parst das JSON-Resultat.
-func RunCompression(ctx context.Context, imgPath string) (interface{}, error) {
-	script := filepath.Join("pythonScripts", "analyze_compression.py")
-
-	cmd := exec.CommandContext(ctx, "python3", script, imgPath)
+// Run führt den Analyzer name aus der aktiven Registry (siehe registry.go)
+// auf imgPath aus - der frühere exec/read/parse-Ablauf, den jede Run*-
+// Funktion einzeln dupliziert hatte, existiert jetzt nur noch hier: Run
+// begrenzt den Call auf Analyzer.Timeout (sofern gesetzt, sonst gilt der
+// aus ctx geerbte Deadline/Timeout unverändert), delegiert an den
+// BackendManager und validiert das Ergebnis gegen Analyzer.ResultSchema
+// (sofern gesetzt). Ein unbekannter Name ist ein Konfigurationsfehler, kein
+// Laufzeitfehler des Bildes, daher kein Retry.
+func Run(ctx context.Context, name, imgPath string) (interface{}, error) {
+	return RunStreaming(ctx, name, imgPath, nil)
+}
 
-	stdoutPipe, _ := cmd.StdoutPipe()
-	stderrPipe, _ := cmd.StderrPipe()
+// RunStreaming verhält sich wie Run, reicht aber jede Zwischenfortschritts-
+// meldung, die der Worker vor seiner abschließenden Antwort schreibt, als
+// geparstes JSON an onProgress weiter (onProgress darf nil sein). Damit kann
+// z.B. pipeline.RunAnalysisStream "artifacts: 40% blocks processed" einer
+// langlaufenden Stage an einen SSE-Client durchreichen, bevor das
+// Endergebnis vorliegt.
+func RunStreaming(ctx context.Context, name, imgPath string, onProgress func(interface{})) (interface{}, error) {
+	return RunWithDeps(ctx, name, imgPath, nil, onProgress)
+}
 
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("could not start compression script: %v", err)
+// RunWithDeps verhält sich wie RunStreaming, schickt aber deps - die
+// bereits abgeschlossenen Ergebnisse der in pipeline.AnalysisStage.
+// Dependencies gelisteten Stages - zusätzlich als Params mit. Ein Worker,
+// der ein solches Feld kennt (z.B. "advanced-artifacts", das "artifacts"
+// als Dependency führt), kann das schon berechnete Ergebnis wiederverwenden
+// statt es erneut zu berechnen; ein Worker, der deps nicht kennt, ignoriert
+// das zusätzliche Feld einfach.
+func RunWithDeps(ctx context.Context, name, imgPath string, deps map[string]interface{}, onProgress func(interface{})) (interface{}, error) {
+	analyzer, ok := lookupAnalyzer(name)
+	if !ok {
+		return nil, fmt.Errorf("pythonrunner: unknown analyzer %q", name)
+	}
+
+	callCtx := ctx
+	if analyzer.Timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, analyzer.Timeout)
+		defer cancel()
+	}
+
+	var rawProgress func(json.RawMessage)
+	if onProgress != nil {
+		rawProgress = func(raw json.RawMessage) {
+			var parsed interface{}
+			if err := json.Unmarshal(raw, &parsed); err == nil {
+				onProgress(parsed)
+			}
+		}
 	}
 
-	stdoutBytes, _ := io.ReadAll(stdoutPipe)
-	stderrBytes, _ := io.ReadAll(stderrPipe)
+	var params map[string]interface{}
+	if len(deps) > 0 {
+		params = map[string]interface{}{"deps": deps}
+	}
 
-	if err := cmd.Wait(); err != nil {
-		return nil, fmt.Errorf("compression analysis failed: %v\nStderr:\n%s", err, stderrBytes)
+	result, err := backendManager().CallWithParams(callCtx, name, imgPath, params, rawProgress)
+	if err != nil {
+		return nil, err
 	}
 
-	var result map[string]interface{}
-	if err := json.Unmarshal(stdoutBytes, &result); err != nil {
-		return nil, fmt.Errorf("JSON parsing failed: %v\nStdout:\n%s", err, stdoutBytes)
+	if analyzer.ResultSchema != "" {
+		if err := validateResult(result, analyzer.ResultSchema); err != nil {
+			return nil, fmt.Errorf("%s result failed validation: %w", name, err)
+		}
 	}
 	return result, nil
+}
 
+// RunCompression ruft den persistenten "compression"-Worker auf (siehe
+// BackendManager) statt wie zuvor jedes Mal einen neuen python3-Prozess für
+// analyze_compression.py zu starten.
+func RunCompression(ctx context.Context, imgPath string) (interface{}, error) {
+	return Run(ctx, "compression", imgPath)
 }
 
 func RunArtifacts(ctx context.Context, imagePath string) (interface{}, error) {
-	scriptPath := filepath.Join("pythonScripts", "detect-artifacts.py")
-
-	// Hier liegt der Fehler - wir müssen python3 als Befehl verwenden
-	// und das Skript als Argument übergeben
-	cmd := exec.CommandContext(ctx, "python3", scriptPath, imagePath)
-
-	stdoutPipe, _ := cmd.StdoutPipe()
-	stderrPipe, _ := cmd.StderrPipe()
-
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("could not start artifacts detection script: %v", err)
-	}
-
-	stdoutBytes, _ := io.ReadAll(stdoutPipe)
-	stderrBytes, _ := io.ReadAll(stderrPipe)
-
-	if err := cmd.Wait(); err != nil {
-		return nil, fmt.Errorf("artifacts detection failed: %v\nStderr:\n%s", err, stderrBytes)
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(stdoutBytes, &result); err != nil {
-		return nil, fmt.Errorf("JSON parsing failed: %v\nStdout:\n%s", err, stdoutBytes)
-	}
-	return result, nil
+	return Run(ctx, "artifacts", imagePath)
 }
 
-// Füge diese Funktion zu deinen bestehenden runner.go-Funktionen hinzu
-
-// RunColorBalance führt das Farbbalance-Analyse-Skript aus
+// RunColorBalance führt das Farbbalance-Analyse-Skript über den
+// persistenten "color-balance"-Worker aus.
 func RunColorBalance(ctx context.Context, imgPath string) (interface{}, error) {
-	script := filepath.Join("pythonScripts", "analyze_color_balance.py")
-
-	cmd := exec.CommandContext(ctx, "python3", script, imgPath)
-
-	stdoutPipe, _ := cmd.StdoutPipe()
-	stderrPipe, _ := cmd.StderrPipe()
-
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("could not start color balance script: %v", err)
-	}
-
-	stdoutBytes, _ := io.ReadAll(stdoutPipe)
-	stderrBytes, _ := io.ReadAll(stderrPipe)
-
-	if err := cmd.Wait(); err != nil {
-		return nil, fmt.Errorf("color balance analysis failed: %v\nStderr:\n%s", err, stderrBytes)
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(stdoutBytes, &result); err != nil {
-		return nil, fmt.Errorf("JSON parsing failed: %v\nStdout:\n%s", err, stdoutBytes)
-	}
-	return result, nil
+	return Run(ctx, "color-balance", imgPath)
 }
 
 func RunColorHistogram(ctx context.Context, imgPath string) (interface{}, error) {
-	script := filepath.Join("pythonScripts", "analyze_color_histogram.py")
-
-	cmd := exec.CommandContext(ctx, "python3", script, imgPath)
-
-	stdoutPipe, _ := cmd.StdoutPipe()
-	stderrPipe, _ := cmd.StderrPipe()
-
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("could not start color histogram script: %v", err)
-	}
-
-	stdoutBytes, _ := io.ReadAll(stdoutPipe)
-	stderrBytes, _ := io.ReadAll(stderrPipe)
-
-	if err := cmd.Wait(); err != nil {
-		return nil, fmt.Errorf("color histogram analysis failed: %v\nStderr:\n%s", err, stderrBytes)
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(stdoutBytes, &result); err != nil {
-		return nil, fmt.Errorf("JSON parsing failed: %v\nStdout:\n%s", err, stdoutBytes)
-	}
-	return result, nil
+	return Run(ctx, "color-histogram", imgPath)
 }
 
 func RunPixelAnalysis(ctx context.Context, imgPath string) (interface{}, error) {
-	script := filepath.Join("pythonScripts", "analyze_pixel.py")
-
-	cmd := exec.CommandContext(ctx, "python3", script, imgPath)
-
-	stdoutPipe, _ := cmd.StdoutPipe()
-	stderrPipe, _ := cmd.StderrPipe()
-
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("could not start pixel analysis script: %v", err)
-	}
-
-	stdoutBytes, _ := io.ReadAll(stdoutPipe)
-	stderrBytes, _ := io.ReadAll(stderrPipe)
-
-	if err := cmd.Wait(); err != nil {
-		return nil, fmt.Errorf("pixel analysis failed: %v\nStderr:\n%s", err, stderrBytes)
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(stdoutBytes, &result); err != nil {
-		return nil, fmt.Errorf("JSON parsing failed: %v\nStdout:\n%s", err, stdoutBytes)
-	}
-	return result, nil
+	return Run(ctx, "pixel-analysis", imgPath)
 }
 
 func RunAdvancedArtifacts(ctx context.Context, imagePath string) (interface{}, error) {
-	scriptPath := filepath.Join("pythonScripts", "advanced-artifacts.py")
-
-	cmd := exec.CommandContext(ctx, "python3", scriptPath, imagePath)
-
-	stdoutPipe, _ := cmd.StdoutPipe()
-	stderrPipe, _ := cmd.StderrPipe()
-
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("could not start advanced artifacts script: %v", err)
-	}
-
-	stdoutBytes, _ := io.ReadAll(stdoutPipe)
-	stderrBytes, _ := io.ReadAll(stderrPipe)
-
-	if err := cmd.Wait(); err != nil {
-		return nil, fmt.Errorf("advanced artifacts failed: %v\nStderr:\n%s", err, stderrBytes)
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(stdoutBytes, &result); err != nil {
-		return nil, fmt.Errorf("JSON parsing failed: %v\nStdout:\n%s", err, stdoutBytes)
-	}
-	return result, nil
+	return Run(ctx, "advanced-artifacts", imagePath)
 }
-func RunObjectCoherence(ctx context.Context, imagePath string) (interface{}, error) {
-	scriptPath := filepath.Join("pythonScripts", "analyze_coherence.py")
 
-	cmd := exec.CommandContext(ctx, "python3", scriptPath, imagePath)
-
-	stdoutPipe, _ := cmd.StdoutPipe()
-	stderrPipe, _ := cmd.StderrPipe()
-
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("could not start object coherence script: %v", err)
-	}
-
-	stdoutBytes, _ := io.ReadAll(stdoutPipe)
-	stderrBytes, _ := io.ReadAll(stderrPipe)
-
-	if err := cmd.Wait(); err != nil {
-		return nil, fmt.Errorf("object coherence analysis failed: %v\nStderr:\n%s", err, stderrBytes)
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(stdoutBytes, &result); err != nil {
-		return nil, fmt.Errorf("JSON parsing failed: %v\nStdout:\n%s", err, stdoutBytes)
-	}
-	return result, nil
+func RunObjectCoherence(ctx context.Context, imagePath string) (interface{}, error) {
+	return Run(ctx, "object-coherence", imagePath)
 }
 
 func RunLightingAnalysis(ctx context.Context, imagePath string) (interface{}, error) {
-	scriptPath := filepath.Join("pythonScripts", "analyze_lighting.py")
-
-	cmd := exec.CommandContext(ctx, "python3", scriptPath, imagePath)
-
-	stdoutPipe, _ := cmd.StdoutPipe()
-	stderrPipe, _ := cmd.StderrPipe()
-
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("could not start lighting analysis script: %v", err)
-	}
-
-	stdoutBytes, _ := io.ReadAll(stdoutPipe)
-	stderrBytes, _ := io.ReadAll(stderrPipe)
-
-	if err := cmd.Wait(); err != nil {
-		return nil, fmt.Errorf("lighting analysis failed: %v\nStderr:\n%s", err, stderrBytes)
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(stdoutBytes, &result); err != nil {
-		return nil, fmt.Errorf("JSON parsing failed: %v\nStdout:\n%s", err, stdoutBytes)
-	}
-	return result, nil
+	return Run(ctx, "lighting-analysis", imagePath)
 }
 
 // func RunAIModelPrediction(ctx context.Context, imgPath string) (interface{}, error) {
@@ -299,98 +220,6 @@ func RunLightingAnalysis(ctx context.Context, imagePath string) (interface{}, er
 //		return result, nil
 //	}
 
-func parseClassifyV6Output(output, imgPath string) (interface{}, error) {
-	log.Printf("DEBUG: Full classify-v6 output:\n%s", output)
-
-	lines := strings.Split(output, "\n")
-	var predLine string
-
-	// Find prediction line - flexiblere Suche
-	imageName := filepath.Base(imgPath)
-	log.Printf("DEBUG: Looking for image name: %s", imageName)
-
-	for _, line := range lines {
-		log.Printf("DEBUG: Checking line: %s", line)
-		if strings.Contains(line, "img:") {
-			predLine = line
-			log.Printf("DEBUG: Found prediction line: %s", predLine)
-			break
-		}
-	}
-
-	if predLine == "" {
-		log.Printf("DEBUG: No prediction line found in output")
-		return nil, fmt.Errorf("no prediction found in output")
-	}
-
-	// Parse: "img: KI-Bilder-erstellen.jpg pred: FAKE prob: 1.000 conf: 1.000"
-	parts := strings.Fields(predLine)
-	log.Printf("DEBUG: Parsed fields: %v", parts)
-
-	if len(parts) < 8 {
-		return nil, fmt.Errorf("invalid prediction format: %s (parts: %d)", predLine, len(parts))
-	}
-
-	var prediction string
-	var probability, confidence float64
-	var err error
-
-	// Robusteres Parsing
-	for i, part := range parts {
-		switch part {
-		case "pred:":
-			if i+1 < len(parts) {
-				prediction = parts[i+1]
-				log.Printf("DEBUG: Found prediction: %s", prediction)
-			}
-		case "prob:":
-			if i+1 < len(parts) {
-				if probability, err = strconv.ParseFloat(parts[i+1], 64); err != nil {
-					log.Printf("DEBUG: Error parsing probability: %v", err)
-					return nil, fmt.Errorf("invalid probability value: %v", err)
-				}
-				log.Printf("DEBUG: Found probability: %f", probability)
-			}
-		case "conf:":
-			if i+1 < len(parts) {
-				if confidence, err = strconv.ParseFloat(parts[i+1], 64); err != nil {
-					log.Printf("DEBUG: Error parsing confidence: %v", err)
-					return nil, fmt.Errorf("invalid confidence value: %v", err)
-				}
-				log.Printf("DEBUG: Found confidence: %f", confidence)
-			}
-		}
-	}
-
-	if prediction == "" {
-		return nil, fmt.Errorf("could not parse prediction from: %s", predLine)
-	}
-
-	// Convert to expected format
-	isFake := prediction == "FAKE"
-	authenticityScore := probability
-	if isFake {
-		// Für FAKE: higher prob means more AI-like, so lower authenticity
-		authenticityScore = 1.0 - probability
-	}
-
-	result := map[string]interface{}{
-		"prediction":  strings.ToLower(prediction),
-		"probability": probability,
-		"confidence":  confidence,
-		"model_type":  "ensemble_efficientnetv2",
-		"ai_model_analysis": map[string]interface{}{
-			"predicted_class":    strings.ToLower(prediction),
-			"confidence_score":   confidence,
-			"is_ai_generated":    isFake,
-			"authenticity_score": authenticityScore,
-		},
-	}
-
-	log.Printf("DEBUG: Final parsed result: %+v", result)
-	return result, nil
-}
-
 // // Helper function to get the correct Python command
 // func getPythonCommand() string {
 // 	// Prüfe Windows Virtual Environment zuerst
@@ -450,27 +279,12 @@ func findWorkingPython() string {
 	return "python3" // Fallback
 }
 
+// RunAIModelPrediction ruft den persistenten "ai-model"-Worker auf - das
+// ensemble1-Checkpoint bleibt dadurch über Requests hinweg geladen, statt
+// wie zuvor bei jedem Aufruf per classify-v6.py neu von der Platte
+// eingelesen zu werden. Der Worker liefert das von parseClassifyV6Output
+// früher aus dem CLI-Textformat rekonstruierte JSON bereits direkt als
+// Payload.
 func RunAIModelPrediction(ctx context.Context, imgPath string) (interface{}, error) {
-	scriptPath := filepath.Join("ai-analyse", "new_analysis", "classify-v6.py")
-	modelsDir := filepath.Join("ai-analyse", "new_analysis", "ensemble1")
-
-	pythonCmd := findWorkingPython()
-
-	cmd := exec.CommandContext(ctx, pythonCmd, scriptPath,
-		"--models", modelsDir,
-		imgPath)
-
-	cmd.Dir = "."
-
-	output, err := cmd.Output()
-	if err != nil {
-		if exitError, ok := err.(*exec.ExitError); ok {
-			log.Printf("AI model script stderr: %s", string(exitError.Stderr))
-		}
-		return nil, fmt.Errorf("AI model prediction failed: %v", err)
-	}
-
-	log.Printf("AI model raw output: %s", string(output))
-
-	return parseClassifyV6Output(string(output), imgPath)
+	return Run(ctx, "ai-model", imgPath)
 }
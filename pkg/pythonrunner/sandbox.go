@@ -0,0 +1,228 @@
+package pythonrunner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SandboxMode wählt, wie stark ein Worker-Prozess (siehe backend.start)
+// gegenüber dem Host isoliert wird - von "none" (unverändertes
+// exec.Command wie vor dieser Datei) bis "container" (der komplette
+// Prozess läuft in einem Docker/Podman-Container ohne Netzwerk und mit
+// read-only Root-FS). Ein Bild, das einen pathologischen Pfad in opencv
+// auslöst (z.B. eine Allokation, die nie zurückkehrt), soll höchstens den
+// eigenen Worker mitreißen, nie den Go-Server selbst.
+type SandboxMode string
+
+const (
+	// SandboxNone startet den Worker unverändert - Dev-Default, da
+	// prlimit/Namespaces/Container lokal oft fehlen oder Rechte
+	// voraussetzen, die ein Entwickler-Laptop nicht hat.
+	SandboxNone SandboxMode = "none"
+	// SandboxRlimit begrenzt den Worker-Prozess nach dem Start per
+	// prlimit(2) (RLIMIT_AS/RLIMIT_CPU/RLIMIT_FSIZE, siehe
+	// sandbox_linux.go) - braucht keine Extra-Berechtigung, isoliert aber
+	// weder Filesystem noch Netzwerk.
+	SandboxRlimit SandboxMode = "rlimit"
+	// SandboxNamespace ergänzt SandboxRlimit um ein eigenes PID-/
+	// Mount-Namespace, in dem nur SandboxConfig.MountDir (read-only
+	// neu gemountet) sichtbar bleibt - nur unter Linux verfügbar, siehe
+	// sandbox_linux.go.
+	SandboxNamespace SandboxMode = "namespace"
+	// SandboxContainer startet den Worker statt direkt über
+	// ContainerRuntime (docker/podman) mit --network=none, --read-only
+	// und MountDir read-only gemountet - teuerster, aber am stärksten
+	// isolierter Modus; einzige Option, die keine Namespace-Rechte im
+	// Host-Kernel voraussetzt (der Runtime-Daemon übernimmt das).
+	SandboxContainer SandboxMode = "container"
+)
+
+// SandboxConfig steuert SandboxMode und dessen Parameter - siehe
+// sandboxConfigFromEnv für die per Environment-Variable gesetzten Werte,
+// analog zu REDIS_ADDR/ALERT_WEBHOOK_URL in cmd/server/main.go.
+type SandboxConfig struct {
+	Mode SandboxMode
+
+	// MaxMemoryBytes/MaxCPUSeconds/MaxFileSizeBytes setzen RLIMIT_AS,
+	// RLIMIT_CPU bzw. RLIMIT_FSIZE für SandboxRlimit/SandboxNamespace - 0
+	// heißt "kein Limit setzen", also Kernel-Default behalten.
+	MaxMemoryBytes   uint64
+	MaxCPUSeconds    uint64
+	MaxFileSizeBytes uint64
+
+	// MountDir ist das einzige Verzeichnis, das ein Worker unter
+	// SandboxNamespace/SandboxContainer lesend sehen soll - der Ort, an
+	// den utils.CreateSecureTempFile Uploads schreibt (Default /tmp).
+	// Anders als das im Backlog skizzierte "ein exec pro Bild, nur dessen
+	// Tempdir gemountet" lässt sich das hier nicht pro Call neu mounten:
+	// ein Worker bleibt über viele Requests mit wechselndem imgPath am
+	// Leben (siehe backend_manager.go), daher wird beim Start einmal der
+	// ganze Tempfile-Root gemountet statt pro Aufruf eine einzelne Datei.
+	MountDir string
+
+	// ContainerRuntime ist "docker" oder "podman", ContainerImage das
+	// Image, das pythonCmd darin ausführt - muss denselben Interpreter
+	// und dieselben Pakete enthalten wie ein lokal installierter
+	// pythonCmd, nur SandboxContainer verwendet beide Felder.
+	ContainerRuntime string
+	ContainerImage   string
+}
+
+const (
+	defaultSandboxMountDir  = "/tmp"
+	defaultContainerRuntime = "docker"
+)
+
+// sandboxConfigFromEnv liest PYTHON_SANDBOX_MODE und die zugehörigen
+// Parameter aus der Umgebung - fehlt PYTHON_SANDBOX_MODE oder ist sie
+// keinem SandboxMode zuzuordnen, bleibt SandboxNone aktiv (Worker starten
+// wie vor dieser Datei).
+func sandboxConfigFromEnv() SandboxConfig {
+	cfg := SandboxConfig{
+		Mode:             SandboxMode(os.Getenv("PYTHON_SANDBOX_MODE")),
+		MountDir:         defaultSandboxMountDir,
+		ContainerRuntime: defaultContainerRuntime,
+	}
+	switch cfg.Mode {
+	case SandboxRlimit, SandboxNamespace, SandboxContainer:
+	default:
+		cfg.Mode = SandboxNone
+	}
+
+	if v := os.Getenv("PYTHON_SANDBOX_MOUNT_DIR"); v != "" {
+		cfg.MountDir = v
+	}
+	if v := os.Getenv("PYTHON_SANDBOX_MAX_MEMORY_MB"); v != "" {
+		if n, err := strconv.ParseUint(v, 10, 64); err == nil {
+			cfg.MaxMemoryBytes = n * 1024 * 1024
+		}
+	}
+	if v := os.Getenv("PYTHON_SANDBOX_MAX_CPU_SECONDS"); v != "" {
+		if n, err := strconv.ParseUint(v, 10, 64); err == nil {
+			cfg.MaxCPUSeconds = n
+		}
+	}
+	if v := os.Getenv("PYTHON_SANDBOX_MAX_FILE_SIZE_MB"); v != "" {
+		if n, err := strconv.ParseUint(v, 10, 64); err == nil {
+			cfg.MaxFileSizeBytes = n * 1024 * 1024
+		}
+	}
+	if v := os.Getenv("PYTHON_SANDBOX_CONTAINER_RUNTIME"); v != "" {
+		cfg.ContainerRuntime = v
+	}
+	if v := os.Getenv("PYTHON_SANDBOX_CONTAINER_IMAGE"); v != "" {
+		cfg.ContainerImage = v
+	}
+	return cfg
+}
+
+// Sandbox wendet eine SandboxConfig auf den Start eines Worker-Prozesses an
+// (siehe backend.start): Command liefert den tatsächlich zu startenden
+// exec.Cmd (für SandboxContainer ein komplett anderer Pfad/Args, sonst
+// pythonCmd/args selbst plus ggf. SysProcAttr), AfterStart wendet
+// Limits an, die erst auf die laufende PID gesetzt werden können
+// (prlimit, siehe sandbox_linux.go).
+type Sandbox struct {
+	cfg SandboxConfig
+}
+
+// NewSandbox baut einen Sandbox aus cfg.
+func NewSandbox(cfg SandboxConfig) *Sandbox {
+	return &Sandbox{cfg: cfg}
+}
+
+var (
+	defaultSandboxOnce sync.Once
+	defaultSandboxVal  *Sandbox
+)
+
+// defaultSandbox liegt hinter sandboxConfigFromEnv, analog zu
+// currentRegistry()/backendManager() - gebaut beim ersten Zugriff, damit
+// ein Test PYTHON_SANDBOX_MODE vor dem ersten Call noch setzen kann.
+func defaultSandbox() *Sandbox {
+	defaultSandboxOnce.Do(func() {
+		defaultSandboxVal = NewSandbox(sandboxConfigFromEnv())
+	})
+	return defaultSandboxVal
+}
+
+// Command baut den exec.Cmd, mit dem ein Worker für pythonCmd/args
+// tatsächlich gestartet werden soll. Der Aufrufer (backend.start) muss nur
+// noch Stdin/Stdout/Stderr verdrahten und Start() rufen; nach dem Start
+// noch AfterStart(cmd) aufrufen, damit prlimit-basierte Modi überhaupt
+// etwas bewirken.
+func (s *Sandbox) Command(pythonCmd string, args []string) *exec.Cmd {
+	switch s.cfg.Mode {
+	case SandboxContainer:
+		return s.wrapContainer(pythonCmd, args)
+	case SandboxNamespace:
+		return s.wrapNamespace(pythonCmd, args)
+	default:
+		return exec.Command(pythonCmd, args...)
+	}
+}
+
+// AfterStart setzt Limits, die nur auf eine bereits laufende PID angewendet
+// werden können (SandboxRlimit/SandboxNamespace, siehe applyRlimits in
+// sandbox_linux.go) - für SandboxNone/SandboxContainer ein No-Op, da
+// Container-Limits bereits beim docker/podman-"run" in wrapContainer
+// gesetzt werden.
+func (s *Sandbox) AfterStart(cmd *exec.Cmd) error {
+	switch s.cfg.Mode {
+	case SandboxRlimit, SandboxNamespace:
+		return applyRlimits(cmd.Process.Pid, s.cfg)
+	default:
+		return nil
+	}
+}
+
+// wrapContainer ersetzt den direkten Interpreter-Aufruf durch
+// "<runtime> run --rm -i --network=none --read-only -v <MountDir>:/in:ro
+// <image> <pythonCmd> <args...>" - pythonCmd/args laufen dadurch
+// vollständig innerhalb des Containers, ohne Netzwerk und mit
+// schreibgeschütztem Root-FS; einzig MountDir ist (read-only) als /in
+// sichtbar, wo die Upload-Tempfiles liegen, die imgPath referenziert.
+func (s *Sandbox) wrapContainer(pythonCmd string, args []string) *exec.Cmd {
+	runtimeArgs := []string{
+		"run", "--rm", "-i",
+		"--network=none",
+		"--read-only",
+		"-v", fmt.Sprintf("%s:/in:ro", s.cfg.MountDir),
+	}
+	if s.cfg.MaxMemoryBytes > 0 {
+		runtimeArgs = append(runtimeArgs, "--memory", strconv.FormatUint(s.cfg.MaxMemoryBytes, 10))
+	}
+	if s.cfg.MaxCPUSeconds > 0 {
+		runtimeArgs = append(runtimeArgs, "--cpus", "1")
+	}
+	runtimeArgs = append(runtimeArgs, s.cfg.ContainerImage, pythonCmd)
+	runtimeArgs = append(runtimeArgs, args...)
+	return exec.Command(s.cfg.ContainerRuntime, runtimeArgs...)
+}
+
+// TranslatePath übersetzt einen Host-Pfad unterhalb von MountDir in den Pfad,
+// unter dem ihn ein SandboxContainer-Worker sieht (wrapContainer mountet
+// MountDir als /in) - backend.call muss imgPath hierdurch schicken, bevor es
+// in den Request geschrieben wird, sonst sucht der Worker im Container nach
+// einem Host-Pfad, den es dort gar nicht gibt. Für alle anderen Modi läuft
+// der Worker im selben Mount-Namespace wie der Go-Prozess (SandboxNamespace
+// bindet MountDir lediglich read-only neu ein, siehe wrapNamespace) und
+// sieht denselben Pfad, hostPath bleibt also unverändert. Liegt hostPath
+// nicht unter MountDir, wird er ebenfalls unverändert zurückgegeben - der
+// anschließende Fehlschlag im Container ist dann aussagekräftiger als ein
+// stillschweigend falsch übersetzter Pfad.
+func (s *Sandbox) TranslatePath(hostPath string) string {
+	if s.cfg.Mode != SandboxContainer {
+		return hostPath
+	}
+	rel, err := filepath.Rel(s.cfg.MountDir, hostPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return hostPath
+	}
+	return filepath.Join("/in", rel)
+}
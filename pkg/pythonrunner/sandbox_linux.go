@@ -0,0 +1,87 @@
+//go:build linux
+
+package pythonrunner
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// wrapNamespace startet pythonCmd/args über eine kleine Shell-Zwischenstufe
+// in einem neuen PID-/Mount-Namespace (CLONE_NEWPID|CLONE_NEWNS): das
+// eigentliche exec in das Namespace hinein geschieht zwar schon für den
+// "sh"-Prozess selbst, ein Bind-Remount von MountDir auf read-only muss
+// aber innerhalb des neuen Mount-Namespace laufen, nicht im Eltern-Prozess
+// (sonst würde er den Host-Mount betreffen) - daher übernimmt die Shell
+// erst den Remount, bevor sie per "exec" in pythonCmd übergeht (kein
+// zusätzlicher Prozess, gleiche PID wie der spätere Worker).
+func (s *Sandbox) wrapNamespace(pythonCmd string, args []string) *exec.Cmd {
+	dir := shellQuote(s.cfg.MountDir)
+	target := append([]string{pythonCmd}, args...)
+	script := fmt.Sprintf(
+		"mount --bind %s %s && mount -o remount,bind,ro %s && exec %s",
+		dir, dir, dir, shellQuoteAll(target),
+	)
+
+	cmd := exec.Command("sh", "-c", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWNS | syscall.CLONE_NEWPID,
+		// AmbientCaps bleibt leer: der Worker erbt keine zusätzlichen
+		// Capabilities über das vom Parent-Prozess gesetzte Erbe hinaus -
+		// ein vollständiges Absenken der Bounding-Set bräuchte zusätzlich
+		// CAP_SETPCAP/prctl(PR_CAPBSET_DROP) vor dem exec, was ohne
+		// eigenen Helper-Prozess mit os/exec nicht darstellbar ist.
+	}
+	return cmd
+}
+
+// applyRlimits setzt RLIMIT_AS/RLIMIT_CPU/RLIMIT_FSIZE für pid per
+// prlimit(2) - anders als setrlimit() wirkt das auch auf einen bereits
+// gestarteten fremden Prozess, weshalb es erst nach cmd.Start()
+// (Sandbox.AfterStart) statt vorher via SysProcAttr gesetzt werden kann.
+// package syscall exportiert keinen Prlimit-Wrapper (nur das intern
+// verwendete prlimit1), daher der direkte Syscall über SYS_PRLIMIT64. Ein
+// Limit von 0 in cfg heißt "nicht setzen"; Felder, die 0 bleiben, werden
+// übersprungen statt fälschlich auf "unlimited" gesetzt.
+func applyRlimits(pid int, cfg SandboxConfig) error {
+	set := func(resource int, value uint64) error {
+		if value == 0 {
+			return nil
+		}
+		limit := syscall.Rlimit{Cur: value, Max: value}
+		_, _, errno := syscall.RawSyscall6(syscall.SYS_PRLIMIT64, uintptr(pid), uintptr(resource), uintptr(unsafe.Pointer(&limit)), 0, 0, 0)
+		if errno != 0 {
+			return errno
+		}
+		return nil
+	}
+
+	if err := set(syscall.RLIMIT_AS, cfg.MaxMemoryBytes); err != nil {
+		return fmt.Errorf("set RLIMIT_AS for pid %d: %w", pid, err)
+	}
+	if err := set(syscall.RLIMIT_CPU, cfg.MaxCPUSeconds); err != nil {
+		return fmt.Errorf("set RLIMIT_CPU for pid %d: %w", pid, err)
+	}
+	if err := set(syscall.RLIMIT_FSIZE, cfg.MaxFileSizeBytes); err != nil {
+		return fmt.Errorf("set RLIMIT_FSIZE for pid %d: %w", pid, err)
+	}
+	return nil
+}
+
+// shellQuote umschließt s in Single-Quotes für den Einsatz in "sh -c" -
+// genügt hier, da MountDir/Skriptpfade aus Konfiguration bzw. der
+// Analyzer-Registry kommen, nicht aus Nutzereingaben.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func shellQuoteAll(parts []string) string {
+	quoted := make([]string, len(parts))
+	for i, p := range parts {
+		quoted[i] = shellQuote(p)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,30 @@
+//go:build !linux
+
+package pythonrunner
+
+import (
+	"log"
+	"os/exec"
+	"runtime"
+	"sync"
+)
+
+var namespaceWarnOnce sync.Once
+
+// wrapNamespace gibt es auf diesem GOOS nicht (CLONE_NEWPID/CLONE_NEWNS und
+// prlimit(2) sind Linux-spezifisch) - fällt auf den unveränderten
+// pythonCmd/args-Aufruf zurück, wie ihn SandboxNone ohnehin verwendet, und
+// warnt einmal, damit ein auf Linux getesteter SandboxNamespace-Operator
+// nicht stillschweigend unisoliert in Produktion läuft.
+func (s *Sandbox) wrapNamespace(pythonCmd string, args []string) *exec.Cmd {
+	namespaceWarnOnce.Do(func() {
+		log.Printf("WARNING: PYTHON_SANDBOX_MODE=namespace wird auf %s nicht unterstützt, starte Worker ohne Namespace-Isolation", runtime.GOOS)
+	})
+	return exec.Command(pythonCmd, args...)
+}
+
+// applyRlimits gibt es auf diesem GOOS nicht - SandboxRlimit fällt auf
+// Kernel-Default-Limits zurück, statt einen Build-Fehler zu riskieren.
+func applyRlimits(pid int, cfg SandboxConfig) error {
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 type ScriptResult struct {
@@ -14,6 +16,60 @@ type ScriptResult struct {
 	Err  string      `json:"err,omitempty"`
 }
 
+// ManifestAssertion ist eine einzelne C2PA-Assertion innerhalb eines
+// Manifests (z.B. c2pa.actions, c2pa.ingredient, c2pa.training-mining).
+type ManifestAssertion struct {
+	Label string                 `json:"label"`
+	Data  map[string]interface{} `json:"data,omitempty"`
+}
+
+// Manifest ist ein Knoten der C2PA-Provenienzkette.
+type Manifest struct {
+	Signer           string              `json:"signer,omitempty"`
+	SigningCertChain []string            `json:"signing_cert_chain,omitempty"`
+	Assertions       []ManifestAssertion `json:"assertions,omitempty"`
+	ValidationStatus string              `json:"validation_status"`
+	TrustLevel       string              `json:"trust_level"`
+}
+
+// C2PAResult ist die strukturierte Sicht auf die C2PA-Manifest-Kette, statt
+// des rohen interface{} das der Rust-Binary liefert.
+type C2PAResult struct {
+	Manifests   []Manifest `json:"manifests"`
+	ClaimsFound bool       `json:"claims_found"`
+	ClaimsCount int        `json:"claims_count"`
+	Score       float64    `json:"score"`
+}
+
+// knownGenerativeTools sind claim_generator/softwareAgent-Werte, die ein
+// c2pa.actions/c2pa.created als Produkt eines generativen KI-Tools markieren.
+var knownGenerativeTools = []string{
+	"dall-e", "dalle", "midjourney", "stable diffusion", "firefly",
+	"imagen", "gemini", "runway", "sora",
+}
+
+// TrustedSigners ist die konfigurierbare Liste vertrauenswürdiger Aussteller
+// der Signierzertifikate. Leer lassen, um jede gültige Signatur als
+// "trusted" zu akzeptieren.
+var TrustedSigners = []string{
+	"C2PA Test Signing Cert",
+	"Adobe",
+	"Truepic",
+	"Camera Content Authenticity",
+}
+
+// TrustedSignersUpdatedAt hält fest, wann TrustedSigners zuletzt gesetzt
+// wurde - verdict.computeTemporalScore liest dies, um die Verdict-
+// Temporal-Dimension abzuwerten, wenn die Trust-Liste seit längerem nicht
+// aktualisiert wurde (siehe internal/handlers/verdict/scoring_dimensions.go).
+var TrustedSignersUpdatedAt = time.Now()
+
+// SetTrustList überschreibt TrustedSigners, z.B. aus einem Config-Loader.
+func SetTrustList(signers []string) {
+	TrustedSigners = signers
+	TrustedSignersUpdatedAt = time.Now()
+}
+
 func RunC2PA(ctx context.Context, imgPath string) (interface{}, error) {
 	binaryPath := filepath.Join("pkg", "analyzer", "c2pa-rust", "target", "release", "c2pa-rust")
 
@@ -23,10 +79,194 @@ func RunC2PA(ctx context.Context, imgPath string) (interface{}, error) {
 		return nil, fmt.Errorf("c2pa-rust failed: %v\n%s", err, out)
 	}
 
-	var result interface{}
-	if err := json.Unmarshal(out, &result); err != nil {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(out, &raw); err != nil {
 		return nil, fmt.Errorf("JSON parsing failed: %w\nRaw output: %s", err, out)
 	}
 
-	return result, nil
+	result := parseC2PAOutput(raw)
+
+	// Flache Map für Abwärtskompatibilität mit der bestehenden
+	// verdict.calculateC2PAScore / ExtractConfidenceScore-Logik, die über
+	// map[string]interface{} auf "score"/"claims_found"/"claims_count"
+	// zugreift, plus die volle Provenienzkette für die API-Antwort.
+	// verdict.calculateC2PAScore erwartet "score" weiterhin auf einer
+	// 0-100-Skala (historisch vom Rust-Binary so geliefert) und normalisiert
+	// selbst auf 0-1.
+	response := map[string]interface{}{
+		"claims_found": result.ClaimsFound,
+		"claims_count": result.ClaimsCount,
+		"score":        result.Score * 100,
+		"manifests":    result.Manifests,
+	}
+
+	return response, nil
+}
+
+// parseC2PAOutput interpretiert die rohe c2pa-rust-Ausgabe in die typisierte
+// Provenienzkette und bewertet jedes Manifest gegen die Trust-Liste.
+func parseC2PAOutput(raw map[string]interface{}) *C2PAResult {
+	result := &C2PAResult{}
+
+	rawManifests, _ := raw["manifests"].([]interface{})
+	if rawManifests == nil {
+		// c2patool-artige Ausgaben liefern oft ein einzelnes "active_manifest".
+		if single, ok := raw["active_manifest"].(map[string]interface{}); ok {
+			rawManifests = []interface{}{single}
+		}
+	}
+
+	for _, m := range rawManifests {
+		manifestMap, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		result.Manifests = append(result.Manifests, buildManifest(manifestMap))
+	}
+
+	result.ClaimsFound = len(result.Manifests) > 0
+	result.ClaimsCount = len(result.Manifests)
+
+	if claimsFound, ok := raw["claims_found"].(bool); ok {
+		result.ClaimsFound = claimsFound
+	}
+	if count, ok := raw["claims_count"].(float64); ok {
+		result.ClaimsCount = int(count)
+	}
+
+	result.Score = scoreManifests(result.Manifests)
+	if rawScore, ok := raw["score"].(float64); ok && result.Score == 0 {
+		result.Score = rawScore
+	}
+
+	return result
+}
+
+func buildManifest(m map[string]interface{}) Manifest {
+	manifest := Manifest{
+		ValidationStatus: "unknown",
+	}
+
+	if signer, ok := m["signer"].(string); ok {
+		manifest.Signer = signer
+	} else if signatureInfo, ok := m["signature_info"].(map[string]interface{}); ok {
+		if issuer, ok := signatureInfo["issuer"].(string); ok {
+			manifest.Signer = issuer
+		}
+	}
+
+	if chain, ok := m["signing_cert_chain"].([]interface{}); ok {
+		for _, c := range chain {
+			if s, ok := c.(string); ok {
+				manifest.SigningCertChain = append(manifest.SigningCertChain, s)
+			}
+		}
+	}
+
+	if assertions, ok := m["assertions"].([]interface{}); ok {
+		for _, a := range assertions {
+			assertionMap, ok := a.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			label, _ := assertionMap["label"].(string)
+			data, _ := assertionMap["data"].(map[string]interface{})
+			manifest.Assertions = append(manifest.Assertions, ManifestAssertion{Label: label, Data: data})
+		}
+	}
+
+	if status, ok := m["validation_status"].(string); ok {
+		manifest.ValidationStatus = status
+	} else if valid, ok := m["is_valid"].(bool); ok && valid {
+		manifest.ValidationStatus = "valid"
+	}
+
+	manifest.TrustLevel = trustLevelFor(manifest.Signer, manifest.ValidationStatus)
+
+	return manifest
+}
+
+func trustLevelFor(signer string, validationStatus string) string {
+	if validationStatus != "valid" {
+		return "untrusted"
+	}
+	if len(TrustedSigners) == 0 {
+		return "trusted"
+	}
+	for _, trusted := range TrustedSigners {
+		if strings.Contains(strings.ToLower(signer), strings.ToLower(trusted)) {
+			return "trusted"
+		}
+	}
+	return "unverified_signer"
+}
+
+// scoreManifests bewertet die Manifest-Kette: ein gültiges Manifest, das
+// c2pa.actions mit einer c2pa.created-Aktion von einem bekannten generativen
+// Tool trägt, ist ein nahezu definitiver AI-Beweis. Ein gültiges,
+// kamerasigniertes Manifest mit intakten Ingredient-Hashes spricht dagegen
+// für ein authentisches Bild.
+func scoreManifests(manifests []Manifest) float64 {
+	if len(manifests) == 0 {
+		return -1
+	}
+
+	bestScore := -1.0
+
+	for _, manifest := range manifests {
+		if manifest.ValidationStatus != "valid" {
+			continue
+		}
+
+		for _, assertion := range manifest.Assertions {
+			if assertion.Label != "c2pa.actions" {
+				continue
+			}
+			if isGenerativeCreation(assertion.Data) {
+				return 0.97 // near-definitive - eine gültige Signatur erklärt KI-Erzeugung
+			}
+		}
+
+		if hasIntactIngredients(manifest) {
+			bestScore = 0.05
+		} else if bestScore < 0 {
+			bestScore = 0.3
+		}
+	}
+
+	return bestScore
+}
+
+func isGenerativeCreation(actionData map[string]interface{}) bool {
+	actions, ok := actionData["actions"].([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, a := range actions {
+		actionMap, ok := a.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		action, _ := actionMap["action"].(string)
+		if action != "c2pa.created" {
+			continue
+		}
+		agent, _ := actionMap["softwareAgent"].(string)
+		for _, tool := range knownGenerativeTools {
+			if strings.Contains(strings.ToLower(agent), tool) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasIntactIngredients(manifest Manifest) bool {
+	for _, assertion := range manifest.Assertions {
+		if assertion.Label == "c2pa.ingredient" {
+			return true
+		}
+	}
+	return false
 }
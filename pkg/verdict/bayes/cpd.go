@@ -0,0 +1,74 @@
+// Package bayes implementiert eine kleine Bayes'sche Netzstruktur zur Fusion
+// von Detektor-Scores: ein Wurzelknoten IsAIGenerated mit Prior P(AI), und
+// ein Kindknoten pro Detektor, dessen CPD P(score | AI) und
+// P(score | Authentic) als Histogramm über diskretisierte Score-Bins
+// gespeichert ist. BayesFusion.Combine (siehe model.go) ersetzt damit den
+// handgewichteten Summen-Ansatz aus verdict.computeVerdict durch eine
+// Posterior-Berechnung, in die C2PA/Metadata als starke Likelihood statt als
+// hartkodierter Kurzschluss eingeht.
+package bayes
+
+// NumBins ist die Anzahl gleich breiter Bins, in die ein Score aus [0,1] für
+// die CPD-Histogramme diskretisiert wird.
+const NumBins = 10
+
+// laplaceFloor verhindert eine Zero-Likelihood für einen in den
+// Trainingsdaten nie beobachteten Bin - ein einzelner nie gesehener Bin
+// würde sonst die gesamte Posterior unabhängig von allen anderen Knoten auf
+// 0 ziehen.
+const laplaceFloor = 1e-6
+
+// NodeCPD ist die bedingte Verteilung eines einzelnen Detektor-Knotens:
+// GivenAI[i]/GivenAuthentic[i] ist P(score in Bin i | AI) bzw.
+// P(score in Bin i | Authentic), jeweils auf 1 normalisiert.
+type NodeCPD struct {
+	GivenAI        [NumBins]float64
+	GivenAuthentic [NumBins]float64
+}
+
+// bin ordnet score seinem Histogramm-Bin zu, score wird dafür auf [0,1]
+// geklemmt.
+func bin(score float64) int {
+	if score < 0 {
+		score = 0
+	}
+	if score >= 1 {
+		score = 0.999999
+	}
+	idx := int(score * NumBins)
+	if idx >= NumBins {
+		idx = NumBins - 1
+	}
+	return idx
+}
+
+// likelihood liefert P(score | ai) aus dieser CPD, mit laplaceFloor als
+// Untergrenze gegen nie beobachtete Bins.
+func (cpd NodeCPD) likelihood(score float64, ai bool) float64 {
+	hist := cpd.GivenAuthentic
+	if ai {
+		hist = cpd.GivenAI
+	}
+
+	p := hist[bin(score)]
+	if p < laplaceFloor {
+		return laplaceFloor
+	}
+	return p
+}
+
+// normalize skaliert hist so, dass seine Einträge sich zu 1 summieren -
+// no-op, falls hist bereits komplett leer ist (Summe 0).
+func normalize(hist [NumBins]float64) [NumBins]float64 {
+	var sum float64
+	for _, v := range hist {
+		sum += v
+	}
+	if sum == 0 {
+		return hist
+	}
+	for i := range hist {
+		hist[i] /= sum
+	}
+	return hist
+}
@@ -0,0 +1,71 @@
+package bayes
+
+import "math"
+
+// defaultPrior ist P(AI) ohne jede Beobachtung - bewusst neutral gewählt,
+// analog zum Prior in pkg/analyzer/fusion (siehe fusion.currentWeights).
+const defaultPrior = 0.5
+
+// gaussianHistogram baut ein auf 1 normalisiertes NumBins-Histogramm mit
+// Peak um center (in [0,1]) und Streuung spread - Hilfsfunktion, um die
+// eingebauten Default-CPDs unten lesbar zu halten, statt 2x10 Fließkomma-
+// Literale pro Detektor von Hand abzutippen. Ein echtes, aus Kalibrierungs-
+// Läufen gefittetes Modell ersetzt dies über Fit (siehe train.go) und
+// LoadModel (siehe persistence.go).
+func gaussianHistogram(center, spread float64) [NumBins]float64 {
+	var hist [NumBins]float64
+	var sum float64
+	for i := 0; i < NumBins; i++ {
+		mid := (float64(i) + 0.5) / NumBins
+		d := (mid - center) / spread
+		hist[i] = math.Exp(-0.5 * d * d)
+		sum += hist[i]
+	}
+	for i := range hist {
+		hist[i] /= sum
+	}
+	return hist
+}
+
+// defaultNode baut eine NodeCPD, bei der ein hoher Score für AI spricht
+// (aiCenter > authenticCenter) - das entspricht der Konvention der
+// calculateXScore-Funktionen in internal/handlers/verdict/scores.go, wo 1.0
+// immer "AI" und 0.0 immer "authentisch" bedeutet.
+func defaultNode(aiCenter, authenticCenter, spread float64) NodeCPD {
+	return NodeCPD{
+		GivenAI:        gaussianHistogram(aiCenter, spread),
+		GivenAuthentic: gaussianHistogram(authenticCenter, spread),
+	}
+}
+
+// DefaultModel liefert das eingebaute Fallback-Modell, das verwendet wird,
+// solange kein trainiertes Modell unter pkg/verdict/bayes/models/ existiert
+// (siehe LoadModel). Die Peaks/Spreads sind bewusst konservative Schätzungen
+// aus der Semantik der jeweiligen calculateXScore-Funktion, keine gefitteten
+// Werte - sobald ein Operator fit-bayes-model über echte Kalibrierungsläufe
+// ausführt, ersetzt das persistierte Modell diese Defaults.
+func DefaultModel() *Model {
+	return &Model{
+		Prior: defaultPrior,
+		Nodes: map[string]NodeCPD{
+			// Starke, nahezu binäre Indikatoren - CPDs entsprechend eng um
+			// die Extreme konzentriert.
+			"metadata":            defaultNode(0.9, 0.15, 0.2),
+			"metadata-structured": defaultNode(0.9, 0.15, 0.2),
+			"c2pa":                defaultNode(0.95, 0.1, 0.15),
+			"exif":                defaultNode(0.85, 0.15, 0.25),
+
+			// Klassifikatoren mit kontinuierlicher Wahrscheinlichkeit -
+			// breitere CPDs, da diese Detektoren selbst schon kalibriert
+			// sind und ihr Score direkt als P(AI) zu lesen ist.
+			"ai-model":           defaultNode(0.75, 0.25, 0.3),
+			"compression":        defaultNode(0.7, 0.3, 0.3),
+			"lighting-analysis":  defaultNode(0.65, 0.35, 0.35),
+			"color-balance":      defaultNode(0.65, 0.35, 0.35),
+			"artifacts":          defaultNode(0.65, 0.35, 0.35),
+			"advanced-artifacts": defaultNode(0.65, 0.35, 0.35),
+			"pixel-analysis":     defaultNode(0.65, 0.35, 0.35),
+			"object-coherence":   defaultNode(0.6, 0.4, 0.35),
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package bayes
+
+import "math"
+
+// Model ist ein flaches Bayes-Netz: ein Wurzelknoten IsAIGenerated mit Prior
+// Prior, und ein Kindknoten (NodeCPD) pro Detektor unter Nodes. Detektoren
+// ohne Eintrag in Nodes werden bei der Inferenz ignoriert (siehe posterior).
+type Model struct {
+	Prior float64
+	Nodes map[string]NodeCPD
+}
+
+// posterior berechnet P(AI | scores) über alle Detektoren, für die sowohl
+// ein Score in scores als auch eine CPD in m.Nodes existiert - fehlende
+// Detektoren werden dadurch automatisch marginalisiert (sie tragen weder
+// zum AI- noch zum Authentic-Zweig etwas bei), statt einen Default-Score
+// erzwingen zu müssen.
+func (m *Model) posterior(scores map[string]float64) (ai float64, authentic float64) {
+	ai = m.Prior
+	authentic = 1 - m.Prior
+
+	for name, score := range scores {
+		cpd, exists := m.Nodes[name]
+		if !exists {
+			continue
+		}
+		ai *= cpd.likelihood(score, true)
+		authentic *= cpd.likelihood(score, false)
+	}
+
+	return ai, authentic
+}
+
+// BayesFusion kapselt ein trainiertes oder eingebautes Model hinter der
+// gewünschten Combine-Schnittstelle - ersetzt in computeVerdict den
+// Log-Odds-Aufruf samt der >= 0.95 Kurzschluss-Zweige für
+// metadata/c2pa, sobald DetectorConfig.UseBayesFusion aktiv ist (siehe
+// internal/handlers/verdict/calculator.go).
+type BayesFusion struct {
+	model *Model
+}
+
+// NewFusion baut ein BayesFusion um model. Ein nil model fällt auf
+// DefaultModel zurück.
+func NewFusion(model *Model) *BayesFusion {
+	if model == nil {
+		model = DefaultModel()
+	}
+	return &BayesFusion{model: model}
+}
+
+// Combine berechnet die Posterior-Wahrscheinlichkeit P(AI | scores) sowie
+// eine Confidence, die angibt, wie weit die Posterior von der
+// Unentschiedenheits-Grenze 0.5 entfernt liegt (0 = genau 0.5, 1 = 0 oder 1).
+// Detektoren ohne hinterlegte CPD werden ignoriert, nicht als neutral (0.5)
+// gewertet - wichtig, damit ein starkes C2PA/metadata-Signal bei wenigen
+// anderen verfügbaren Detektoren nicht verwässert wird.
+func (f *BayesFusion) Combine(scores map[string]float64) (posterior float64, confidence float64) {
+	ai, authentic := f.model.posterior(scores)
+
+	z := ai + authentic
+	if z == 0 {
+		return f.model.Prior, 0
+	}
+
+	posterior = ai / z
+	confidence = math.Abs(posterior-0.5) * 2
+	return posterior, confidence
+}
@@ -0,0 +1,65 @@
+package bayes
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// modelsDir ist das Verzeichnis, unter dem fit-bayes-model (siehe
+// cmd/fit-bayes-model) trainierte Modelle als Gob-Dateien ablegt und von dem
+// LoadModel sie wieder einliest - analog zu calibration.currentDir.
+const modelsDir = "pkg/verdict/bayes/models"
+
+// modelFileName ist die Gob-Datei für das Hauptmodell. Anders als im
+// calibration-Paket gibt es hier bewusst nur ein einziges, gemeinsames
+// Modell statt einer Datei pro Detektor - die Detektoren sind im
+// Bayes-Netz Kindknoten desselben Modells, nicht unabhängig trainierbare
+// Einheiten.
+const modelFileName = "model.gob"
+
+// LoadModel liest das persistierte Modell aus modelsDir/modelFileName. Wenn
+// die Datei fehlt oder nicht geladen werden kann, liefert LoadModel
+// DefaultModel() zurück statt eines Fehlers - ein fehlendes trainiertes
+// Modell ist der Normalfall vor dem ersten fit-bayes-model-Lauf, nicht ein
+// Betriebsfehler (gleiches Prinzip wie calibration.Load).
+func LoadModel() *Model {
+	path := filepath.Join(modelsDir, modelFileName)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return DefaultModel()
+	}
+	defer f.Close()
+
+	var model Model
+	if err := gob.NewDecoder(f).Decode(&model); err != nil {
+		fmt.Printf("WARNING: Bayes-Modell %s beschädigt (%v), verwende Default-Modell\n", path, err)
+		return DefaultModel()
+	}
+
+	return &model
+}
+
+// SaveModel persistiert model als Gob-Datei unter modelsDir/modelFileName,
+// damit LoadModel es bei künftigen Starts findet. Wird von
+// cmd/fit-bayes-model nach einem Trainingslauf aufgerufen.
+func SaveModel(model *Model) error {
+	if err := os.MkdirAll(modelsDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create models dir: %w", err)
+	}
+
+	path := filepath.Join(modelsDir, modelFileName)
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create model file: %w", err)
+	}
+	defer f.Close()
+
+	if err := gob.NewEncoder(f).Encode(model); err != nil {
+		return fmt.Errorf("failed to encode model: %w", err)
+	}
+
+	return nil
+}
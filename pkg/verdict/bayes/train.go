@@ -0,0 +1,67 @@
+package bayes
+
+// LabeledRun ist eine einzelne beschriftete Beobachtung aus einem
+// historischen Analyse-Lauf: die rohen Detektor-Scores (vor Kalibrierung,
+// wie sie verdict.CalculateOverallVerdict sammelt) und ob das Bild
+// tatsächlich AI-generiert war. cmd/fit-bayes-model liest diese zeilenweise
+// aus JSON ein (siehe dort).
+type LabeledRun struct {
+	Scores map[string]float64 `json:"scores"`
+	IsAI   bool               `json:"is_ai"`
+}
+
+// Fit baut ein Model aus runs: Prior ist der beobachtete Anteil AI-
+// generierter Läufe, und jede NodeCPD wird als Laplace-geglättetes
+// Histogramm der pro Hypothese beobachteten Scores geschätzt. Detektoren,
+// die in keinem Run vorkommen, erhalten keinen Eintrag in Nodes und werden
+// bei der Inferenz marginalisiert (siehe Model.posterior). runs ohne
+// Einträge liefert Fit DefaultModel() zurück.
+func Fit(runs []LabeledRun) *Model {
+	if len(runs) == 0 {
+		return DefaultModel()
+	}
+
+	var aiCount int
+	counts := map[string]*NodeCPD{}
+
+	for _, run := range runs {
+		if run.IsAI {
+			aiCount++
+		}
+		for detector, score := range run.Scores {
+			cpd, exists := counts[detector]
+			if !exists {
+				cpd = &NodeCPD{}
+				counts[detector] = cpd
+			}
+			if run.IsAI {
+				cpd.GivenAI[bin(score)]++
+			} else {
+				cpd.GivenAuthentic[bin(score)]++
+			}
+		}
+	}
+
+	nodes := make(map[string]NodeCPD, len(counts))
+	for detector, cpd := range counts {
+		nodes[detector] = NodeCPD{
+			GivenAI:        addLaplaceSmoothing(cpd.GivenAI),
+			GivenAuthentic: addLaplaceSmoothing(cpd.GivenAuthentic),
+		}
+	}
+
+	return &Model{
+		Prior: float64(aiCount) / float64(len(runs)),
+		Nodes: nodes,
+	}
+}
+
+// addLaplaceSmoothing addiert 1 auf jeden Bin, bevor normalize ihn auf 1
+// skaliert - verhindert, dass ein in den Trainingsdaten nie beobachteter
+// Bin auf eine harte 0 statt nur auf laplaceFloor fällt.
+func addLaplaceSmoothing(hist [NumBins]float64) [NumBins]float64 {
+	for i := range hist {
+		hist[i]++
+	}
+	return normalize(hist)
+}
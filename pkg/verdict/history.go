@@ -0,0 +1,74 @@
+// Package verdict hält den über einzelne Analysen hinweg geteilten Zustand,
+// den internal/handlers/verdict für seine Konsistenz-/Übereinstimmungs-
+// Metriken braucht - aktuell ein größenbeschränktes Rolling-Window der
+// zuletzt berechneten (computer_vision, ai_model)-Scorepaare (siehe
+// History). Liegt in einem eigenen Paket statt direkt in
+// internal/handlers/verdict, weil es reiner, von HTTP/Pipeline
+// unabhängiger Zustand ist - ähnlich wie pkg/analyzer/calibration/
+// pkg/verdict/bayes eigene Pakete für ihren jeweiligen Laufzeitzustand
+// sind.
+package verdict
+
+import "sync"
+
+// HistoryWindow ist die Default-Kapazität von DefaultHistory - wie viele
+// zurückliegende Analysen für die rollierende Korrelation in
+// checkConsistency vorgehalten werden, bevor der älteste Eintrag verdrängt
+// wird.
+const HistoryWindow = 200
+
+// Observation ist ein einzelnes, in History vorgehaltenes Scorepaar aus
+// einer vergangenen Analyse - die beiden Eingaben, deren Korrelation
+// checkConsistency über die Zeit beobachten will.
+type Observation struct {
+	ComputerVision float64
+	AIModel        float64
+}
+
+// History ist ein größenbeschränkter Ringpuffer von Observation-Einträgen,
+// sicher für nebenläufigen Zugriff aus mehreren gleichzeitigen Analysen.
+type History struct {
+	mu      sync.Mutex
+	entries []Observation
+	next    int
+	full    bool
+}
+
+// NewHistory baut eine leere History mit Kapazität capacity.
+func NewHistory(capacity int) *History {
+	return &History{entries: make([]Observation, capacity)}
+}
+
+// Add fügt obs hinzu und verdrängt bei voller Kapazität den ältesten
+// Eintrag.
+func (h *History) Add(obs Observation) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries[h.next] = obs
+	h.next = (h.next + 1) % len(h.entries)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Snapshot liefert eine Kopie der aktuell vorgehaltenen Observations. Die
+// Reihenfolge ist nicht garantiert chronologisch - ausreichend für die
+// Pearson-Korrelation in checkConsistency, die über die gesamte Menge,
+// nicht über die zeitliche Abfolge aggregiert.
+func (h *History) Snapshot() []Observation {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	n := h.next
+	if h.full {
+		n = len(h.entries)
+	}
+	out := make([]Observation, n)
+	copy(out, h.entries[:n])
+	return out
+}
+
+// DefaultHistory ist die von internal/handlers/verdict genutzte,
+// prozessweite History-Instanz.
+var DefaultHistory = NewHistory(HistoryWindow)
@@ -0,0 +1,39 @@
+package reliability
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ModelsDir ist das Verzeichnis, unter dem cmd/train-reliability trainierte
+// Modelle als Gob-Dateien ablegt - dasselbe Verzeichnis, das der
+// go:embed-Direktive oben als models/*.gob zugrunde liegt. Ein Neutrainieren
+// wird daher erst nach einem Neubuild dieses Pakets wirksam, analog zu einem
+// geänderten DefaultModel in pkg/verdict/bayes.
+const ModelsDir = "pkg/verdict/reliability/models"
+
+// SaveModels persistiert models als je eine Gob-Datei
+// ModelsDir/<detector>.gob, im selben Format, das die go:embed-Direktive
+// beim nächsten Build wieder einliest.
+func SaveModels(models map[string]Model) error {
+	if err := os.MkdirAll(ModelsDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create models dir: %w", err)
+	}
+
+	for detector, model := range models {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(model); err != nil {
+			return fmt.Errorf("failed to encode model for %s: %w", detector, err)
+		}
+
+		path := filepath.Join(ModelsDir, detector+".gob")
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package reliability embeddet (via go:embed) ein Gob-File pro Detektor mit
+// dessen gemessener Güte aus einem gelabelten Benchmark-Lauf - Precision/
+// Recall/Brier-Score je Score-Bin, sowie optionale Platt-Scaling-
+// Koeffizienten. Anders als pkg/analyzer/calibration und pkg/verdict/bayes,
+// die ihre Dateien zur Laufzeit von der Platte laden, stecken die
+// Reliability-Modelle hier fest in der Binary - sie ändern sich erst mit
+// einem Neubuild nach cmd/train-reliability, nicht bei jedem Prozessstart.
+// ReliabilityAwareWeight ersetzt die handgepflegten Konstanten aus
+// DetectorConfig.DetectorWeights für jeden Detektor, für den ein Modell
+// existiert; Calibrate liefert dieselbe Idee für die Score-Kalibrierung
+// (siehe verdict.applyBalancedCalibration).
+package reliability
+
+import (
+	"bytes"
+	"embed"
+	"encoding/gob"
+	"math"
+	"strings"
+)
+
+//go:embed models/*.gob
+var modelFiles embed.FS
+
+// NumBins ist die Anzahl gleich breiter Bins, in die ein Roh-Score für die
+// Bin-Statistik unten diskretisiert wird - bewusst nicht aus
+// pkg/verdict/bayes importiert, da beide Pakete unabhängig voneinander
+// trainiert und ausgetauscht werden.
+const NumBins = 10
+
+// BinStats sind die für einen Score-Bin aus dem Benchmark-Lauf gemessenen
+// Gütekennzahlen (siehe cmd/train-reliability).
+type BinStats struct {
+	Precision float64
+	Recall    float64
+	Brier     float64
+	Count     int
+}
+
+// Model ist das gemessene Zuverlässigkeits-Profil eines einzelnen
+// Detektors: die Bin-Statistik für Weight sowie optionale
+// Platt-Scaling-Koeffizienten für Calibrate. HasPlatt ist false, solange für
+// diesen Detektor kein Platt-Fit vorliegt - Calibrate liefert dann ok=false
+// statt eines erfundenen Werts.
+type Model struct {
+	Bins     [NumBins]BinStats
+	HasPlatt bool
+	PlattA   float64
+	PlattB   float64
+}
+
+var models = loadEmbedded()
+
+// loadEmbedded dekodiert jede models/*.gob-Datei zu ihrem Detektor-Namen
+// (Dateiname ohne Endung). Eine nicht lesbare oder beschädigte Datei wird
+// übersprungen statt einen Panic beim Programmstart auszulösen - ein
+// fehlendes Modell behandeln Weight/Calibrate wie jeden anderen Detektor
+// ohne Modell.
+func loadEmbedded() map[string]Model {
+	entries, err := modelFiles.ReadDir("models")
+	if err != nil {
+		return map[string]Model{}
+	}
+
+	out := make(map[string]Model, len(entries))
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".gob")
+
+		raw, err := modelFiles.ReadFile("models/" + entry.Name())
+		if err != nil {
+			continue
+		}
+
+		var m Model
+		if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&m); err != nil {
+			continue
+		}
+		out[name] = m
+	}
+	return out
+}
+
+// bin ordnet score seinem Histogramm-Bin zu, score wird dafür auf [0,1]
+// geklemmt - analog zu bayes.bin.
+func bin(score float64) int {
+	if score < 0 {
+		score = 0
+	}
+	if score >= 1 {
+		score = 0.999999
+	}
+	idx := int(score * NumBins)
+	if idx >= NumBins {
+		idx = NumBins - 1
+	}
+	return idx
+}
+
+// HasModel meldet, ob für detector ein eingebettetes Reliability-Modell
+// existiert - genutzt von Aufrufern, die ohne Modell auf ihren eigenen
+// statischen Default zurückfallen wollen (siehe
+// verdict.effectiveDetectorWeight).
+func HasModel(detector string) bool {
+	_, ok := models[detector]
+	return ok
+}
+
+// Weight liefert das an rawScore gemessene Informativitäts-Gewicht von
+// detector: Bins, in denen der Detektor historisch nahe am Münzwurf lag
+// (Precision nahe 0.5) oder schlecht kalibriert war (hoher Brier-Score),
+// werden abgewertet; Bins mit hoher, gut kalibrierter Precision werden
+// verstärkt. Ohne Modell liefert Weight das neutrale Default-Gewicht 1.0 -
+// Aufrufer, die stattdessen einen konfigurierten Fallback wollen, prüfen
+// vorher HasModel.
+func Weight(detector string, rawScore float64) float64 {
+	m, ok := models[detector]
+	if !ok {
+		return 1.0
+	}
+
+	b := m.Bins[bin(rawScore)]
+
+	// confidence ist 0 bei Precision 0.5 (uninformativ) und 1 bei Precision
+	// 0 oder 1 (der Bin sagt eindeutig eine Klasse voraus).
+	confidence := math.Abs(b.Precision-0.5) * 2
+
+	// reliability straft einen schlecht kalibrierten Bin (hoher Brier-Score)
+	// ab, unabhängig von dessen Precision - ein Bin kann "sicher", aber
+	// dennoch schlecht kalibriert sein.
+	reliability := 1 - math.Min(b.Brier*2, 1)
+
+	weight := 0.2 + 1.6*confidence*reliability
+	return weight
+}
+
+// Calibrate bildet rawScore über die Platt-Scaling-Koeffizienten von
+// detector auf eine kalibrierte Wahrscheinlichkeit P(AI | rawScore) ab.
+// ok=false, wenn kein Modell oder kein Platt-Fit für detector existiert -
+// der Aufrufer entscheidet dann selbst über seinen nächsten Fallback (siehe
+// verdict.applyBalancedCalibration).
+func Calibrate(detector string, rawScore float64) (calibrated float64, ok bool) {
+	m, exists := models[detector]
+	if !exists || !m.HasPlatt {
+		return 0, false
+	}
+
+	z := m.PlattA*rawScore + m.PlattB
+	return sigmoid(z), true
+}
+
+func sigmoid(x float64) float64 {
+	return 1.0 / (1.0 + math.Exp(-x))
+}
@@ -0,0 +1,127 @@
+package reliability
+
+import "math"
+
+// LabeledRun ist eine einzelne beschriftete Beobachtung aus einem
+// historischen Analyse-Lauf: die rohen Detektor-Scores (vor Kalibrierung)
+// und ob das Bild tatsächlich AI-generiert war. cmd/train-reliability liest
+// diese zeilenweise aus JSON ein (siehe dort).
+type LabeledRun struct {
+	Scores map[string]float64 `json:"scores"`
+	IsAI   bool               `json:"is_ai"`
+}
+
+// Fit baut für jeden in runs vorkommenden Detektor ein Model: Precision/
+// Recall/Brier je Bin aus den beobachteten (Score, Label)-Paaren, sowie
+// Platt-Scaling-Koeffizienten A/B per Gradientenabstieg auf der
+// Log-Likelihood (siehe fitPlatt). Ein Detektor mit weniger als
+// minPlattSamples Beobachtungen erhält kein Platt-Fit (HasPlatt bleibt
+// false) - zu wenige Punkte liefern sonst ein überangepasstes statt ein
+// brauchbares A/B.
+func Fit(runs []LabeledRun) map[string]Model {
+	type observation struct {
+		score float64
+		isAI  bool
+	}
+	observationsByDetector := map[string][]observation{}
+
+	for _, run := range runs {
+		for detector, score := range run.Scores {
+			observationsByDetector[detector] = append(observationsByDetector[detector], observation{score: score, isAI: run.IsAI})
+		}
+	}
+
+	models := make(map[string]Model, len(observationsByDetector))
+	for detector, observations := range observationsByDetector {
+		var m Model
+
+		var truePos, predPos, actualPos [NumBins]int
+		var count [NumBins]int
+		var brierSum [NumBins]float64
+
+		for _, o := range observations {
+			idx := bin(o.score)
+			count[idx]++
+			predPos[idx]++ // ein Detektor "sagt AI" für jeden Score in diesem Bin
+			if o.isAI {
+				truePos[idx]++
+				actualPos[idx]++
+				brierSum[idx] += (1 - o.score) * (1 - o.score)
+			} else {
+				brierSum[idx] += o.score * o.score
+			}
+		}
+
+		for i := 0; i < NumBins; i++ {
+			if count[i] == 0 {
+				continue
+			}
+			m.Bins[i] = BinStats{
+				Precision: float64(truePos[i]) / float64(predPos[i]),
+				Recall:    safeDiv(float64(truePos[i]), float64(actualPos[i])),
+				Brier:     brierSum[i] / float64(count[i]),
+				Count:     count[i],
+			}
+		}
+
+		if len(observations) >= minPlattSamples {
+			scores := make([]float64, len(observations))
+			labels := make([]float64, len(observations))
+			for i, o := range observations {
+				scores[i] = o.score
+				if o.isAI {
+					labels[i] = 1
+				}
+			}
+			m.PlattA, m.PlattB = fitPlatt(scores, labels)
+			m.HasPlatt = true
+		}
+
+		models[detector] = m
+	}
+
+	return models
+}
+
+// minPlattSamples ist die Mindestanzahl beschrifteter Beobachtungen, ab der
+// Fit ein Platt-Scaling-Fit statt eines unkalibrierten Modells (nur
+// Bin-Statistik) für einen Detektor erzeugt.
+const minPlattSamples = 30
+
+func safeDiv(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+// fitPlatt schätzt A/B in sigmoid(A*score + B) per Gradientenabstieg auf der
+// Log-Likelihood der Labels - dasselbe Verfahren, das
+// calibration.PlattCalibrator zur Laufzeit für einzelne Detektoren fittet,
+// hier aber offline für die embeddeten Reliability-Modelle.
+func fitPlatt(scores, labels []float64) (a, b float64) {
+	const (
+		iterations   = 500
+		learningRate = 0.1
+	)
+
+	a, b = 1.0, 0.0
+	n := float64(len(scores))
+
+	for iter := 0; iter < iterations; iter++ {
+		var gradA, gradB float64
+		for i, score := range scores {
+			p := sigmoid(a*score + b)
+			err := p - labels[i]
+			gradA += err * score
+			gradB += err
+		}
+		a -= learningRate * gradA / n
+		b -= learningRate * gradB / n
+	}
+
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return 1.0, 0.0
+	}
+	return a, b
+}
@@ -0,0 +1,258 @@
+// Package verdictstore persistiert jedes Analyse-Ergebnis für die
+// Longitudinal-Auswertung im Dashboard (/dashboard/history,
+// /api/verdicts/{hash}). Der Store wäre im Idealfall SQLite mit optionalem
+// Postgres-Backend - dieser Baum hat aber weder go.mod noch
+// Dependency-Vendoring, also implementiert dies ein eingebetteter,
+// dateibasierter Store mit derselben Abfrage-Oberfläche: ein JSON-Dokument
+// pro Hash unter dir/<hash>.json, mit einem beim Start aus dem Verzeichnis
+// rekonstruierten In-Memory-Index für Filter-Queries ohne Festplattenzugriff.
+package verdictstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/BramseDev/imageAnalyzer/internal/handlers/verdict"
+)
+
+// Record ist ein einzelner gespeicherter Analyse-Durchlauf.
+type Record struct {
+	Hash             string                 `json:"hash"`
+	Timestamp        time.Time              `json:"timestamp"`
+	RawScores        map[string]float64     `json:"raw_scores"`
+	CalibratedScores map[string]float64     `json:"calibrated_scores"`
+	WeightsUsed      map[string]float64     `json:"weights_used"`
+	Verdict          string                 `json:"verdict"`
+	Confidence       float64                `json:"confidence"`
+	StagesRun        []string               `json:"stages_run"`
+	DurationsNanos   map[string]int64       `json:"durations_ns"`
+	PipelineResult   json.RawMessage        `json:"pipeline_result"`
+}
+
+// Filter grenzt Query auf einen Zeitraum, ein Verdict-Band und/oder einen
+// Analyzer ein, der als "ausgelöst" gilt, wenn sein Rohscore >= 0.7 war.
+type Filter struct {
+	From              time.Time
+	To                time.Time
+	Verdict           string
+	AnalyzerTriggered string
+}
+
+// Store ist ein nebenläufigkeitssicherer, dateibasierter Verdict-Index.
+type Store struct {
+	dir string
+
+	mu     sync.RWMutex
+	byHash map[string]*Record
+	order  []*Record // nach Timestamp aufsteigend, für Range-Queries
+}
+
+var (
+	globalStore     *Store
+	globalStoreOnce sync.Once
+)
+
+// GlobalStore liefert den geteilten, Prozess-weiten Verdict-Store - analog
+// zum globalen Analysis-Cache in pkg/analyzer/pipeline. Der Pfad kann über
+// VERDICT_STORE_DIR überschrieben werden (z.B. für Tests oder mehrere
+// Instanzen hinter demselben Host).
+func GlobalStore() *Store {
+	globalStoreOnce.Do(func() {
+		dir := filepath.Join(os.TempDir(), "analyzer-verdicts")
+		if d := os.Getenv("VERDICT_STORE_DIR"); d != "" {
+			dir = d
+		}
+
+		store, err := NewStore(dir)
+		if err != nil {
+			fmt.Printf("WARNING: verdict store %s konnte nicht geöffnet werden (%v), History bleibt leer\n", dir, err)
+			store = &Store{dir: dir, byHash: make(map[string]*Record)}
+		}
+		globalStore = store
+	})
+	return globalStore
+}
+
+// NewStore öffnet (und erstellt ggf.) dir und rekonstruiert den Index aus
+// den dort bereits abgelegten Records.
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create verdict store dir: %w", err)
+	}
+
+	s := &Store{
+		dir:    dir,
+		byHash: make(map[string]*Record),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read verdict store dir: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			continue
+		}
+
+		s.byHash[record.Hash] = &record
+		s.order = append(s.order, &record)
+	}
+
+	sort.Slice(s.order, func(i, j int) bool {
+		return s.order[i].Timestamp.Before(s.order[j].Timestamp)
+	})
+
+	return s, nil
+}
+
+// HashFile berechnet den SHA-256-Inhalts-Hash einer Datei - dieselbe
+// Content-Adressierung wie der Analysis-Cache (cache.GetFileHash), hier
+// unabhängig dupliziert, um keine Abhängigkeit auf das cache-Paket
+// einzuführen.
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// Save persistiert record atomar (Schreiben auf .tmp + Rename) und
+// aktualisiert den In-Memory-Index.
+func (s *Store) Save(record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal verdict record: %w", err)
+	}
+
+	path := s.path(record.Hash)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write verdict record: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to finalize verdict record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.byHash[record.Hash]; !exists {
+		s.order = append(s.order, record)
+		sort.Slice(s.order, func(i, j int) bool {
+			return s.order[i].Timestamp.Before(s.order[j].Timestamp)
+		})
+	}
+	s.byHash[record.Hash] = record
+
+	return nil
+}
+
+// Get liefert den gespeicherten Record für hash, falls vorhanden.
+func (s *Store) Get(hash string) (*Record, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	record, exists := s.byHash[hash]
+	return record, exists
+}
+
+// Query filtert die gespeicherten Records nach Zeitraum, Verdict-Band und
+// ausgelöstem Analyzer. Ein Nullwert in Filter deaktiviert das jeweilige
+// Kriterium.
+func (s *Store) Query(f Filter) []*Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := make([]*Record, 0, len(s.order))
+	for _, record := range s.order {
+		if !f.From.IsZero() && record.Timestamp.Before(f.From) {
+			continue
+		}
+		if !f.To.IsZero() && record.Timestamp.After(f.To) {
+			continue
+		}
+		if f.Verdict != "" && record.Verdict != f.Verdict {
+			continue
+		}
+		if f.AnalyzerTriggered != "" {
+			score, exists := record.RawScores[f.AnalyzerTriggered]
+			if !exists || score < 0.7 {
+				continue
+			}
+		}
+		matches = append(matches, record)
+	}
+
+	return matches
+}
+
+// All liefert alle gespeicherten Records, neueste zuletzt - Grundlage für
+// den Rescore-Hintergrundjob, der nach einer Kalibrierungsänderung jeden
+// Record gegen die Rohscores neu bewertet.
+func (s *Store) All() []*Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	all := make([]*Record, len(s.order))
+	copy(all, s.order)
+	return all
+}
+
+func (s *Store) path(hash string) string {
+	return filepath.Join(s.dir, hash+".json")
+}
+
+// RescoreAll spielt die gespeicherten Rohscores jedes Records durch das
+// aktuell aktive Kalibrierungsprofil (verdict.RescoreFromRawScores), ohne
+// die teuren Analyzer erneut auszuführen, und persistiert aktualisierte
+// CalibratedScores/Verdict/Confidence. Gibt die Anzahl neu bewerteter
+// Records zurück. Records, deren Rohscores keinen Analyzer mit Gewicht > 0
+// enthalten, werden übersprungen statt den Job abzubrechen.
+func (s *Store) RescoreAll() (int, error) {
+	rescored := 0
+	for _, record := range s.All() {
+		result, err := verdict.RescoreFromRawScores(record.RawScores, record.WeightsUsed)
+		if err != nil {
+			continue
+		}
+
+		updated := *record
+		updated.Verdict, _ = result["verdict"].(string)
+		updated.Confidence, _ = result["confidence"].(float64)
+		if calibrated, ok := result["calibrated_scores"].(map[string]float64); ok {
+			updated.CalibratedScores = calibrated
+		}
+
+		if err := s.Save(&updated); err != nil {
+			return rescored, err
+		}
+		rescored++
+	}
+
+	return rescored, nil
+}